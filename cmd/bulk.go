@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"order-service/internal/application/bulk"
+	"order-service/internal/domain/services"
+	"order-service/internal/infrastructure/projections"
+	"os"
+	"path/filepath"
+)
+
+const (
+	importUsage = "usage: import <file|-> [csv|jsonl]"
+	exportUsage = "usage: export <csv|jsonl> [file]"
+)
+
+// runImport executes the import subcommand, printing the import report as JSON
+func runImport(args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return errors.New(importUsage)
+	}
+
+	formatName := filepath.Ext(args[0])
+	if len(args) == 2 {
+		formatName = args[1]
+	}
+	format, err := bulk.ParseFormat(formatName)
+	if err != nil {
+		return fmt.Errorf("%w\n%s", err, importUsage)
+	}
+
+	var input io.Reader = os.Stdin
+	if args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	service, err := newBulkOrderService()
+	if err != nil {
+		return err
+	}
+
+	report, err := service.Import(context.Background(), bulk.NewReader(format, input).Next)
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if encodeErr := encoder.Encode(report); encodeErr != nil {
+		return encodeErr
+	}
+	if err == nil && report.Failed > 0 {
+		err = fmt.Errorf("%d order(s) were rejected", report.Failed)
+	}
+	if err == nil && len(report.Unpublished) > 0 {
+		err = fmt.Errorf("%d imported order(s) were not published", len(report.Unpublished))
+	}
+	return err
+}
+
+// runExport executes the export subcommand, writing to stdout unless a file is given
+func runExport(args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return errors.New(exportUsage)
+	}
+	format, err := bulk.ParseFormat(args[0])
+	if err != nil {
+		return fmt.Errorf("%w\n%s", err, exportUsage)
+	}
+
+	var output io.Writer = os.Stdout
+	if len(args) == 2 {
+		file, err := os.Create(args[1])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		output = file
+	}
+
+	service, err := newBulkOrderService()
+	if err != nil {
+		return err
+	}
+
+	writer := bulk.NewWriter(format, output)
+	if err := service.Export(context.Background(), writer.Write); err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+// newBulkOrderService connects to the configured order store; imported orders are projected
+// into the read model like orders created through the API
+func newBulkOrderService() (*services.BulkOrderService, error) {
+	db, err := openDatabase()
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to the database: %w", err)
+	}
+	_, bulkRepo, _, err := newOrderRepositories(db)
+	if err != nil {
+		return nil, err
+	}
+
+	eventPublisher := projections.NewProjectingEventPublisher(&services.LoggerEventPublisher{}, projections.NewOrderProjector(db))
+	return services.NewBulkOrderService(bulkRepo, eventPublisher, envInt("BULK_BATCH_SIZE", 500)), nil
+}
@@ -2,35 +2,49 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
-	"order-service/internal/domain/models"
+	"order-service/sql/migrations"
 
+	"order-service/internal/application/auth"
+	"order-service/internal/application/command"
 	"order-service/internal/application/handlers"
+	v1 "order-service/internal/application/handlers/v1"
+	v2 "order-service/internal/application/handlers/v2"
+	"order-service/internal/application/jobs"
+	"order-service/internal/application/policy"
+	"order-service/internal/application/query"
+	"order-service/internal/application/repository"
+	"order-service/internal/domain/repositories"
 	"order-service/internal/domain/services"
 	"order-service/internal/infrastructure/awsservice"
+	"order-service/internal/infrastructure/cache"
+	"order-service/internal/infrastructure/dynamo"
+	"order-service/internal/infrastructure/jwtauth"
 	"order-service/internal/infrastructure/logging"
 	"order-service/internal/infrastructure/persistence"
+	"order-service/internal/infrastructure/projections"
+	"order-service/internal/infrastructure/ratelimit"
 	"order-service/internal/infrastructure/tracing"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/contrib/swagger"
 	"github.com/gofiber/fiber/v2"
-	"gorm.io/driver/postgres"
+	"github.com/gofiber/fiber/v2/middleware/expvar"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
-// @title Order Service API
-// @version 1.0
-// @description This is an API for managing orders
-// @termsOfService http://swagger.io/terms/
-// @contact.name API Support
-// @contact.url http://www.swagger.io/support
-// @contact.email support@swagger.io
-// @license.name Apache 2.0
-// @license.url http://www.apache.org/licenses/LICENSE-2.0.html
-// @host localhost:8080
+// unversionedPrefixes are the v1 routes that are still served at the root, as they were before versioning
+var unversionedPrefixes = []string{"/orders", "/customers", "/retention"}
+
+// publicPrefixes are served without authentication
+var publicPrefixes = []string{"/swagger"}
 
 type DBConfig struct {
 	User     string `json:"DB_USER"`
@@ -38,14 +52,35 @@ type DBConfig struct {
 	Name     string `json:"DB_NAME"`
 	Host     string `json:"DB_HOST"`
 	Port     string `json:"DB_PORT"`
+	// ReplicaHosts is an optional comma-separated list of host[:port] read replicas
+	ReplicaHosts string `json:"DB_REPLICA_HOSTS"`
 }
 
 func main() {
 	// Initialize logging
 	logging.InitLogger()
 
+	// Run a CLI subcommand instead of the server when one is given
+	commands := map[string]func(args []string) error{
+		"migrate":     runMigrate,
+		"retention":   runRetention,
+		"projections": runProjections,
+		"partitions":  runPartitions,
+		"import":      runImport,
+		"export":      runExport,
+	}
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			if err := command(os.Args[2:]); err != nil {
+				logging.Logger.Error().Msgf("%s: %v", os.Args[1], err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	// Initialize tracing
-	if err := tracing.InitTracer(); err != nil {
+	if err := tracing.InitTracer(context.Background(), newTracingConfig()); err != nil {
 		logging.Logger.Error().Msgf("failed to initialize tracer: %v", err)
 		return
 	}
@@ -55,60 +90,192 @@ func main() {
 		}
 	}()
 
-	// Retrieve database configuration from AWS Secrets Manager
-	secretsManager := &awsservice.AwsSecretsManager{}
-	secretValue, err := secretsManager.GetSecretValue(context.Background(), os.Getenv("AWS_SECRET_NAME"))
+	// Connect to the database
+	db, err := openDatabase()
 	if err != nil {
-		logging.Logger.Error().Msgf("failed to retrieve secret value: %v", err)
+		logging.Logger.Error().Msgf("could not connect to the database: %v", err)
+		os.Exit(1)
 	}
 
-	var dbConfig DBConfig
-	if err := json.Unmarshal([]byte(secretValue), &dbConfig); err != nil {
-		logging.Logger.Error().Msgf("failed to unmarshal secret string: %v", err)
+	logging.Logger.Info().Msg("Connected to the database successfully")
+
+	// Refuse to serve against a schema that is behind the embedded migrations
+	migrator, err := persistence.NewMigrator(db, migrations.FS)
+	if err != nil {
+		logging.Logger.Error().Msgf("failed to load migrations: %v", err)
+		return
+	}
+	if err := migrator.EnsureCurrent(); err != nil {
+		logging.Logger.Error().Msgf("database schema is not up to date, run `migrate up` first: %v", err)
+		return
+	}
+
+	// Set up repositories
+	orderRepo, bulkRepo, searchRepo, err := newOrderRepositories(db)
+	if err != nil {
+		logging.Logger.Error().Msgf("failed to set up the order repository: %v", err)
+		return
 	}
 
-	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable", dbConfig.User, dbConfig.Password, dbConfig.Host, dbConfig.Port, dbConfig.Name)
+	// Set up event publisher, keeping the query-side read model in step with every event
+	var eventPublisher services.EventPublisher = &services.LoggerEventPublisher{}
+	eventPublisher = projections.NewProjectingEventPublisher(eventPublisher, projections.NewOrderProjector(db))
 
-	// Connect to the database
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
-	})
+	// Cache order lookups, invalidated on saves and published order events
+	if store := newCacheStore(); store != nil {
+		cachedRepo := cache.NewOrderRepository(orderRepo, store, envDuration("CACHE_TTL", 5*time.Minute))
+		orderRepo = cachedRepo
+		eventPublisher = cache.NewInvalidatingEventPublisher(eventPublisher, cachedRepo)
+	}
+	// Trace every publish together with the projection and cache invalidation it triggers
+	eventPublisher = tracing.NewTracingEventPublisher(eventPublisher)
+
+	// Set up services
+	domainOrderService := services.NewOrderService(orderRepo, searchRepo, eventPublisher)
+
+	// Authorize every order operation with the RBAC policy of POLICY_FILE, or the built-in policy
+	accessPolicy, err := loadPolicy()
 	if err != nil {
-		logging.Logger.Error().Msgf("could not connect to the database: %v", err)
+		logging.Logger.Error().Msgf("invalid authorization policy: %v", err)
+		return
 	}
+	authorizer := policy.NewAuthorizer(accessPolicy, policy.LogAuditor{})
 
-	logging.Logger.Info().Msgf("Connected to the database successfully: %v", db)
+	// Dispatch order use cases as commands through the bus
+	commandBus := command.NewBus(
+		command.TracingMiddleware(),
+		command.LoggingMiddleware(),
+		command.AuthorizationMiddleware(command.NewPolicyAuthorizer(authorizer, orderRepo)),
+		command.ValidationMiddleware(),
+		command.TransactionMiddleware(persistence.NewGormTransactionManager(db)),
+	)
+	command.RegisterOrderHandlers(commandBus, domainOrderService)
+	orderService := command.NewOrderService(commandBus, domainOrderService, authorizer)
 
-	// Apply migrations
-	err = db.AutoMigrate(&models.Order{}, &models.OrderItem{})
+	// Set up Fiber and API handlers
+	// Stream request bodies so bulk imports are processed as they upload, the body limit middleware
+	// reads every other body within BODY_LIMIT
+	app := fiber.New(fiber.Config{StreamRequestBody: true, ErrorHandler: handlers.ErrorHandler})
+	// Trace every request first, continuing the caller's trace, then identify it so all of its
+	// log lines, spans and events carry the same ID
+	app.Use(handlers.NewTracingMiddleware())
+	app.Use(handlers.NewRequestIDMiddleware())
+	app.Use(handlers.NewBodyLimitMiddleware(envInt("BODY_LIMIT", fiber.DefaultBodyLimit), handlers.IsBulkImport))
+
+	// Limit request rates per client, IP and route when RATE_LIMIT_RULES names a rules file. Rules
+	// that only need the request run before authentication, so they also shield it from floods.
+	rateLimitRules, err := loadRateLimitRules()
 	if err != nil {
-		logging.Logger.Error().Msgf("failed to migrate database: %v", err)
+		logging.Logger.Error().Msgf("invalid rate limit rules: %v", err)
+		return
+	}
+	rateLimitStore := newRateLimitStore()
+	ipRules, clientRules := ratelimit.SplitRules(rateLimitRules)
+	if len(ipRules) > 0 {
+		app.Use(handlers.NewRateLimitMiddleware(rateLimitStore, ipRules))
 	}
 
-	logging.Logger.Info().Msg("Database migration completed successfully.")
+	// Authenticate every API request with its bearer token, or the API key of a service-to-service caller
+	apiKeys := auth.NewAPIKeys(persistence.NewGormAPIKeyStore(db))
+	authMiddleware, err := newAuthMiddleware(apiKeys)
+	if err != nil {
+		logging.Logger.Error().Msgf("invalid authentication settings: %v", err)
+		return
+	}
+	app.Use(authMiddleware)
 
-	// Set up repositories
-	orderRepo := persistence.NewGormOrderRepository(db)
+	// Serve the expvar counters on /debug/vars to admins only, they include memstats and the command line
+	app.Use("/debug/vars", handlers.Authorize(authorizer, policy.ReadMetrics), expvar.New())
 
-	// Set up event publisher
-	eventPublisher := &services.LoggerEventPublisher{}
+	// Pin reads to the primary for a while after a write, with tokens signed by DB_READ_YOUR_WRITES_SECRET
+	app.Use(handlers.NewReadYourWritesMiddleware(handlers.ReadYourWrites{
+		Window:     envDuration("DB_READ_YOUR_WRITES_WINDOW", 5*time.Second),
+		Secret:     readYourWritesSecret(),
+		Authorizer: authorizer,
+	}))
 
-	// Set up services
-	orderService := services.NewOrderService(orderRepo, eventPublisher)
+	// Limit request rates of the authenticated clients
+	if len(clientRules) > 0 {
+		app.Use(handlers.NewRateLimitMiddleware(rateLimitStore, clientRules))
+	}
 
-	// Set up Fiber and API handlers
-	app := fiber.New()
-	handlers.NewOrderHandler(app, orderService)
-
-	var swag = swagger.New(swagger.Config{
-		BasePath: "/",
-		FilePath: "./docs/swagger.json",
-		Path:     "swagger",
-		Title:    "Swagger API Docs",
-	})
+	// Replay responses for retried order POST and PATCH requests carrying an Idempotency-Key
+	var idempotencyStore repository.IdempotencyStore = persistence.NewGormIdempotencyStore(db)
+	if os.Getenv("IDEMPOTENCY_BACKEND") == "memory" {
+		idempotencyStore = persistence.NewInMemoryIdempotencyStore()
+	}
+	idempotency := handlers.NewIdempotencyMiddleware(idempotencyStore, envDuration("IDEMPOTENCY_TTL", 24*time.Hour))
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	jobs.NewIdempotencyCleanupJob(idempotencyStore, time.Hour).Start(jobsCtx)
+
+	// Create monthly order partitions ahead of time and archive old ones when configured
+	if err := requirePostgresOrders("partition maintenance"); err != nil {
+		logging.Logger.Info().Msgf("partition maintenance is disabled: %v", err)
+	} else {
+		partitionJob := jobs.NewPartitionMaintenanceJob(newPartitionStore(db), envDuration("PARTITION_MAINTENANCE_INTERVAL", 24*time.Hour),
+			envInt("PARTITION_MONTHS_AHEAD", 3), envInt("PARTITION_ARCHIVE_AFTER_MONTHS", 0))
+		partitionJob.Start(jobsCtx)
+	}
+
+	// v1 is deprecated: its routes, also served at the root for older clients, announce the v1 sunset
+	deprecation, err := v1Deprecation()
+	if err != nil {
+		logging.Logger.Error().Msgf("invalid API deprecation settings: %v", err)
+		return
+	}
+	unversioned := deprecation
+	unversioned.Next = func(c *fiber.Ctx) bool { return !hasAnyPrefix(c.Path(), unversionedPrefixes) }
+	app.Use(handlers.NewDeprecationMiddleware(unversioned))
+	v1Routers := []fiber.Router{app, app.Group("/v1", handlers.NewDeprecationMiddleware(deprecation))}
+	v2Router := app.Group("/v2")
+	routers := append(v1Routers, v2Router)
 
-	// Serve Swagger UI
-	app.Use(swag)
+	// Both versions share the services; bulk routes go first so /orders/export is not matched as /orders/:id
+	bulkService := services.NewBulkOrderService(bulkRepo, eventPublisher, envInt("BULK_BATCH_SIZE", 500))
+	orderQueries := query.NewOrderQueries(projections.NewGormReadModel(db), authorizer)
+	for _, router := range routers {
+		// Only order commands are idempotent; other responses, such as issued API keys, must not be stored
+		router.Use("/orders", idempotency)
+		handlers.NewBulkHandler(router, bulkService, authorizer)
+		handlers.NewAPIKeyHandler(router, apiKeys, authorizer)
+	}
+	for _, router := range v1Routers {
+		v1.NewOrderHandler(router, orderService, orderQueries)
+	}
+	v2.NewOrderHandler(v2Router, orderService, orderQueries)
+
+	// Purge or anonymize orders past the retention period when a policy is configured
+	if policy, ok, err := retentionPolicy(); err != nil {
+		logging.Logger.Error().Msgf("invalid retention policy: %v", err)
+		return
+	} else if ok {
+		if err := requirePostgresOrders("retention"); err != nil {
+			logging.Logger.Error().Msgf("%v, unset RETENTION_DAYS to start without retention", err)
+			return
+		}
+		retentionService, err := services.NewRetentionService(persistence.NewGormOrderRetentionRepository(db), persistence.NewGormOrderRepository(db), eventPublisher, policy)
+		if err != nil {
+			logging.Logger.Error().Msgf("invalid retention policy: %v", err)
+			return
+		}
+		for _, router := range routers {
+			handlers.NewRetentionHandler(router, retentionService, authorizer)
+		}
+
+		retentionJob := jobs.NewRetentionJob(retentionService, envDuration("RETENTION_INTERVAL", time.Hour), os.Getenv("RETENTION_DRY_RUN") == "true")
+		retentionJob.Start(jobsCtx)
+	}
+
+	// Serve the Swagger UI of each version
+	for _, version := range []string{"v1", "v2"} {
+		app.Use(swagger.New(swagger.Config{
+			BasePath: "/",
+			FilePath: "./docs/" + version + "_swagger.json",
+			Path:     "swagger/" + version,
+			Title:    "Swagger API Docs " + version,
+		}))
+	}
 
 	// Start the server
 	logging.Logger.Info().Msg("Starting server on port 8080")
@@ -116,3 +283,294 @@ func main() {
 		logging.Logger.Error().Msgf("failed to start server: %v", err)
 	}
 }
+
+// loadDBConfig retrieves the database configuration from AWS Secrets Manager
+func loadDBConfig() (DBConfig, error) {
+	var dbConfig DBConfig
+
+	secretsManager := &awsservice.AwsSecretsManager{}
+	secretValue, err := secretsManager.GetSecretValue(context.Background(), os.Getenv("AWS_SECRET_NAME"))
+	if err != nil {
+		return dbConfig, fmt.Errorf("failed to retrieve secret value: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(secretValue), &dbConfig); err != nil {
+		return dbConfig, fmt.Errorf("failed to unmarshal secret string: %w", err)
+	}
+	return dbConfig, nil
+}
+
+// openDatabase connects to the database described by the AWS secret with the pool sized from the environment
+func openDatabase() (*gorm.DB, error) {
+	dbConfig, err := loadDBConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	config := persistence.DatabaseConfig{
+		PrimaryDSN: dbConfig.dsn(dbConfig.Host, dbConfig.Port),
+		Logger:     logger.Default.LogMode(logger.Info),
+		Pool: persistence.PoolConfig{
+			MaxOpenConns:    envInt("DB_MAX_OPEN_CONNS", persistence.DefaultPoolConfig.MaxOpenConns),
+			MaxIdleConns:    envInt("DB_MAX_IDLE_CONNS", persistence.DefaultPoolConfig.MaxIdleConns),
+			ConnMaxLifetime: envDuration("DB_CONN_MAX_LIFETIME", persistence.DefaultPoolConfig.ConnMaxLifetime),
+			ConnMaxIdleTime: envDuration("DB_CONN_MAX_IDLE_TIME", persistence.DefaultPoolConfig.ConnMaxIdleTime),
+		},
+	}
+	for _, replica := range strings.Split(dbConfig.ReplicaHosts, ",") {
+		replica = strings.TrimSpace(replica)
+		if replica == "" {
+			continue
+		}
+		host, port, found := strings.Cut(replica, ":")
+		if !found {
+			port = dbConfig.Port
+		}
+		config.ReplicaDSNs = append(config.ReplicaDSNs, dbConfig.dsn(host, port))
+	}
+
+	// Postgres may still be starting, keep retrying until DB_CONNECT_TIMEOUT and then give up
+	return persistence.ConnectWithRetry(context.Background(), config, persistence.RetryConfig{
+		Timeout:        envDuration("DB_CONNECT_TIMEOUT", time.Minute),
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+	})
+}
+
+// dsn builds a connection string for the given host using the shared credentials
+func (c DBConfig) dsn(host, port string) string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable", c.User, c.Password, host, port, c.Name)
+}
+
+// newOrderRepositories selects where orders are written from ORDER_STORE (postgres or dynamodb).
+// The read model and the other stores stay in Postgres either way. Search needs the orders table,
+// so it answers 501 Not Implemented when orders are stored in DynamoDB.
+func newOrderRepositories(db *gorm.DB) (repositories.OrderRepository, repositories.OrderBulkRepository, repositories.OrderSearchRepository, error) {
+	switch os.Getenv("ORDER_STORE") {
+	case "", "postgres":
+		return persistence.NewGormOrderRepository(db), persistence.NewGormOrderBulkRepository(db), persistence.NewGormOrderSearchRepository(db), nil
+	case "dynamodb":
+		region := os.Getenv("AWS_REGION")
+		if region == "" {
+			region = "us-east-2"
+		}
+		table := os.Getenv("DYNAMODB_TABLE")
+		if table == "" {
+			table = "orders"
+		}
+
+		ctx := context.Background()
+		client, err := dynamo.NewClient(ctx, region, os.Getenv("DYNAMODB_ENDPOINT"))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		repo := dynamo.NewOrderRepository(client, table)
+		if os.Getenv("DYNAMODB_CREATE_TABLE") == "true" {
+			if err := repo.EnsureTable(ctx); err != nil {
+				return nil, nil, nil, fmt.Errorf("failed to create table %s: %w", table, err)
+			}
+		}
+		return repo, repo, dynamo.NewOrderSearchRepository(), nil
+	default:
+		return nil, nil, nil, fmt.Errorf("unknown ORDER_STORE %q", os.Getenv("ORDER_STORE"))
+	}
+}
+
+// requirePostgresOrders refuses a feature that reads or changes the Postgres orders table
+// when ORDER_STORE keeps orders somewhere else
+func requirePostgresOrders(feature string) error {
+	if store := os.Getenv("ORDER_STORE"); store != "" && store != "postgres" {
+		return fmt.Errorf("%s needs ORDER_STORE=postgres, orders are stored in %s", feature, store)
+	}
+	return nil
+}
+
+// newCacheStore selects the order cache backend from CACHE_BACKEND (none, memory or redis)
+func newCacheStore() cache.Store {
+	switch os.Getenv("CACHE_BACKEND") {
+	case "memory":
+		size, err := strconv.Atoi(os.Getenv("CACHE_SIZE"))
+		if err != nil || size <= 0 {
+			size = 10000
+		}
+		return cache.NewLRUStore(size)
+	case "redis":
+		client := redis.NewClient(&redis.Options{Addr: os.Getenv("REDIS_ADDR")})
+		return cache.NewRedisStore(client, "order-service:")
+	default:
+		return nil
+	}
+}
+
+// newAuthMiddleware verifies bearer tokens with the keys of AUTH_JWKS_FILE, the PEM public key of
+// AUTH_JWT_PUBLIC_KEY_FILE or the HMAC secret AUTH_JWT_SECRET, checking AUTH_ISSUER and AUTH_AUDIENCE
+// when set, and API keys with apiKeys. AUTH_DISABLED=true instead treats every request as an admin,
+// for local development only.
+func newAuthMiddleware(apiKeys *auth.APIKeys) (fiber.Handler, error) {
+	if os.Getenv("AUTH_DISABLED") == "true" {
+		logging.Logger.Warn().Msg("authentication is disabled, every request is treated as an admin")
+		return handlers.NewFixedPrincipalMiddleware(auth.Principal{Subject: "anonymous", Roles: []string{auth.RoleAdmin}}), nil
+	}
+
+	var keys jwtauth.KeySet
+	switch {
+	case os.Getenv("AUTH_JWKS_FILE") != "":
+		data, err := os.ReadFile(os.Getenv("AUTH_JWKS_FILE"))
+		if err != nil {
+			return nil, err
+		}
+		if keys, err = jwtauth.ParseJWKS(data); err != nil {
+			return nil, err
+		}
+	case os.Getenv("AUTH_JWT_PUBLIC_KEY_FILE") != "":
+		data, err := os.ReadFile(os.Getenv("AUTH_JWT_PUBLIC_KEY_FILE"))
+		if err != nil {
+			return nil, err
+		}
+		key, err := jwtauth.ParsePublicKeyPEM(data)
+		if err != nil {
+			return nil, err
+		}
+		keys = jwtauth.KeySet{"": key}
+	case os.Getenv("AUTH_JWT_SECRET") != "":
+		keys = jwtauth.KeySet{"": []byte(os.Getenv("AUTH_JWT_SECRET"))}
+	default:
+		return nil, fmt.Errorf("set AUTH_JWKS_FILE, AUTH_JWT_PUBLIC_KEY_FILE or AUTH_JWT_SECRET, or AUTH_DISABLED=true")
+	}
+
+	verifier := jwtauth.NewVerifier(keys, jwtauth.Config{
+		Issuer:     os.Getenv("AUTH_ISSUER"),
+		Audience:   os.Getenv("AUTH_AUDIENCE"),
+		RolesClaim: os.Getenv("AUTH_ROLES_CLAIM"),
+		Leeway:     envDuration("AUTH_LEEWAY", 30*time.Second),
+	})
+	return handlers.NewAuthMiddleware(handlers.Authentication{
+		Verifier: verifier,
+		APIKeys:  apiKeys,
+		Next:     func(c *fiber.Ctx) bool { return hasAnyPrefix(c.Path(), publicPrefixes) },
+	}), nil
+}
+
+// readYourWritesSecret returns DB_READ_YOUR_WRITES_SECRET, or a random secret when it is not set,
+// in which case each instance only honours the read-your-writes tokens it issued itself
+func readYourWritesSecret() []byte {
+	if secret := os.Getenv("DB_READ_YOUR_WRITES_SECRET"); secret != "" {
+		return []byte(secret)
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(fmt.Sprintf("failed to generate a read-your-writes secret: %v", err))
+	}
+	logging.Logger.Warn().Msg("DB_READ_YOUR_WRITES_SECRET is not set, read-your-writes tokens are only honoured by the instance that issued them")
+	return secret
+}
+
+// v1Deprecation reads when v1 was deprecated from API_V1_DEPRECATED_AT and when it is retired from
+// API_V1_SUNSET, both RFC 3339 timestamps such as "2027-04-19T00:00:00Z". The sunset defaults to
+// six months after the deprecation.
+func v1Deprecation() (handlers.Deprecation, error) {
+	since, err := time.Parse(time.RFC3339, os.Getenv("API_V1_DEPRECATED_AT"))
+	if err != nil {
+		return handlers.Deprecation{}, fmt.Errorf("API_V1_DEPRECATED_AT must be an RFC 3339 timestamp: %w", err)
+	}
+	deprecation := handlers.Deprecation{Since: since, Sunset: since.AddDate(0, 6, 0), Successor: "/v2"}
+	if sunset := os.Getenv("API_V1_SUNSET"); sunset != "" {
+		if deprecation.Sunset, err = time.Parse(time.RFC3339, sunset); err != nil {
+			return handlers.Deprecation{}, fmt.Errorf("API_V1_SUNSET must be an RFC 3339 timestamp: %w", err)
+		}
+	}
+	return deprecation, nil
+}
+
+// newTracingConfig reads the trace exporter from TRACING_EXPORTER (none, stdout, otlp-grpc or otlp-http)
+// and TRACING_ENDPOINT, keeping TRACING_SAMPLE_RATIO of new traces. SERVICE_VERSION,
+// DEPLOYMENT_ENVIRONMENT and SERVICE_INSTANCE_ID, or else the host name, describe this instance.
+func newTracingConfig() tracing.Config {
+	instanceID := os.Getenv("SERVICE_INSTANCE_ID")
+	if instanceID == "" {
+		instanceID, _ = os.Hostname()
+	}
+	return tracing.Config{
+		Exporter:    os.Getenv("TRACING_EXPORTER"),
+		Endpoint:    os.Getenv("TRACING_ENDPOINT"),
+		Insecure:    os.Getenv("TRACING_INSECURE") == "true",
+		SampleRatio: envRatio("TRACING_SAMPLE_RATIO", 1),
+		Version:     os.Getenv("SERVICE_VERSION"),
+		Environment: os.Getenv("DEPLOYMENT_ENVIRONMENT"),
+		InstanceID:  instanceID,
+	}
+}
+
+// loadPolicy reads the roles and scopes from the JSON file named by POLICY_FILE, or returns the
+// built-in policy when it is not set
+func loadPolicy() (*policy.Policy, error) {
+	path := os.Getenv("POLICY_FILE")
+	if path == "" {
+		return policy.Default(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return policy.Parse(data)
+}
+
+// loadRateLimitRules reads the rate limit rules from the JSON file named by RATE_LIMIT_RULES, if any
+func loadRateLimitRules() ([]ratelimit.Rule, error) {
+	path := os.Getenv("RATE_LIMIT_RULES")
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ratelimit.ParseRules(data)
+}
+
+// newRateLimitStore selects where request counts are kept from RATE_LIMIT_BACKEND (memory or redis).
+// Use redis when several instances serve the API so they share one count.
+func newRateLimitStore() ratelimit.Store {
+	if os.Getenv("RATE_LIMIT_BACKEND") == "redis" {
+		client := redis.NewClient(&redis.Options{Addr: os.Getenv("REDIS_ADDR")})
+		return ratelimit.NewRedisStore(client, "order-service:ratelimit:")
+	}
+	return ratelimit.NewMemoryStore()
+}
+
+// envDuration reads a duration such as "5s" from the environment, falling back to def
+func envDuration(name string, def time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(name))
+	if err != nil || value <= 0 {
+		return def
+	}
+	return value
+}
+
+// envRatio reads a ratio from 0 to 1 from the environment, falling back to def
+func envRatio(name string, def float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(name), 64)
+	if err != nil || value < 0 || value > 1 {
+		return def
+	}
+	return value
+}
+
+// hasAnyPrefix reports whether path starts with one of prefixes
+func hasAnyPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// envInt reads a positive integer from the environment, falling back to def
+func envInt(name string, def int) int {
+	value, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || value <= 0 {
+		return def
+	}
+	return value
+}
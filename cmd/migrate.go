@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"order-service/internal/infrastructure/persistence"
+	"order-service/sql/migrations"
+	"strconv"
+)
+
+const migrateUsage = "usage: migrate status | up | down [steps] | force <version>"
+
+// runMigrate executes the migrate subcommand against the configured database
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	db, err := openDatabase()
+	if err != nil {
+		return fmt.Errorf("could not connect to the database: %w", err)
+	}
+
+	migrator, err := persistence.NewMigrator(db, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "status":
+		status, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("version: %d\ndirty: %t\nlatest: %d\n", status.Version, status.Dirty, status.Latest)
+		for _, migration := range status.Pending {
+			fmt.Printf("pending: %d_%s\n", migration.Version, migration.Name)
+		}
+		return nil
+
+	case "up":
+		applied, err := migrator.Up()
+		fmt.Printf("applied %d migration(s)\n", applied)
+		return err
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		rolledBack, err := migrator.Down(steps)
+		fmt.Printf("rolled back %d migration(s)\n", rolledBack)
+		return err
+
+	case "force":
+		if len(args) < 2 {
+			return errors.New(migrateUsage)
+		}
+		version, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		if err := migrator.Force(version); err != nil {
+			return err
+		}
+		fmt.Printf("forced version %d\n", version)
+		return nil
+
+	default:
+		return errors.New(migrateUsage)
+	}
+}
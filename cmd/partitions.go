@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"order-service/internal/application/repository"
+	"order-service/internal/infrastructure/persistence"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const partitionsUsage = "usage: partitions ensure | archive"
+
+// runPartitions executes the partitions subcommand
+func runPartitions(args []string) error {
+	if len(args) != 1 || (args[0] != "ensure" && args[0] != "archive") {
+		return errors.New(partitionsUsage)
+	}
+	if err := requirePostgresOrders("partition maintenance"); err != nil {
+		return err
+	}
+
+	db, err := openDatabase()
+	if err != nil {
+		return fmt.Errorf("could not connect to the database: %w", err)
+	}
+	store := newPartitionStore(db)
+	now := time.Now()
+
+	if args[0] == "ensure" {
+		created, err := store.EnsurePartitions(context.Background(), now, envInt("PARTITION_MONTHS_AHEAD", 3)+1)
+		for _, month := range created {
+			fmt.Printf("created partitions for %s\n", month.Format("2006-01"))
+		}
+		return err
+	}
+
+	archiveAfter := envInt("PARTITION_ARCHIVE_AFTER_MONTHS", 0)
+	if archiveAfter == 0 {
+		return errors.New("no archive period configured, set PARTITION_ARCHIVE_AFTER_MONTHS")
+	}
+	archived, err := store.ArchivePartitions(context.Background(), now.AddDate(0, -archiveAfter, 0))
+	for _, month := range archived {
+		fmt.Printf("archived partitions for %s\n", month.Format("2006-01"))
+	}
+	return err
+}
+
+// newPartitionStore archives into PARTITION_ARCHIVE_SCHEMA and the optional PARTITION_ARCHIVE_TABLESPACE
+func newPartitionStore(db *gorm.DB) repository.OrderPartitionStore {
+	schema := os.Getenv("PARTITION_ARCHIVE_SCHEMA")
+	if schema == "" {
+		schema = "archive"
+	}
+	return persistence.NewGormOrderPartitionStore(db, schema, os.Getenv("PARTITION_ARCHIVE_TABLESPACE"))
+}
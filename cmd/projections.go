@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"order-service/internal/infrastructure/projections"
+)
+
+const projectionsUsage = "usage: projections rebuild"
+
+// runProjections executes the projections subcommand
+func runProjections(args []string) error {
+	if len(args) != 1 || args[0] != "rebuild" {
+		return errors.New(projectionsUsage)
+	}
+	if err := requirePostgresOrders("rebuilding the read model"); err != nil {
+		return err
+	}
+
+	db, err := openDatabase()
+	if err != nil {
+		return fmt.Errorf("could not connect to the database: %w", err)
+	}
+
+	replayed, err := projections.NewOrderProjector(db).Rebuild(context.Background())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("replayed %d order(s) into the read model\n", replayed)
+	return nil
+}
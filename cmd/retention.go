@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"order-service/internal/domain/models"
+	"order-service/internal/domain/services"
+	"order-service/internal/infrastructure/persistence"
+	"order-service/internal/infrastructure/projections"
+	"os"
+	"strconv"
+	"time"
+)
+
+const retentionUsage = "usage: retention report | run"
+
+// runRetention executes the retention subcommand, report is a dry run
+func runRetention(args []string) error {
+	if len(args) != 1 || (args[0] != "report" && args[0] != "run") {
+		return errors.New(retentionUsage)
+	}
+	if err := requirePostgresOrders("retention"); err != nil {
+		return err
+	}
+
+	policy, ok, err := retentionPolicy()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("no retention policy configured, set RETENTION_DAYS")
+	}
+
+	db, err := openDatabase()
+	if err != nil {
+		return fmt.Errorf("could not connect to the database: %w", err)
+	}
+
+	// Purged orders must also leave the read model
+	eventPublisher := projections.NewProjectingEventPublisher(&services.LoggerEventPublisher{}, projections.NewOrderProjector(db))
+	retentionService, err := services.NewRetentionService(persistence.NewGormOrderRetentionRepository(db), persistence.NewGormOrderRepository(db), eventPublisher, policy)
+	if err != nil {
+		return err
+	}
+
+	report, err := retentionService.Run(context.Background(), args[0] == "report")
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if encodeErr := encoder.Encode(report); encodeErr != nil {
+		return encodeErr
+	}
+	return err
+}
+
+// retentionPolicy reads the policy from RETENTION_DAYS, RETENTION_ACTION and RETENTION_BATCH_SIZE.
+// The second result is false when no retention period is configured.
+func retentionPolicy() (models.RetentionPolicy, bool, error) {
+	days := os.Getenv("RETENTION_DAYS")
+	if days == "" {
+		return models.RetentionPolicy{}, false, nil
+	}
+
+	period, err := strconv.Atoi(days)
+	if err != nil {
+		return models.RetentionPolicy{}, false, fmt.Errorf("invalid RETENTION_DAYS %q", days)
+	}
+
+	policy := models.RetentionPolicy{
+		Period:    time.Duration(period) * 24 * time.Hour,
+		Action:    models.RetentionAction(os.Getenv("RETENTION_ACTION")),
+		BatchSize: 500,
+	}
+	if policy.Action == "" {
+		policy.Action = models.RetentionAnonymize
+	}
+	if size := os.Getenv("RETENTION_BATCH_SIZE"); size != "" {
+		if policy.BatchSize, err = strconv.Atoi(size); err != nil {
+			return models.RetentionPolicy{}, false, fmt.Errorf("invalid RETENTION_BATCH_SIZE %q", size)
+		}
+	}
+	return policy, true, policy.Validate()
+}
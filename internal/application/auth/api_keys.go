@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"order-service/internal/application/repository"
+	"order-service/internal/domain/domainerr"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIKeyPrefix starts every API key, so leaked keys are easy to recognise and scan for
+const APIKeyPrefix = "osk_"
+
+// APIKeyScopes are the scopes an API key may be given
+var APIKeyScopes = []string{ScopeOrdersRead, ScopeOrdersWrite, ScopeAdmin}
+
+// ErrInvalidAPIKey is returned for an API key that is unknown, expired or revoked
+var ErrInvalidAPIKey = domainerr.Unauthorized("invalid_api_key", "API key is invalid, expired or revoked")
+
+// IssuedAPIKey is a newly created API key. Secret is the full key the caller sends; it is only
+// available when the key is issued and cannot be recovered afterwards.
+type IssuedAPIKey struct {
+	repository.APIKey
+	Secret string
+}
+
+// APIKeys issues, rotates, revokes and checks the API keys of service-to-service callers
+type APIKeys struct {
+	store repository.APIKeyStore
+	now   func() time.Time
+}
+
+func NewAPIKeys(store repository.APIKeyStore) *APIKeys {
+	return &APIKeys{store: store, now: time.Now}
+}
+
+// Create issues a key with the given scopes that expires at expiresAt, or never when it is nil
+func (k *APIKeys) Create(ctx context.Context, name string, scopes []string, expiresAt *time.Time) (IssuedAPIKey, error) {
+	if err := k.validate(name, scopes, expiresAt); err != nil {
+		return IssuedAPIKey{}, err
+	}
+	issued, err := newAPIKey(name, scopes, expiresAt)
+	if err != nil {
+		return IssuedAPIKey{}, err
+	}
+	issued.CreatedAt = k.now()
+	if err := k.store.Create(ctx, &issued.APIKey); err != nil {
+		return IssuedAPIKey{}, err
+	}
+	return issued, nil
+}
+
+// Rotate issues a replacement for key id with the same name, scopes and expiry. The old key
+// keeps working for gracePeriod so callers can switch over, and stops at once when it is zero.
+func (k *APIKeys) Rotate(ctx context.Context, id uint, gracePeriod time.Duration) (IssuedAPIKey, error) {
+	if gracePeriod < 0 {
+		return IssuedAPIKey{}, domainerr.Validation("invalid_grace_period", "grace period must not be negative")
+	}
+	old, err := k.store.FindByID(ctx, id)
+	if err != nil {
+		return IssuedAPIKey{}, err
+	}
+	now := k.now()
+	if !old.Active(now) {
+		return IssuedAPIKey{}, domainerr.Conflict("api_key_inactive", "only an active API key can be rotated")
+	}
+
+	issued, err := newAPIKey(old.Name, old.Scopes, old.ExpiresAt)
+	if err != nil {
+		return IssuedAPIKey{}, err
+	}
+	issued.CreatedAt = now
+	issued.RotatedFromID = &old.ID
+	if err := k.store.Rotate(ctx, &issued.APIKey, now.Add(gracePeriod)); err != nil {
+		return IssuedAPIKey{}, err
+	}
+	return issued, nil
+}
+
+// Revoke stops key id from authenticating any further request
+func (k *APIKeys) Revoke(ctx context.Context, id uint) error {
+	return k.store.Revoke(ctx, id, k.now())
+}
+
+// List returns every key, including expired and revoked ones, without their secrets
+func (k *APIKeys) List(ctx context.Context) ([]repository.APIKey, error) {
+	return k.store.List(ctx)
+}
+
+// Authenticate returns the principal of an active API key. Keys are compared by hash, in constant time.
+func (k *APIKeys) Authenticate(ctx context.Context, secret string) (Principal, error) {
+	prefix, ok := keyPrefix(secret)
+	if !ok {
+		return Principal{}, ErrInvalidAPIKey
+	}
+	key, err := k.store.FindByPrefix(ctx, prefix)
+	if errors.Is(err, repository.ErrAPIKeyNotFound) {
+		return Principal{}, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return Principal{}, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashAPIKey(secret)), []byte(key.Hash)) != 1 || !key.Active(k.now()) {
+		return Principal{}, ErrInvalidAPIKey
+	}
+
+	roles := []string{RoleService}
+	for _, scope := range key.Scopes {
+		if scope == ScopeAdmin {
+			roles = append(roles, RoleAdmin)
+		}
+	}
+	return Principal{
+		Subject:  "apikey:" + strconv.FormatUint(uint64(key.ID), 10),
+		Roles:    roles,
+		Scopes:   key.Scopes,
+		APIKeyID: key.ID,
+	}, nil
+}
+
+func (k *APIKeys) validate(name string, scopes []string, expiresAt *time.Time) error {
+	var fields []domainerr.FieldError
+	if strings.TrimSpace(name) == "" {
+		fields = append(fields, domainerr.FieldError{Field: "Name", Message: "is required"})
+	}
+	if len(scopes) == 0 {
+		fields = append(fields, domainerr.FieldError{Field: "Scopes", Message: "is required"})
+	}
+	for _, scope := range scopes {
+		if !contains(APIKeyScopes, scope) {
+			fields = append(fields, domainerr.FieldError{Field: "Scopes", Message: "must be one of " + strings.Join(APIKeyScopes, ", ")})
+			break
+		}
+	}
+	if expiresAt != nil && !expiresAt.After(k.now()) {
+		fields = append(fields, domainerr.FieldError{Field: "ExpiresAt", Message: "must be in the future"})
+	}
+	if len(fields) > 0 {
+		return domainerr.Validation("validation_failed", "request has invalid fields").WithFields(fields...)
+	}
+	return nil
+}
+
+// newAPIKey generates a key of the form osk_<prefix>_<secret>. The prefix finds the stored key
+// and the 256-bit secret makes a fast hash as safe to store as a password hash.
+func newAPIKey(name string, scopes []string, expiresAt *time.Time) (IssuedAPIKey, error) {
+	prefix := make([]byte, 6)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(prefix); err != nil {
+		return IssuedAPIKey{}, err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return IssuedAPIKey{}, err
+	}
+
+	key := APIKeyPrefix + hex.EncodeToString(prefix) + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	return IssuedAPIKey{
+		APIKey: repository.APIKey{
+			Name:      name,
+			Prefix:    hex.EncodeToString(prefix),
+			Hash:      hashAPIKey(key),
+			Scopes:    scopes,
+			ExpiresAt: expiresAt,
+		},
+		Secret: key,
+	}, nil
+}
+
+func keyPrefix(key string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, APIKeyPrefix)
+	if !ok {
+		return "", false
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	return prefix, ok && prefix != "" && secret != ""
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
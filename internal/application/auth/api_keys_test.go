@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"context"
+	"order-service/internal/domain/domainerr"
+	"order-service/internal/infrastructure/persistence"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAPIKeys tests that issued keys authenticate until they expire or are revoked
+func TestAPIKeys(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	store := persistence.NewInMemoryAPIKeyStore()
+	keys := NewAPIKeys(store)
+	keys.now = func() time.Time { return now }
+
+	expiresAt := now.Add(24 * time.Hour)
+	issued, err := keys.Create(ctx, "warehouse", []string{ScopeOrdersRead}, &expiresAt)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(issued.Secret, APIKeyPrefix+issued.Prefix+"_"))
+	assert.NotContains(t, issued.Hash, issued.Secret)
+
+	principal, err := keys.Authenticate(ctx, issued.Secret)
+	assert.NoError(t, err)
+	assert.Equal(t, issued.ID, principal.APIKeyID)
+	assert.Equal(t, []string{RoleService}, principal.Roles)
+	assert.Equal(t, []string{ScopeOrdersRead}, principal.Scopes)
+
+	for _, forged := range []string{"", "osk_", issued.Secret + "x", APIKeyPrefix + issued.Prefix + "_guess", "Bearer " + issued.Secret} {
+		_, err := keys.Authenticate(ctx, forged)
+		assert.ErrorIs(t, err, ErrInvalidAPIKey, forged)
+	}
+
+	now = expiresAt
+	_, err = keys.Authenticate(ctx, issued.Secret)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	_, err = keys.Create(ctx, "", []string{"orders:delete"}, &expiresAt)
+	validation, ok := domainerr.As(err)
+	require.True(t, ok)
+	assert.Len(t, validation.Fields, 3)
+}
+
+// TestAPIKeysRotate tests that a rotated key keeps working for the grace period only
+func TestAPIKeysRotate(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	keys := NewAPIKeys(persistence.NewInMemoryAPIKeyStore())
+	keys.now = func() time.Time { return now }
+
+	old, err := keys.Create(ctx, "billing", []string{ScopeOrdersRead, ScopeAdmin}, nil)
+	require.NoError(t, err)
+
+	replacement, err := keys.Rotate(ctx, old.ID, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, &old.ID, replacement.RotatedFromID)
+	assert.Equal(t, old.Scopes, replacement.Scopes)
+	assert.NotEqual(t, old.Secret, replacement.Secret)
+
+	_, err = keys.Authenticate(ctx, old.Secret)
+	assert.NoError(t, err, "the old key works during the grace period")
+	principal, err := keys.Authenticate(ctx, replacement.Secret)
+	assert.NoError(t, err)
+	assert.True(t, principal.HasRole(RoleAdmin))
+
+	now = now.Add(time.Hour)
+	_, err = keys.Authenticate(ctx, old.Secret)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	assert.NoError(t, keys.Revoke(ctx, replacement.ID))
+	_, err = keys.Authenticate(ctx, replacement.Secret)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	_, err = keys.Rotate(ctx, replacement.ID, time.Hour)
+	assert.Error(t, err, "a revoked key cannot be rotated")
+}
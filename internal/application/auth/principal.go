@@ -0,0 +1,75 @@
+// Package auth describes who is calling the application and issues the API keys of other services.
+package auth
+
+import (
+	"context"
+	"order-service/internal/domain/domainerr"
+	"strconv"
+)
+
+// Roles of the default policy
+const (
+	// RoleCustomer may see and change only the orders of the customer named by its subject
+	RoleCustomer = "customer"
+	// RoleStaff may see and change every order
+	RoleStaff = "staff"
+	// RoleAdmin has every staff permission and manages API keys
+	RoleAdmin = "admin"
+	// RoleService is held by other services calling with an API key
+	RoleService = "service"
+)
+
+// Scopes an API key may be given
+const (
+	// ScopeOrdersRead lets an API key read orders
+	ScopeOrdersRead = "orders:read"
+	// ScopeOrdersWrite lets an API key create and change orders
+	ScopeOrdersWrite = "orders:write"
+	// ScopeAdmin lets an API key do everything, including managing API keys
+	ScopeAdmin = "admin"
+)
+
+// ErrUnauthenticated is returned when no principal is attached to the request
+var ErrUnauthenticated = domainerr.Unauthorized("unauthenticated", "authentication is required")
+
+// Principal is the authenticated caller. The Subject of a customer is their customer ID.
+// Principals authenticated by an API key carry its ID and the key's Scopes. What a principal
+// may do is decided by the policy package.
+type Principal struct {
+	Subject  string
+	Roles    []string
+	Scopes   []string
+	APIKeyID uint
+}
+
+// HasRole reports whether the principal has role
+func (p Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// CustomerID returns the customer the principal acts for, or 0 when its subject is not a customer ID
+func (p Principal) CustomerID() uint {
+	id, err := strconv.ParseUint(p.Subject, 10, 0)
+	if err != nil {
+		return 0
+	}
+	return uint(id)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a context that carries the authenticated principal
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the principal carried by ctx, if any
+func PrincipalFrom(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
@@ -0,0 +1,132 @@
+package bulk
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"order-service/internal/application/dto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readAll(t *testing.T, reader Reader) []dto.ImportRow {
+	var rows []dto.ImportRow
+	for {
+		row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return rows
+		}
+		require.NoError(t, err)
+		rows = append(rows, row)
+	}
+}
+
+// TestCSVReaderGroupsItems tests that consecutive rows of one order become one order
+func TestCSVReaderGroupsItems(t *testing.T) {
+	input := `order_id,customer_id,order_date,product_id,product_name,quantity,price
+ORD-1,1,2024-10-27T09:00:00Z,10,Widget,2,5
+ORD-1,1,2024-10-27T09:00:00Z,11,Gadget,1,7.5
+ORD-2,2,2024-10-28T09:00:00Z,10,Widget,x,5
+ORD-3,3,2024-10-29T09:00:00Z,12,Gizmo,1,3
+`
+	rows := readAll(t, NewReader(FormatCSV, strings.NewReader(input)))
+	require.Len(t, rows, 3)
+
+	assert.Equal(t, 2, rows[0].Row)
+	assert.NoError(t, rows[0].Err)
+	assert.Equal(t, "ORD-1", rows[0].Record.OrderID)
+	assert.Equal(t, time.Date(2024, time.October, 27, 9, 0, 0, 0, time.UTC), rows[0].Record.OrderDate)
+	assert.Len(t, rows[0].Record.Items, 2)
+	assert.Equal(t, 7.5, rows[0].Record.Items[1].Price)
+
+	assert.Equal(t, 4, rows[1].Row)
+	assert.ErrorContains(t, rows[1].Err, `invalid quantity "x"`)
+
+	assert.Equal(t, 5, rows[2].Row)
+	assert.NoError(t, rows[2].Err)
+}
+
+// TestCSVReaderRequiresColumns tests that a header without the required columns fails the import
+func TestCSVReaderRequiresColumns(t *testing.T) {
+	_, err := NewReader(FormatCSV, strings.NewReader("order_id,customer_id\n")).Next()
+	assert.ErrorContains(t, err, `missing column "order_date"`)
+}
+
+// TestJSONLinesReader tests that each line is an order and malformed lines are reported on their row
+func TestJSONLinesReader(t *testing.T) {
+	input := `{"order_id":"ORD-1","customer_id":1,"order_date":"2024-10-27T09:00:00Z","items":[{"product_id":10,"quantity":2,"price":5}]}
+
+{"order_id":"ORD-2","unknown":true}
+`
+	rows := readAll(t, NewReader(FormatJSONLines, strings.NewReader(input)))
+	require.Len(t, rows, 2)
+	assert.NoError(t, rows[0].Err)
+	assert.Len(t, rows[0].Record.Items, 1)
+	assert.Equal(t, 3, rows[1].Row)
+	assert.Error(t, rows[1].Err)
+}
+
+// TestRoundTrip tests that exported orders import unchanged in both formats
+func TestRoundTrip(t *testing.T) {
+	records := []dto.OrderRecord{
+		{
+			OrderID:      "ORD-1",
+			CustomerID:   1,
+			CustomerName: "Ada, Countess of Lovelace",
+			Status:       "pending",
+			OrderDate:    time.Date(2024, time.October, 27, 9, 0, 0, 0, time.UTC),
+			TotalAmount:  17.5,
+			Items: []dto.OrderItemResponse{
+				{ProductID: 10, ProductName: "Widget", Quantity: 2, Price: 5},
+				{ProductID: 11, ProductName: "Gadget", Quantity: 1, Price: 7.5},
+			},
+		},
+		{
+			OrderID:    "ORD-2",
+			CustomerID: 2,
+			OrderDate:  time.Date(2024, time.October, 28, 9, 0, 0, 0, time.UTC),
+			Items:      []dto.OrderItemResponse{{ProductID: 12, Quantity: 1, Price: 3}},
+		},
+	}
+
+	for _, format := range []Format{FormatCSV, FormatJSONLines} {
+		var buffer bytes.Buffer
+		writer := NewWriter(format, &buffer)
+		for _, record := range records {
+			require.NoError(t, writer.Write(record))
+		}
+		require.NoError(t, writer.Flush())
+
+		rows := readAll(t, NewReader(format, &buffer))
+		require.Len(t, rows, len(records), format)
+		for i, row := range rows {
+			assert.NoError(t, row.Err, format)
+			assert.Equal(t, records[i].OrderID, row.Record.OrderID, format)
+			assert.Equal(t, records[i].CustomerName, row.Record.CustomerName, format)
+			assert.Equal(t, records[i].Items, row.Record.Items, format)
+			assert.True(t, records[i].OrderDate.Equal(row.Record.OrderDate), format)
+		}
+	}
+}
+
+// TestParseFormat tests format names, extensions and content types
+func TestParseFormat(t *testing.T) {
+	for value, expected := range map[string]Format{
+		"csv":                     FormatCSV,
+		".csv":                    FormatCSV,
+		"text/csv; charset=utf-8": FormatCSV,
+		"jsonl":                   FormatJSONLines,
+		"application/x-ndjson":    FormatJSONLines,
+	} {
+		format, err := ParseFormat(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, format, value)
+	}
+
+	_, err := ParseFormat("application/json")
+	assert.Error(t, err)
+}
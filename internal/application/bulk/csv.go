@@ -0,0 +1,207 @@
+package bulk
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"order-service/internal/application/dto"
+	"strconv"
+	"time"
+)
+
+// csvColumns is the export header; imports need the columns in csvRequired in any order
+// and ignore the rest
+var (
+	csvColumns  = []string{"order_id", "customer_id", "customer_name", "status", "order_date", "total_amount", "product_id", "product_name", "quantity", "price"}
+	csvRequired = []string{"order_id", "customer_id", "order_date", "product_id", "quantity", "price"}
+)
+
+// csvLine is one parsed item row together with the order columns it repeats
+type csvLine struct {
+	number int
+	record dto.OrderRecord
+	err    error
+}
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+	// pending is the first line of the next order, read while finishing the previous one
+	pending *csvLine
+	done    bool
+}
+
+func newCSVReader(r io.Reader) *csvReader {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	return &csvReader{reader: reader}
+}
+
+// Next groups consecutive rows sharing an order_id into one order
+func (r *csvReader) Next() (dto.ImportRow, error) {
+	if r.columns == nil {
+		if err := r.readHeader(); err != nil {
+			return dto.ImportRow{}, err
+		}
+	}
+
+	first := r.pending
+	r.pending = nil
+	if first == nil {
+		line, err := r.readLine()
+		if err != nil {
+			return dto.ImportRow{}, err
+		}
+		first = line
+	}
+
+	row := dto.ImportRow{Row: first.number, Record: first.record, Err: first.err}
+	for {
+		line, err := r.readLine()
+		if errors.Is(err, io.EOF) {
+			return row, nil
+		}
+		if err != nil {
+			return dto.ImportRow{}, err
+		}
+		if line.record.OrderID != row.Record.OrderID || row.Record.OrderID == "" {
+			r.pending = line
+			return row, nil
+		}
+		if row.Err == nil && line.err != nil {
+			row.Err = line.err
+		}
+		row.Record.Items = append(row.Record.Items, line.record.Items...)
+	}
+}
+
+func (r *csvReader) readHeader() error {
+	header, err := r.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return io.EOF
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	r.columns = make(map[string]int, len(header))
+	for i, name := range header {
+		r.columns[name] = i
+	}
+	for _, name := range csvRequired {
+		if _, ok := r.columns[name]; !ok {
+			return fmt.Errorf("CSV header is missing column %q", name)
+		}
+	}
+	return nil
+}
+
+// readLine parses the next row; malformed values are reported on the line, not as an error
+func (r *csvReader) readLine() (*csvLine, error) {
+	if r.done {
+		return nil, io.EOF
+	}
+	fields, err := r.reader.Read()
+	if errors.Is(err, io.EOF) {
+		r.done = true
+		return nil, io.EOF
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return &csvLine{number: parseErr.StartLine, err: parseErr.Err}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	number, _ := r.reader.FieldPos(0)
+	line := &csvLine{number: number}
+
+	field := func(name string) string {
+		if i, ok := r.columns[name]; ok && i < len(fields) {
+			return fields[i]
+		}
+		return ""
+	}
+	record := dto.OrderRecord{
+		OrderID:      field("order_id"),
+		CustomerName: field("customer_name"),
+	}
+	item := dto.OrderItemResponse{ProductName: field("product_name")}
+
+	var customerID, productID uint64
+	errs := []error{}
+	parse := func(name string, fn func(string) error) {
+		if err := fn(field(name)); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s %q", name, field(name)))
+		}
+	}
+	parse("customer_id", func(v string) (err error) { customerID, err = strconv.ParseUint(v, 10, 32); return })
+	parse("order_date", func(v string) (err error) { record.OrderDate, err = time.Parse(time.RFC3339, v); return })
+	parse("product_id", func(v string) (err error) { productID, err = strconv.ParseUint(v, 10, 32); return })
+	parse("quantity", func(v string) (err error) { item.Quantity, err = strconv.Atoi(v); return })
+	parse("price", func(v string) (err error) { item.Price, err = strconv.ParseFloat(v, 64); return })
+
+	record.CustomerID = uint(customerID)
+	item.ProductID = uint(productID)
+	record.Items = []dto.OrderItemResponse{item}
+	line.record = record
+	line.err = errors.Join(errs...)
+	return line, nil
+}
+
+type csvWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{writer: csv.NewWriter(w)}
+}
+
+// Write writes one row per item, or a single row with empty item columns for an order without items
+func (w *csvWriter) Write(record dto.OrderRecord) error {
+	if !w.headerWritten {
+		if err := w.writer.Write(csvColumns); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+
+	order := []string{
+		record.OrderID,
+		strconv.FormatUint(uint64(record.CustomerID), 10),
+		record.CustomerName,
+		record.Status,
+		record.OrderDate.Format(time.RFC3339),
+		strconv.FormatFloat(record.TotalAmount, 'f', -1, 64),
+	}
+	if len(record.Items) == 0 {
+		return w.writer.Write(append(order, "", "", "", ""))
+	}
+	for _, item := range record.Items {
+		row := append(append([]string{}, order...),
+			strconv.FormatUint(uint64(item.ProductID), 10),
+			item.ProductName,
+			strconv.Itoa(item.Quantity),
+			strconv.FormatFloat(item.Price, 'f', -1, 64),
+		)
+		if err := w.writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush writes the header even when nothing was exported
+func (w *csvWriter) Flush() error {
+	if !w.headerWritten {
+		if err := w.writer.Write(csvColumns); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
@@ -0,0 +1,75 @@
+// Package bulk reads and writes orders in the bulk import and export formats.
+package bulk
+
+import (
+	"fmt"
+	"io"
+	"order-service/internal/application/dto"
+	"strings"
+)
+
+// Format is a bulk file format
+type Format string
+
+const (
+	// FormatCSV has one row per order item, repeating the order columns; consecutive rows
+	// with the same order_id form one order
+	FormatCSV Format = "csv"
+	// FormatJSONLines has one JSON order per line
+	FormatJSONLines Format = "jsonl"
+)
+
+// ParseFormat accepts a format name, file extension or content type
+func ParseFormat(value string) (Format, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	value = strings.TrimPrefix(value, ".")
+	if mediaType, _, found := strings.Cut(value, ";"); found {
+		value = strings.TrimSpace(mediaType)
+	}
+
+	switch value {
+	case "csv", "text/csv":
+		return FormatCSV, nil
+	case "jsonl", "ndjson", "application/jsonl", "application/x-ndjson", "application/x-jsonlines":
+		return FormatJSONLines, nil
+	default:
+		return "", fmt.Errorf("unsupported bulk format %q, use csv or jsonl", value)
+	}
+}
+
+// ContentType is the media type served for the format
+func (f Format) ContentType() string {
+	if f == FormatCSV {
+		return "text/csv"
+	}
+	return "application/x-ndjson"
+}
+
+// Reader yields the orders of an import one at a time and returns io.EOF after the last.
+// Problems with a single order are reported on the row, other errors end the import.
+type Reader interface {
+	Next() (dto.ImportRow, error)
+}
+
+// Writer writes exported orders
+type Writer interface {
+	Write(record dto.OrderRecord) error
+	// Flush writes any buffered data to the underlying writer
+	Flush() error
+}
+
+// NewReader reads orders in format from r
+func NewReader(format Format, r io.Reader) Reader {
+	if format == FormatCSV {
+		return newCSVReader(r)
+	}
+	return newJSONLinesReader(r)
+}
+
+// NewWriter writes orders in format to w
+func NewWriter(format Format, w io.Writer) Writer {
+	if format == FormatCSV {
+		return newCSVWriter(w)
+	}
+	return newJSONLinesWriter(w)
+}
@@ -0,0 +1,62 @@
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"order-service/internal/application/dto"
+)
+
+// maxLineSize bounds a single JSON Lines order
+const maxLineSize = 1 << 20
+
+type jsonLinesReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newJSONLinesReader(r io.Reader) *jsonLinesReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	return &jsonLinesReader{scanner: scanner}
+}
+
+func (r *jsonLinesReader) Next() (dto.ImportRow, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		row := dto.ImportRow{Row: r.line}
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.DisallowUnknownFields()
+		row.Err = decoder.Decode(&row.Record)
+		return row, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return dto.ImportRow{}, err
+	}
+	return dto.ImportRow{}, io.EOF
+}
+
+type jsonLinesWriter struct {
+	writer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func newJSONLinesWriter(w io.Writer) *jsonLinesWriter {
+	writer := bufio.NewWriter(w)
+	return &jsonLinesWriter{writer: writer, encoder: json.NewEncoder(writer)}
+}
+
+// Write encodes the order on its own line
+func (w *jsonLinesWriter) Write(record dto.OrderRecord) error {
+	return w.encoder.Encode(record)
+}
+
+func (w *jsonLinesWriter) Flush() error {
+	return w.writer.Flush()
+}
@@ -0,0 +1,62 @@
+package command
+
+import (
+	"context"
+	"order-service/internal/application/policy"
+	"order-service/internal/domain/repositories"
+)
+
+// OrderCommand is implemented by commands that act on an existing order
+type OrderCommand interface {
+	Command
+	TargetOrderID() uint
+}
+
+// PolicyAuthorizer authorizes every command against the policy, on the order it creates or acts on
+type PolicyAuthorizer struct {
+	authorizer *policy.Authorizer
+	orders     repositories.OrderRepository
+}
+
+func NewPolicyAuthorizer(authorizer *policy.Authorizer, orders repositories.OrderRepository) *PolicyAuthorizer {
+	return &PolicyAuthorizer{authorizer: authorizer, orders: orders}
+}
+
+// Authorize loads the target order only when the caller is not allowed to act on every order
+func (a *PolicyAuthorizer) Authorize(ctx context.Context, cmd Command) error {
+	action, ok := commandAction(cmd)
+	if !ok {
+		return policy.ErrDenied
+	}
+
+	switch cmd := cmd.(type) {
+	case CreateOrder:
+		return a.authorizer.Authorize(ctx, action, policy.Order(0, cmd.Order.CustomerID))
+	case OrderCommand:
+		if _, all, err := a.authorizer.Scope(ctx, action); err != nil || all {
+			return err
+		}
+		order, err := a.orders.FindByID(ctx, cmd.TargetOrderID())
+		if err != nil {
+			return err
+		}
+		return a.authorizer.Authorize(ctx, action, policy.Order(order.ID, order.CustomerID))
+	}
+	return policy.ErrDenied
+}
+
+func commandAction(cmd Command) (policy.Action, bool) {
+	switch cmd.(type) {
+	case CreateOrder:
+		return policy.CreateOrder, true
+	case AddItem:
+		return policy.AddItem, true
+	case CancelOrder:
+		return policy.CancelOrder, true
+	case UpdateOrder, PatchOrder:
+		return policy.UpdateOrder, true
+	case DeleteOrder:
+		return policy.DeleteOrder, true
+	}
+	return "", false
+}
@@ -0,0 +1,73 @@
+package command
+
+import (
+	"context"
+	"order-service/internal/application/auth"
+	"order-service/internal/application/dto"
+	"order-service/internal/application/policy"
+	"order-service/internal/domain/models"
+	"order-service/internal/domain/repositories"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOrderRepository is a mock implementation of the OrderRepository interface
+type MockOrderRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderRepository) Save(ctx context.Context, order *models.Order) error {
+	return m.Called(ctx, order).Error(0)
+}
+
+func (m *MockOrderRepository) FindByID(ctx context.Context, id uint) (*models.Order, error) {
+	args := m.Called(ctx, id)
+	order, _ := args.Get(0).(*models.Order)
+	return order, args.Error(1)
+}
+
+func (m *MockOrderRepository) FindAll(ctx context.Context) ([]models.Order, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Order), args.Error(1)
+}
+
+// TestPolicyAuthorizer tests that customers may only run commands on their own orders
+func TestPolicyAuthorizer(t *testing.T) {
+	orders := new(MockOrderRepository)
+	orders.On("FindByID", mock.Anything, uint(1)).Return(&models.Order{ID: 1, CustomerID: 42}, nil)
+	orders.On("FindByID", mock.Anything, uint(2)).Return(&models.Order{ID: 2, CustomerID: 7}, nil)
+	authorizer := NewPolicyAuthorizer(policy.NewAuthorizer(policy.Default(), policy.LogAuditor{}), orders)
+
+	customer := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "42", Roles: []string{auth.RoleCustomer}})
+	staff := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "alice", Roles: []string{auth.RoleStaff}})
+	readOnlyKey := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "apikey:1", Roles: []string{auth.RoleService}, Scopes: []string{auth.ScopeOrdersRead}, APIKeyID: 1})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		cmd  Command
+		err  error
+	}{
+		{"create own order", customer, CreateOrder{Order: dto.OrderCreateDto{CustomerID: 42}}, nil},
+		{"create for another customer", customer, CreateOrder{Order: dto.OrderCreateDto{CustomerID: 7}}, policy.ErrDenied},
+		{"cancel own order", customer, CancelOrder{ID: 1}, nil},
+		{"cancel another customer's order", customer, CancelOrder{ID: 2}, repositories.ErrOrderNotFound},
+		{"delete another customer's order", customer, DeleteOrder{ID: 2}, repositories.ErrOrderNotFound},
+		{"staff", staff, DeleteOrder{ID: 2}, nil},
+		{"read-only API key", readOnlyKey, PatchOrder{ID: 1}, policy.ErrScopeMissing},
+		{"anonymous", context.Background(), CancelOrder{ID: 1}, auth.ErrUnauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizer.Authorize(tt.ctx, tt.cmd)
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
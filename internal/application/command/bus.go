@@ -0,0 +1,64 @@
+// Package command dispatches the order use cases as explicit commands through a bus,
+// so cross-cutting concerns live in middleware instead of in every service method.
+package command
+
+import (
+	"context"
+	"fmt"
+)
+
+// Command is a request to change the state of the system
+type Command interface {
+	CommandName() string
+}
+
+// Handler executes a command and returns its result
+type Handler func(ctx context.Context, cmd Command) (interface{}, error)
+
+// Middleware wraps a Handler with behaviour that applies to every command
+type Middleware func(next Handler) Handler
+
+// Bus routes each command to the handler registered for its name
+type Bus struct {
+	handlers   map[string]Handler
+	middleware []Middleware
+}
+
+// NewBus creates a bus; middleware runs in the order given, the first one outermost
+func NewBus(middleware ...Middleware) *Bus {
+	return &Bus{handlers: map[string]Handler{}, middleware: middleware}
+}
+
+// Register adds a typed handler for the commands of type C
+func Register[C Command, R any](bus *Bus, handle func(ctx context.Context, cmd C) (R, error)) {
+	var zero C
+	bus.handlers[zero.CommandName()] = func(ctx context.Context, cmd Command) (interface{}, error) {
+		return handle(ctx, cmd.(C))
+	}
+}
+
+// Dispatch sends cmd through the middleware to its handler
+func (b *Bus) Dispatch(ctx context.Context, cmd Command) (interface{}, error) {
+	handler, ok := b.handlers[cmd.CommandName()]
+	if !ok {
+		return nil, fmt.Errorf("no handler registered for command %s", cmd.CommandName())
+	}
+	for i := len(b.middleware) - 1; i >= 0; i-- {
+		handler = b.middleware[i](handler)
+	}
+	return handler(ctx, cmd)
+}
+
+// Dispatch sends cmd on bus and returns its result as R
+func Dispatch[R any](ctx context.Context, bus *Bus, cmd Command) (R, error) {
+	var zero R
+	result, err := bus.Dispatch(ctx, cmd)
+	if err != nil {
+		return zero, err
+	}
+	typed, ok := result.(R)
+	if !ok {
+		return zero, fmt.Errorf("command %s returned %T, expected %T", cmd.CommandName(), result, zero)
+	}
+	return typed, nil
+}
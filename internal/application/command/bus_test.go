@@ -0,0 +1,93 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCommand struct {
+	Value string
+}
+
+func (testCommand) CommandName() string { return "Test" }
+
+func (c testCommand) Validate() error {
+	if c.Value == "" {
+		return errors.New("value is required")
+	}
+	return nil
+}
+
+type denyAll struct{}
+
+func (denyAll) Authorize(context.Context, Command) error {
+	return errors.New("denied")
+}
+
+type recordingTransactions struct {
+	calls int
+}
+
+func (r *recordingTransactions) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	r.calls++
+	return fn(ctx)
+}
+
+// TestDispatch tests that commands reach their typed handler through the middleware in order
+func TestDispatch(t *testing.T) {
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, cmd Command) (interface{}, error) {
+				order = append(order, name)
+				return next(ctx, cmd)
+			}
+		}
+	}
+	transactions := &recordingTransactions{}
+
+	bus := NewBus(trace("outer"), trace("inner"), ValidationMiddleware(), TransactionMiddleware(transactions))
+	Register(bus, func(ctx context.Context, cmd testCommand) (string, error) {
+		order = append(order, "handler")
+		return "handled " + cmd.Value, nil
+	})
+
+	result, err := Dispatch[string](context.Background(), bus, testCommand{Value: "x"})
+	assert.NoError(t, err)
+	assert.Equal(t, "handled x", result)
+	assert.Equal(t, []string{"outer", "inner", "handler"}, order)
+	assert.Equal(t, 1, transactions.calls)
+}
+
+// TestDispatchValidation tests that invalid commands never reach the handler
+func TestDispatchValidation(t *testing.T) {
+	bus := NewBus(ValidationMiddleware())
+	Register(bus, func(ctx context.Context, cmd testCommand) (string, error) {
+		t.Fatal("handler must not run")
+		return "", nil
+	})
+
+	_, err := Dispatch[string](context.Background(), bus, testCommand{})
+	assert.EqualError(t, err, "value is required")
+}
+
+// TestDispatchAuthorization tests that denied commands never reach the handler
+func TestDispatchAuthorization(t *testing.T) {
+	bus := NewBus(AuthorizationMiddleware(denyAll{}))
+	Register(bus, func(ctx context.Context, cmd testCommand) (string, error) {
+		t.Fatal("handler must not run")
+		return "", nil
+	})
+
+	_, err := Dispatch[string](context.Background(), bus, testCommand{Value: "x"})
+	assert.EqualError(t, err, "denied")
+}
+
+// TestDispatchUnknownCommand tests that unregistered commands are rejected
+func TestDispatchUnknownCommand(t *testing.T) {
+	_, err := NewBus().Dispatch(context.Background(), testCommand{Value: "x"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,121 @@
+package command
+
+import (
+	"context"
+	"order-service/internal/application/auth"
+	"order-service/internal/infrastructure/logging"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Validator is implemented by commands that can check their own fields
+type Validator interface {
+	Validate() error
+}
+
+// Authorizer decides whether the caller in ctx may execute cmd
+type Authorizer interface {
+	Authorize(ctx context.Context, cmd Command) error
+}
+
+// TransactionManager runs fn in a transaction that repositories pick up from ctx
+type TransactionManager interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// ValidationMiddleware rejects commands whose Validate method fails
+func ValidationMiddleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, cmd Command) (interface{}, error) {
+			if validator, ok := cmd.(Validator); ok {
+				if err := validator.Validate(); err != nil {
+					return nil, err
+				}
+			}
+			return next(ctx, cmd)
+		}
+	}
+}
+
+// LoggingMiddleware logs every command with its caller, duration and outcome. The caller of an
+// API key is logged as apikey:<id>.
+func LoggingMiddleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, cmd Command) (interface{}, error) {
+			start := time.Now()
+			principal, _ := auth.PrincipalFrom(ctx)
+			result, err := next(ctx, cmd)
+			if err != nil {
+				logging.Ctx(ctx).Warn().Str("command", cmd.CommandName()).Str("principal", principal.Subject).Dur("duration", time.Since(start)).Err(err).Msg("command failed")
+			} else {
+				logging.Ctx(ctx).Info().Str("command", cmd.CommandName()).Str("principal", principal.Subject).Dur("duration", time.Since(start)).Msg("command handled")
+			}
+			return result, err
+		}
+	}
+}
+
+// TracingMiddleware wraps every command in a span
+func TracingMiddleware() Middleware {
+	tracer := otel.Tracer("order-service/command")
+	return func(next Handler) Handler {
+		return func(ctx context.Context, cmd Command) (interface{}, error) {
+			ctx, span := tracer.Start(ctx, "command "+cmd.CommandName())
+			defer span.End()
+			span.SetAttributes(attribute.String("command.name", cmd.CommandName()))
+			if principal, ok := auth.PrincipalFrom(ctx); ok {
+				span.SetAttributes(attribute.String("enduser.id", principal.Subject))
+				if principal.APIKeyID != 0 {
+					span.SetAttributes(attribute.Int64("api_key.id", int64(principal.APIKeyID)))
+				}
+			}
+
+			result, err := next(ctx, cmd)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return result, err
+		}
+	}
+}
+
+// TransactionMiddleware runs each command in a single transaction
+func TransactionMiddleware(transactions TransactionManager) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, cmd Command) (interface{}, error) {
+			var result interface{}
+			err := transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+				var err error
+				result, err = next(ctx, cmd)
+				return err
+			})
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+	}
+}
+
+// AuthorizationMiddleware rejects commands the authorizer denies
+func AuthorizationMiddleware(authorizer Authorizer) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, cmd Command) (interface{}, error) {
+			if err := authorizer.Authorize(ctx, cmd); err != nil {
+				return nil, err
+			}
+			return next(ctx, cmd)
+		}
+	}
+}
+
+// AllowAll is an Authorizer that permits every command
+type AllowAll struct{}
+
+func (AllowAll) Authorize(context.Context, Command) error {
+	return nil
+}
@@ -0,0 +1,36 @@
+package command
+
+import (
+	"context"
+	"order-service/internal/application/dto"
+	"order-service/internal/application/validation"
+	"order-service/internal/domain/services"
+)
+
+// RegisterOrderHandlers registers the order command handlers, which run the domain logic
+func RegisterOrderHandlers(bus *Bus, orders *services.OrderService) {
+	Register(bus, func(ctx context.Context, cmd CreateOrder) (dto.OrderResponse, error) {
+		return orders.CreateOrder(ctx, cmd.Order)
+	})
+	Register(bus, func(ctx context.Context, cmd AddItem) (*dto.OrderResponse, error) {
+		return orders.AddItemToOrder(ctx, cmd.ID, cmd.Item)
+	})
+	Register(bus, func(ctx context.Context, cmd CancelOrder) (*dto.OrderResponse, error) {
+		return orders.CancelOrder(ctx, cmd.ID)
+	})
+	Register(bus, func(ctx context.Context, cmd UpdateOrder) (*dto.OrderResponse, error) {
+		return orders.UpdateOrder(ctx, cmd.ID, cmd.Order)
+	})
+	Register(bus, func(ctx context.Context, cmd PatchOrder) (*dto.OrderResponse, error) {
+		// The patched fields are validated like a PUT body before the domain sees them
+		return orders.PatchOrder(ctx, cmd.ID, func(current dto.OrderUpdateDto) (dto.OrderUpdateDto, error) {
+			if err := cmd.Patch.Apply(&current); err != nil {
+				return current, err
+			}
+			return current, validation.Validate(current)
+		})
+	})
+	Register(bus, func(ctx context.Context, cmd DeleteOrder) (struct{}, error) {
+		return struct{}{}, orders.DeleteOrder(ctx, cmd.ID)
+	})
+}
@@ -0,0 +1,102 @@
+package command
+
+import (
+	"order-service/internal/application/dto"
+	"order-service/internal/application/patch"
+	"order-service/internal/application/validation"
+	"order-service/internal/domain/domainerr"
+)
+
+// CreateOrder places a new order
+type CreateOrder struct {
+	Order dto.OrderCreateDto
+}
+
+func (CreateOrder) CommandName() string { return "CreateOrder" }
+
+func (c CreateOrder) Validate() error {
+	return validation.Validate(c.Order)
+}
+
+// AddItem adds an item to an existing order
+type AddItem struct {
+	ID   uint
+	Item dto.OrderItemDto
+}
+
+func (AddItem) CommandName() string { return "AddItem" }
+
+func (c AddItem) TargetOrderID() uint { return c.ID }
+
+func (c AddItem) Validate() error {
+	if c.ID == 0 {
+		return domainerr.Validation("order_required", "order is required")
+	}
+	return validation.Validate(c.Item)
+}
+
+// CancelOrder cancels a pending order
+type CancelOrder struct {
+	ID uint
+}
+
+func (CancelOrder) CommandName() string { return "CancelOrder" }
+
+func (c CancelOrder) TargetOrderID() uint { return c.ID }
+
+func (c CancelOrder) Validate() error {
+	if c.ID == 0 {
+		return domainerr.Validation("order_required", "order is required")
+	}
+	return nil
+}
+
+// UpdateOrder replaces the mutable fields of a pending order
+type UpdateOrder struct {
+	ID    uint
+	Order dto.OrderUpdateDto
+}
+
+func (UpdateOrder) CommandName() string { return "UpdateOrder" }
+
+func (c UpdateOrder) TargetOrderID() uint { return c.ID }
+
+func (c UpdateOrder) Validate() error {
+	if c.ID == 0 {
+		return domainerr.Validation("order_required", "order is required")
+	}
+	return validation.Validate(c.Order)
+}
+
+// PatchOrder applies a merge patch or JSON Patch to the mutable fields of a pending order
+type PatchOrder struct {
+	ID    uint
+	Patch patch.Patch
+}
+
+func (PatchOrder) CommandName() string { return "PatchOrder" }
+
+func (c PatchOrder) TargetOrderID() uint { return c.ID }
+
+func (c PatchOrder) Validate() error {
+	if c.ID == 0 {
+		return domainerr.Validation("order_required", "order is required")
+	}
+	return nil
+}
+
+// DeleteOrder soft-deletes an order
+type DeleteOrder struct {
+	ID uint
+}
+
+func (DeleteOrder) CommandName() string { return "DeleteOrder" }
+
+func (c DeleteOrder) TargetOrderID() uint { return c.ID }
+
+func (c DeleteOrder) Validate() error {
+	if c.ID == 0 {
+		return domainerr.Validation("order_required", "order is required")
+	}
+	return nil
+}
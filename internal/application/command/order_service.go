@@ -0,0 +1,66 @@
+package command
+
+import (
+	"context"
+	"order-service/internal/application/dto"
+	"order-service/internal/application/patch"
+	"order-service/internal/application/policy"
+	appservices "order-service/internal/application/services"
+	"order-service/internal/domain/services"
+)
+
+// OrderService is the application OrderService as a thin facade over the command bus.
+// Reads are not commands and go straight to the domain service, authorized by the policy.
+type OrderService struct {
+	bus        *Bus
+	orders     *services.OrderService
+	authorizer *policy.Authorizer
+}
+
+func NewOrderService(bus *Bus, orders *services.OrderService, authorizer *policy.Authorizer) appservices.OrderService {
+	return &OrderService{bus: bus, orders: orders, authorizer: authorizer}
+}
+
+func (s *OrderService) CreateOrder(ctx context.Context, order dto.OrderCreateDto) (dto.OrderResponse, error) {
+	return Dispatch[dto.OrderResponse](ctx, s.bus, CreateOrder{Order: order})
+}
+
+func (s *OrderService) AddItemToOrder(ctx context.Context, id uint, item dto.OrderItemDto) (*dto.OrderResponse, error) {
+	return Dispatch[*dto.OrderResponse](ctx, s.bus, AddItem{ID: id, Item: item})
+}
+
+func (s *OrderService) CancelOrder(ctx context.Context, id uint) (*dto.OrderResponse, error) {
+	return Dispatch[*dto.OrderResponse](ctx, s.bus, CancelOrder{ID: id})
+}
+
+func (s *OrderService) UpdateOrder(ctx context.Context, id uint, order dto.OrderUpdateDto) (*dto.OrderResponse, error) {
+	return Dispatch[*dto.OrderResponse](ctx, s.bus, UpdateOrder{ID: id, Order: order})
+}
+
+func (s *OrderService) PatchOrder(ctx context.Context, id uint, orderPatch patch.Patch) (*dto.OrderResponse, error) {
+	return Dispatch[*dto.OrderResponse](ctx, s.bus, PatchOrder{ID: id, Patch: orderPatch})
+}
+
+func (s *OrderService) DeleteOrder(ctx context.Context, id uint) error {
+	_, err := s.bus.Dispatch(ctx, DeleteOrder{ID: id})
+	return err
+}
+
+func (s *OrderService) GetOrderByID(ctx context.Context, id uint) (*dto.OrderResponse, error) {
+	order, err := s.orders.GetOrderByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizer.Authorize(ctx, policy.ReadOrder, policy.Order(id, order.CustomerID)); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// SearchOrders searches every customer's orders, so the policy must grant it on every order
+func (s *OrderService) SearchOrders(ctx context.Context, text string, page, pageSize int) (dto.OrderSearchResponse, error) {
+	if err := s.authorizer.Authorize(ctx, policy.SearchOrders, policy.Order(0, 0)); err != nil {
+		return dto.OrderSearchResponse{}, err
+	}
+	return s.orders.SearchOrders(ctx, text, page, pageSize)
+}
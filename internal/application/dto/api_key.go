@@ -0,0 +1,34 @@
+package dto
+
+import "time"
+
+// APIKeyCreateDto is the request to issue an API key
+type APIKeyCreateDto struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// APIKeyRotateDto is the request to rotate an API key. GracePeriod is a duration such as "24h"
+// during which the old key keeps working; it stops at once when the period is empty.
+type APIKeyRotateDto struct {
+	GracePeriod string `json:"grace_period"`
+}
+
+// APIKeyResponse describes an API key without its secret
+type APIKeyResponse struct {
+	ID            uint       `json:"id"`
+	Name          string     `json:"name"`
+	Prefix        string     `json:"prefix"`
+	Scopes        []string   `json:"scopes"`
+	CreatedAt     time.Time  `json:"created_at"`
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	RotatedFromID *uint      `json:"rotated_from_id,omitempty"`
+}
+
+// IssuedAPIKeyResponse is a newly issued API key. Key is only ever shown in this response.
+type IssuedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+// CustomerOrderHistoryResponse represents one order in a customer's history
+type CustomerOrderHistoryResponse struct {
+	OrderID     string    `json:"order_id"`
+	ItemCount   int       `json:"item_count"`
+	TotalAmount float64   `json:"total_amount"`
+	OrderDate   time.Time `json:"order_date"`
+}
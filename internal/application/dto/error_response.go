@@ -1,6 +0,0 @@
-package dto
-
-// ErrorResponse represents an error response
-type ErrorResponse struct {
-	Error string `json:"error"`
-}
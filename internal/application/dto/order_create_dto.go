@@ -2,17 +2,21 @@ package dto
 
 import "time"
 
+// OrderCreateDto is the request to create an order. Its validate tags are checked by the
+// validation package before the order reaches the domain.
 type OrderCreateDto struct {
-	OrderID    string
-	CustomerID uint
-	OrderItems []OrderItemDto
-	OrderDate  time.Time
+	OrderID      string         `validate:"required,max=64"`
+	CustomerID   uint           `validate:"required"`
+	CustomerName string         `validate:"max=255"`
+	OrderItems   []OrderItemDto `validate:"required"`
+	OrderDate    time.Time      `validate:"required"`
 }
 
 type OrderItemDto struct {
-	ProductID uint
-	Quantity  int
-	Price     float64
+	ProductID   uint    `validate:"required"`
+	ProductName string  `validate:"max=255"`
+	Quantity    int     `validate:"gt=0"`
+	Price       float64 `validate:"gt=0"`
 }
 
 // NewOrderCreateDto is a constructor for OrderCreateDto
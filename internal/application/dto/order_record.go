@@ -0,0 +1,42 @@
+package dto
+
+import "time"
+
+// OrderRecord is one order in a bulk import or export. Status and TotalAmount are
+// exported for reference and ignored on import, where the total is recomputed from the items.
+type OrderRecord struct {
+	OrderID      string              `json:"order_id"`
+	CustomerID   uint                `json:"customer_id"`
+	CustomerName string              `json:"customer_name,omitempty"`
+	Status       string              `json:"status,omitempty"`
+	OrderDate    time.Time           `json:"order_date"`
+	TotalAmount  float64             `json:"total_amount,omitempty"`
+	Items        []OrderItemResponse `json:"items"`
+}
+
+// ImportRow is one order read from an import, or the reason it could not be read.
+// Row is the line the order starts on.
+type ImportRow struct {
+	Row    int
+	Record OrderRecord
+	Err    error
+}
+
+// ImportRowError reports why the order starting at Row was not imported, or was imported without its event
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	OrderID string `json:"order_id,omitempty"`
+	Error   string `json:"error"`
+}
+
+// ImportReport summarises a bulk import
+type ImportReport struct {
+	Imported int              `json:"imported"`
+	Failed   int              `json:"failed"`
+	Errors   []ImportRowError `json:"errors"`
+	// Unpublished lists imported orders whose created event could not be published. They are
+	// counted in Imported.
+	Unpublished []ImportRowError `json:"unpublished,omitempty"`
+	// Truncated is set when more rows failed or went unpublished than are listed
+	Truncated bool `json:"truncated,omitempty"`
+}
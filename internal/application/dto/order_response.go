@@ -1,16 +1,25 @@
 package dto
 
-// OrderResponse represents an order response
+import "time"
+
+// OrderResponse is an order as returned by the order services. Each API version maps it to its
+// own response shape, so fields can be added here without changing what clients receive.
 type OrderResponse struct {
-	OrderID     string              `json:"order_id"`
-	CustomerID  uint                `json:"customer_id"`
-	Items       []OrderItemResponse `json:"items"`
-	TotalAmount float64             `json:"total_amount"`
+	OrderID      string              `json:"order_id"`
+	CustomerID   uint                `json:"customer_id"`
+	CustomerName string              `json:"customer_name,omitempty"`
+	Items        []OrderItemResponse `json:"items"`
+	TotalAmount  float64             `json:"total_amount"`
+	Status       string              `json:"status"`
+	OrderDate    time.Time           `json:"order_date"`
+	Version      uint                `json:"version"`
+	UpdatedAt    time.Time           `json:"updated_at"`
 }
 
 // OrderItemResponse represents an order item response
 type OrderItemResponse struct {
-	ProductID uint    `json:"product_id"`
-	Quantity  int     `json:"quantity"`
-	Price     float64 `json:"price"`
+	ProductID   uint    `json:"product_id"`
+	ProductName string  `json:"product_name,omitempty"`
+	Quantity    int     `json:"quantity"`
+	Price       float64 `json:"price"`
 }
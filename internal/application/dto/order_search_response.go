@@ -0,0 +1,15 @@
+package dto
+
+// OrderSearchResponse represents one page of ranked order search results
+type OrderSearchResponse struct {
+	Results  []OrderSearchResult `json:"results"`
+	Total    int64               `json:"total"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"page_size"`
+}
+
+// OrderSearchResult represents a matching order and its relevance
+type OrderSearchResult struct {
+	Order OrderResponse `json:"order"`
+	Rank  float64       `json:"rank"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+// OrderUpdateDto holds the mutable fields of an order. PUT replaces all of them and PATCH
+// documents are applied to them.
+type OrderUpdateDto struct {
+	CustomerName string         `validate:"max=255"`
+	OrderItems   []OrderItemDto `validate:"required"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+// ProblemDetails is an RFC 7807 application/problem+json error response. Code is a stable
+// machine-readable error code, CorrelationID identifies the request in logs and traces, and
+// Errors lists every invalid field when the request failed validation.
+type ProblemDetails struct {
+	Type          string       `json:"type" example:"/problems/order_not_found"`
+	Title         string       `json:"title" example:"Not Found"`
+	Status        int          `json:"status" example:"404"`
+	Detail        string       `json:"detail,omitempty" example:"order not found"`
+	Instance      string       `json:"instance,omitempty" example:"/orders/42"`
+	Code          string       `json:"code" example:"order_not_found"`
+	CorrelationID string       `json:"correlation_id,omitempty"`
+	Errors        []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError describes one invalid field of a request
+type FieldError struct {
+	Field   string `json:"field" example:"order_items[0].quantity"`
+	Message string `json:"message" example:"must be greater than zero"`
+}
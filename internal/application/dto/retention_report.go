@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+// RetentionReport summarises one retention run
+type RetentionReport struct {
+	DryRun   bool      `json:"dry_run"`
+	Action   string    `json:"action"`
+	Cutoff   time.Time `json:"cutoff"`
+	Matched  int       `json:"matched"`
+	Purged   int       `json:"purged"`
+	OrderIDs []string  `json:"order_ids"`
+}
+
+// LegalHoldDto is the request body for placing or releasing a legal hold
+type LegalHoldDto struct {
+	Hold bool `json:"hold"`
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"order-service/internal/application/auth"
+	"order-service/internal/application/dto"
+	"order-service/internal/application/policy"
+	"order-service/internal/application/repository"
+	"order-service/internal/application/services"
+	"order-service/internal/application/validation"
+	"order-service/internal/domain/domainerr"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// APIKeyHandler handles the admin requests that manage API keys
+type APIKeyHandler struct {
+	service services.APIKeyService
+}
+
+// NewAPIKeyHandler initializes the API key handler with routes, which the policy limits to admins
+func NewAPIKeyHandler(router fiber.Router, service services.APIKeyService, authorizer *policy.Authorizer) {
+	handler := &APIKeyHandler{service: service}
+	admin := router.Group("/admin/api-keys", Authorize(authorizer, policy.ManageAPIKeys))
+	admin.Post("/", handler.CreateAPIKey)
+	admin.Get("/", handler.ListAPIKeys)
+	admin.Post("/:id/rotate", handler.RotateAPIKey)
+	admin.Delete("/:id", handler.RevokeAPIKey)
+}
+
+// CreateAPIKey godoc
+// @Summary Issue an API key
+// @Description Issue an API key for a service-to-service caller with the scopes orders:read, orders:write or admin. The key is only shown in this response.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param key body dto.APIKeyCreateDto true "API key"
+// @Success 201 {object} dto.IssuedAPIKeyResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /admin/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	var body dto.APIKeyCreateDto
+	if err := DecodeJSON(c, &body); err != nil {
+		return err
+	}
+	if err := validation.Validate(body); err != nil {
+		return err
+	}
+
+	issued, err := h.service.Create(c.UserContext(), body.Name, body.Scopes, body.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	return sendIssuedAPIKey(c, issued)
+}
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Description List every API key, including expired and revoked ones, without their secrets
+// @Tags admin
+// @Produce json
+// @Success 200 {array} dto.APIKeyResponse
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /admin/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *fiber.Ctx) error {
+	keys, err := h.service.List(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	response := make([]dto.APIKeyResponse, len(keys))
+	for i, key := range keys {
+		response[i] = newAPIKeyResponse(key)
+	}
+	return c.JSON(response)
+}
+
+// RotateAPIKey godoc
+// @Summary Rotate an API key
+// @Description Issue a replacement with the same name, scopes and expiry. The old key keeps working for the grace period.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "API key ID"
+// @Param rotation body dto.APIKeyRotateDto false "Rotation"
+// @Success 201 {object} dto.IssuedAPIKeyResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /admin/api-keys/{id}/rotate [post]
+func (h *APIKeyHandler) RotateAPIKey(c *fiber.Ctx) error {
+	id, err := ParseID(c, "id", "id")
+	if err != nil {
+		return err
+	}
+
+	var body dto.APIKeyRotateDto
+	if len(c.Body()) > 0 {
+		if err := DecodeJSON(c, &body); err != nil {
+			return err
+		}
+	}
+	var gracePeriod time.Duration
+	if body.GracePeriod != "" {
+		if gracePeriod, err = time.ParseDuration(body.GracePeriod); err != nil {
+			return domainerr.Validation("invalid_grace_period", "grace period must be a duration such as 24h").
+				WithFields(domainerr.FieldError{Field: "GracePeriod", Message: "must be a duration such as 24h"}).
+				Wrap(err)
+		}
+	}
+
+	issued, err := h.service.Rotate(c.UserContext(), id, gracePeriod)
+	if err != nil {
+		return err
+	}
+	return sendIssuedAPIKey(c, issued)
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Description The key stops authenticating requests at once
+// @Tags admin
+// @Param id path int true "API key ID"
+// @Success 204
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /admin/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	id, err := ParseID(c, "id", "id")
+	if err != nil {
+		return err
+	}
+	if err := h.service.Revoke(c.UserContext(), id); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func newAPIKeyResponse(key repository.APIKey) dto.APIKeyResponse {
+	return dto.APIKeyResponse{
+		ID:            key.ID,
+		Name:          key.Name,
+		Prefix:        key.Prefix,
+		Scopes:        key.Scopes,
+		CreatedAt:     key.CreatedAt,
+		ExpiresAt:     key.ExpiresAt,
+		RevokedAt:     key.RevokedAt,
+		RotatedFromID: key.RotatedFromID,
+	}
+}
+
+// sendIssuedAPIKey answers with a key's only copy of its secret, which caches and the idempotency
+// store must not keep
+func sendIssuedAPIKey(c *fiber.Ctx, issued auth.IssuedAPIKey) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(fiber.StatusCreated).JSON(newIssuedAPIKeyResponse(issued))
+}
+
+func newIssuedAPIKeyResponse(issued auth.IssuedAPIKey) dto.IssuedAPIKeyResponse {
+	return dto.IssuedAPIKeyResponse{APIKeyResponse: newAPIKeyResponse(issued.APIKey), Key: issued.Secret}
+}
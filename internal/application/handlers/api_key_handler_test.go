@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"order-service/internal/application/auth"
+	"order-service/internal/application/dto"
+	"order-service/internal/application/policy"
+	"order-service/internal/infrastructure/persistence"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAPIKeyHandler tests that admins issue keys that then authenticate, until they are revoked
+func TestAPIKeyHandler(t *testing.T) {
+	keys := auth.NewAPIKeys(persistence.NewInMemoryAPIKeyStore())
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(NewAuthMiddleware(Authentication{
+		Verifier: stubVerifier{
+			"admin-token":    {Subject: "root", Roles: []string{auth.RoleAdmin}},
+			"customer-token": {Subject: "42", Roles: []string{auth.RoleCustomer}},
+		},
+		APIKeys: keys,
+	}))
+	authorizer := policy.NewAuthorizer(policy.Default(), policy.LogAuditor{})
+	NewAPIKeyHandler(app, keys, authorizer)
+	app.Get("/whoami", Authorize(authorizer, policy.RetentionReport), func(c *fiber.Ctx) error {
+		return c.SendString(ClientID(c))
+	})
+	app.Post("/orders/import", Authorize(authorizer, policy.ImportOrders), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	request := func(method, path, body string, header, value string) *http.Response {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set(header, value)
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := request("POST", "/admin/api-keys", `{"name":"warehouse","scopes":["orders:read"]}`, fiber.HeaderAuthorization, "Bearer customer-token")
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp = request("POST", "/admin/api-keys", `{"name":"warehouse","scopes":["orders:read"]}`, fiber.HeaderAuthorization, "Bearer admin-token")
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "no-store", resp.Header.Get(fiber.HeaderCacheControl), "the secret is never cached")
+	var issued dto.IssuedAPIKeyResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&issued))
+	assert.Equal(t, "warehouse", issued.Name)
+	assert.NotEmpty(t, issued.Key)
+	id := strconv.FormatUint(uint64(issued.ID), 10)
+
+	resp = request("GET", "/admin/api-keys", "", fiber.HeaderAuthorization, "Bearer admin-token")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var listed []map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&listed))
+	require.Len(t, listed, 1)
+	assert.NotContains(t, listed[0], "key", "secrets are never listed")
+
+	resp = request("GET", "/whoami", "", APIKeyHeader, issued.Key)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "apikey:"+id, string(body), "requests are attributed to the key")
+
+	resp = request("POST", "/orders/import", "", APIKeyHeader, issued.Key)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode, "the key lacks orders:write")
+
+	resp = request("POST", "/admin/api-keys", "", APIKeyHeader, issued.Key)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode, "the key is not an admin key")
+
+	resp = request("DELETE", "/admin/api-keys/"+id, "", fiber.HeaderAuthorization, "Bearer admin-token")
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp = request("GET", "/whoami", "", APIKeyHeader, issued.Key)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
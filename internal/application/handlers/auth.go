@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"order-service/internal/application/auth"
+	"order-service/internal/application/policy"
+	"order-service/internal/domain/domainerr"
+	"order-service/internal/infrastructure/logging"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// APIKeyHeader carries the API key of service-to-service callers
+const APIKeyHeader = "X-API-Key"
+
+// ErrInvalidToken is returned for a bearer token that is malformed, expired or not signed by a trusted key
+var ErrInvalidToken = domainerr.Unauthorized("invalid_token", "bearer token is invalid or expired")
+
+// TokenVerifier checks a bearer token and returns the principal it was issued to
+type TokenVerifier interface {
+	Verify(token string) (auth.Principal, error)
+}
+
+// APIKeyAuthenticator checks an API key and returns the principal of its caller
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (auth.Principal, error)
+}
+
+// Authentication configures the authentication middleware
+type Authentication struct {
+	Verifier TokenVerifier
+	// APIKeys authenticates requests carrying an X-API-Key header, when set
+	APIKeys APIKeyAuthenticator
+	// Next skips the middleware, leaving the request anonymous, when it returns true
+	Next func(c *fiber.Ctx) bool
+}
+
+// NewAuthMiddleware requires an RFC 6750 bearer token, or an API key when they are enabled, on every
+// request and stores the principal it was issued to in the user context. The principal's subject
+// also identifies the client for rate limits.
+func NewAuthMiddleware(authentication Authentication) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if authentication.Next != nil && authentication.Next(c) {
+			return c.Next()
+		}
+
+		if key := c.Get(APIKeyHeader); key != "" && authentication.APIKeys != nil {
+			principal, err := authentication.APIKeys.Authenticate(c.UserContext(), key)
+			if err != nil {
+				return err
+			}
+			return authenticated(c, principal)
+		}
+
+		scheme, token, _ := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+		if !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+			c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+			return auth.ErrUnauthenticated
+		}
+		principal, err := authentication.Verifier.Verify(strings.TrimSpace(token))
+		if err != nil {
+			c.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+			return ErrInvalidToken.Wrap(err)
+		}
+		return authenticated(c, principal)
+	}
+}
+
+// NewFixedPrincipalMiddleware treats every request as coming from principal. It stands in for
+// bearer authentication where there is no identity provider, such as local development.
+func NewFixedPrincipalMiddleware(principal auth.Principal) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return authenticated(c, principal)
+	}
+}
+
+// Authorize refuses the route to callers the policy does not allow action on the system
+func Authorize(authorizer *policy.Authorizer, action policy.Action) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := authorizer.Authorize(c.UserContext(), action, policy.System()); err != nil {
+			return err
+		}
+		return c.Next()
+	}
+}
+
+// authenticated attaches principal to the request, its log lines and its span, so logs and traces show who made it
+func authenticated(c *fiber.Ctx, principal auth.Principal) error {
+	ctx := auth.WithPrincipal(c.UserContext(), principal)
+	c.SetUserContext(logging.WithPrincipal(ctx, principal.Subject, principal.APIKeyID))
+	SetClientID(c, principal.Subject)
+
+	span := trace.SpanFromContext(c.UserContext())
+	span.SetAttributes(attribute.String("enduser.id", principal.Subject))
+	if principal.APIKeyID != 0 {
+		span.SetAttributes(attribute.Int64("api_key.id", int64(principal.APIKeyID)))
+	}
+	return c.Next()
+}
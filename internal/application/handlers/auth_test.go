@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"order-service/internal/application/auth"
+	"order-service/internal/application/policy"
+	"order-service/internal/infrastructure/logging"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/phuslu/log"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubVerifier map[string]auth.Principal
+
+func (s stubVerifier) Verify(token string) (auth.Principal, error) {
+	principal, ok := s[token]
+	if !ok {
+		return auth.Principal{}, errors.New("unknown token")
+	}
+	return principal, nil
+}
+
+// TestAuthMiddleware tests that bearer tokens become the principal of the request
+func TestAuthMiddleware(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(NewAuthMiddleware(Authentication{
+		Verifier: stubVerifier{
+			"customer-token": {Subject: "42", Roles: []string{auth.RoleCustomer}},
+			"staff-token":    {Subject: "alice", Roles: []string{auth.RoleStaff}},
+		},
+		Next: func(c *fiber.Ctx) bool { return strings.HasPrefix(c.Path(), "/swagger") },
+	}))
+	app.Get("/whoami", func(c *fiber.Ctx) error {
+		principal, _ := auth.PrincipalFrom(c.UserContext())
+		return c.SendString(principal.Subject + " " + ClientID(c))
+	})
+	app.Get("/retention/report", Authorize(policy.NewAuthorizer(policy.Default(), policy.LogAuditor{}), policy.RetentionReport), func(c *fiber.Ctx) error { return c.SendString("report") })
+	app.Get("/swagger/index.html", func(c *fiber.Ctx) error { return c.SendString("docs") })
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		status        int
+		body          string
+		challenge     string
+	}{
+		{"customer", "/whoami", "Bearer customer-token", http.StatusOK, "42 42", ""},
+		{"scheme is case-insensitive", "/whoami", "bearer staff-token", http.StatusOK, "alice alice", ""},
+		{"missing token", "/whoami", "", http.StatusUnauthorized, "", "Bearer"},
+		{"basic credentials", "/whoami", "Basic YWxpY2U6c2VjcmV0", http.StatusUnauthorized, "", "Bearer"},
+		{"invalid token", "/whoami", "Bearer forged", http.StatusUnauthorized, "", `Bearer error="invalid_token"`},
+		{"staff route as customer", "/retention/report", "Bearer customer-token", http.StatusForbidden, "", ""},
+		{"staff route as staff", "/retention/report", "Bearer staff-token", http.StatusOK, "report", ""},
+		{"skipped path", "/swagger/index.html", "", http.StatusOK, "docs", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set(fiber.HeaderAuthorization, tt.authorization)
+			}
+			resp, err := app.Test(req, -1)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, tt.challenge, resp.Header.Get(fiber.HeaderWWWAuthenticate))
+			if tt.body != "" {
+				body, err := io.ReadAll(resp.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.body, string(body))
+			}
+		})
+	}
+}
+
+// TestAuthenticatedLogsPrincipal tests that log lines of an authenticated request name its caller and API key
+func TestAuthenticatedLogsPrincipal(t *testing.T) {
+	var out bytes.Buffer
+	defer func(logger log.Logger) { logging.Logger = logger }(logging.Logger)
+	logging.Logger = log.Logger{Writer: &log.IOWriter{Writer: &out}}
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(NewRequestIDMiddleware())
+	app.Use(NewFixedPrincipalMiddleware(auth.Principal{Subject: "billing", Roles: []string{auth.RoleService}, APIKeyID: 7}))
+	app.Get("/log", func(c *fiber.Ctx) error {
+		logging.Ctx(c.UserContext()).Info().Msg("handled")
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	req := httptest.NewRequest("GET", "/log", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	var line map[string]interface{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &line))
+	assert.Equal(t, "req-1", line["request_id"])
+	assert.Equal(t, "billing", line["principal"])
+	assert.Equal(t, float64(7), line["api_key_id"])
+}
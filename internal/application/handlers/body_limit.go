@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"io"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewBodyLimitMiddleware reads the body of every request up front and rejects bodies over limit
+// bytes with 413. The server streams request bodies so bulk imports are processed as they
+// upload, which also turns off Fiber's BodyLimit; this puts the limit back for every request
+// that next does not skip.
+func NewBodyLimitMiddleware(limit int, next func(c *fiber.Ctx) bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if next != nil && next(c) {
+			return c.Next()
+		}
+		if c.Request().Header.ContentLength() > limit {
+			return tooLarge(c)
+		}
+
+		stream := c.Context().RequestBodyStream()
+		if stream == nil {
+			return c.Next()
+		}
+		// Chunked bodies have no length up front, so read one byte past the limit to detect them
+		body, err := io.ReadAll(io.LimitReader(stream, int64(limit)+1))
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+		if len(body) > limit {
+			return tooLarge(c)
+		}
+		c.Request().SetBody(body)
+		return c.Next()
+	}
+}
+
+// tooLarge closes the connection, since the rest of the body is never read
+func tooLarge(c *fiber.Ctx) error {
+	c.Context().SetConnectionClose()
+	return fiber.ErrRequestEntityTooLarge
+}
+
+// IsBulkImport reports whether c uploads a bulk import, the only request whose body is streamed
+func IsBulkImport(c *fiber.Ctx) bool {
+	return c.Method() == fiber.MethodPost && strings.HasSuffix(c.Path(), "/orders/import")
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBodyLimitMiddleware tests that bodies over the limit are rejected, with or without a length, except on imports
+func TestBodyLimitMiddleware(t *testing.T) {
+	app := fiber.New(fiber.Config{StreamRequestBody: true, ErrorHandler: ErrorHandler})
+	app.Use(NewBodyLimitMiddleware(16, IsBulkImport))
+	received := func(c *fiber.Ctx) error {
+		body := c.Body()
+		if stream := c.Context().RequestBodyStream(); stream != nil {
+			body, _ = io.ReadAll(stream)
+		}
+		return c.SendString(strconv.Itoa(len(body)))
+	}
+	app.Post("/orders", received)
+	app.Post("/v2/orders/import", received)
+
+	tests := []struct {
+		name   string
+		path   string
+		body   io.Reader
+		status int
+		length string
+	}{
+		{"within the limit", "/orders", strings.NewReader(strings.Repeat("a", 16)), http.StatusOK, "16"},
+		{"over the limit", "/orders", strings.NewReader(strings.Repeat("a", 17)), http.StatusRequestEntityTooLarge, ""},
+		{"chunked within the limit", "/orders", io.MultiReader(strings.NewReader(strings.Repeat("a", 16))), http.StatusOK, "16"},
+		{"chunked over the limit", "/orders", io.MultiReader(strings.NewReader(strings.Repeat("a", 1<<20))), http.StatusRequestEntityTooLarge, ""},
+		{"import", "/v2/orders/import", io.MultiReader(strings.NewReader(strings.Repeat("a", 1<<20))), http.StatusOK, strconv.Itoa(1 << 20)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.path, tt.body)
+			if req.ContentLength < 0 {
+				req.TransferEncoding = []string{"chunked"}
+			}
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			if tt.length != "" {
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Equal(t, tt.length, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"order-service/internal/application/bulk"
+	"order-service/internal/application/dto"
+	"order-service/internal/application/policy"
+	"order-service/internal/application/services"
+	"order-service/internal/domain/domainerr"
+	"order-service/internal/infrastructure/logging"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BulkHandler handles bulk order import and export requests
+type BulkHandler struct {
+	service services.BulkOrderService
+}
+
+// NewBulkHandler initializes the bulk handler with routes. It must be registered before
+// the order handlers so /orders/export is not taken for an order ID. Bulk routes span every
+// customer's orders and are limited by the policy to staff.
+func NewBulkHandler(router fiber.Router, service services.BulkOrderService, authorizer *policy.Authorizer) {
+	handler := &BulkHandler{service: service}
+	router.Post("/orders/import", Authorize(authorizer, policy.ImportOrders), handler.ImportOrders)
+	router.Get("/orders/export", Authorize(authorizer, policy.ExportOrders), handler.ExportOrders)
+}
+
+// ImportOrders godoc
+// @Summary Import orders
+// @Description Create orders in bulk from CSV (one row per item, rows of one order share its order_id) or JSON Lines (one order per line). Every order is validated; rejected rows are listed in the report and the rest are imported.
+// @Tags bulk
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce json
+// @Param format query string false "csv or jsonl, defaults to the Content-Type"
+// @Param Idempotency-Key header string false "Retries with the same key replay the first response"
+// @Success 200 {object} dto.ImportReport
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /orders/import [post]
+func (h *BulkHandler) ImportOrders(c *fiber.Ctx) error {
+	format, err := bulk.ParseFormat(c.Query("format", c.Get(fiber.HeaderContentType)))
+	if err != nil {
+		return domainerr.Validation("unsupported_format", err.Error())
+	}
+
+	// Read the body as it arrives when the server streams request bodies
+	var body io.Reader = c.Context().RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Body())
+	}
+
+	// Errors reading the upload are the client's, any other failure is ours
+	reader := bulk.NewReader(format, body)
+	var readErr error
+	next := func() (dto.ImportRow, error) {
+		row, err := reader.Next()
+		if err != nil && !errors.Is(err, io.EOF) {
+			readErr = err
+		}
+		return row, err
+	}
+
+	report, err := h.service.Import(c.UserContext(), next)
+	if readErr != nil {
+		return domainerr.Validation("invalid_import", readErr.Error())
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(report)
+}
+
+// ExportOrders godoc
+// @Summary Export orders
+// @Description Stream every order with its items as CSV or JSON Lines
+// @Tags bulk
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param format query string false "csv or jsonl" default(jsonl)
+// @Success 200 {string} string
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /orders/export [get]
+func (h *BulkHandler) ExportOrders(c *fiber.Ctx) error {
+	format, err := bulk.ParseFormat(c.Query("format", string(bulk.FormatJSONLines)))
+	if err != nil {
+		return domainerr.Validation("unsupported_format", err.Error())
+	}
+
+	ctx := c.UserContext()
+	c.Set(fiber.HeaderContentType, format.ContentType())
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="orders.`+string(format)+`"`)
+	// The status is sent before the first order is read, so failures can only end the stream early
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		writer := bulk.NewWriter(format, w)
+		err := h.service.Export(ctx, writer.Write)
+		if flushErr := writer.Flush(); err == nil {
+			err = flushErr
+		}
+		if err != nil {
+			logging.Ctx(ctx).Error().Msgf("order export failed: %v", err)
+		}
+	})
+	return nil
+}
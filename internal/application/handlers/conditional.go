@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"order-service/internal/application/dto"
+	"order-service/internal/domain/repositories"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Validators identify a representation so clients can make conditional requests (RFC 9110 section 8.8)
+type Validators struct {
+	ETag         string
+	LastModified time.Time
+}
+
+// OrderValidators returns a strong ETag from the order version and Last-Modified from UpdatedAt
+func OrderValidators(order dto.OrderResponse) Validators {
+	return Validators{ETag: fmt.Sprintf(`"%d"`, order.Version), LastModified: order.UpdatedAt}
+}
+
+// ListValidators returns a strong ETag that changes whenever an order in the list is saved or the
+// list gains or loses an order, and Last-Modified from the most recent UpdatedAt
+func ListValidators(orders []dto.OrderResponse) Validators {
+	var validators Validators
+	hash := sha256.New()
+	for _, order := range orders {
+		fmt.Fprintf(hash, "%s:%d\n", order.OrderID, order.Version)
+		if order.UpdatedAt.After(validators.LastModified) {
+			validators.LastModified = order.UpdatedAt
+		}
+	}
+	validators.ETag = fmt.Sprintf(`"%x"`, hash.Sum(nil)[:16])
+	return validators
+}
+
+// Set sets the ETag and Last-Modified response headers
+func (v Validators) Set(c *fiber.Ctx) {
+	c.Set(fiber.HeaderETag, v.ETag)
+	if !v.LastModified.IsZero() {
+		c.Set(fiber.HeaderLastModified, v.LastModified.UTC().Format(http.TimeFormat))
+	}
+}
+
+// NotModified reports whether the client's cached copy is current. If-None-Match takes precedence
+// over If-Modified-Since and uses the weak comparison, as RFC 9110 section 13.2.2 requires.
+func (v Validators) NotModified(c *fiber.Ctx) bool {
+	if noneMatch := c.Get(fiber.HeaderIfNoneMatch); noneMatch != "" {
+		for _, tag := range strings.Split(noneMatch, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == v.ETag {
+				return true
+			}
+		}
+		return false
+	}
+
+	since, err := http.ParseTime(c.Get(fiber.HeaderIfModifiedSince))
+	if err != nil || v.LastModified.IsZero() {
+		return false
+	}
+	// Last-Modified is sent with second precision
+	return !v.LastModified.Truncate(time.Second).After(since)
+}
+
+// SendConditional answers with body and its validators, or with 304 Not Modified when the
+// client's cached copy is current
+func SendConditional(c *fiber.Ctx, validators Validators, body interface{}) error {
+	validators.Set(c)
+	if validators.NotModified(c) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	return c.Status(fiber.StatusOK).JSON(body)
+}
+
+// IfMatch returns the request context expecting the order versions named by If-Match, so a write
+// to an order that changed since the client read it fails with 412 Precondition Failed instead
+// of overwriting the change
+func IfMatch(c *fiber.Ctx) context.Context {
+	ifMatch := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if ifMatch == "" || ifMatch == "*" {
+		return c.UserContext()
+	}
+
+	var versions []uint
+	for _, tag := range strings.Split(ifMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		// If-Match uses the strong comparison, so weak tags never match
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+		if version, err := strconv.ParseUint(tag[1:len(tag)-1], 10, 32); err == nil {
+			versions = append(versions, uint(version))
+		}
+	}
+	return repositories.WithExpectedVersions(c.UserContext(), versions)
+}
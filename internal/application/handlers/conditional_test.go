@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"order-service/internal/application/dto"
+	"order-service/internal/domain/repositories"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSendConditional tests that current cached copies are answered with 304 Not Modified
+func TestSendConditional(t *testing.T) {
+	updatedAt := time.Date(2024, 10, 27, 14, 0, 0, 500, time.UTC)
+	validators := OrderValidators(dto.OrderResponse{OrderID: "Test-123", Version: 3, UpdatedAt: updatedAt})
+	assert.Equal(t, `"3"`, validators.ETag)
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Get("/orders/1", func(c *fiber.Ctx) error {
+		return SendConditional(c, validators, fiber.Map{"order_id": "Test-123"})
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		status int
+	}{
+		{"unconditional", "", "", http.StatusOK},
+		{"matching etag", fiber.HeaderIfNoneMatch, `"3"`, http.StatusNotModified},
+		{"weak matching etag", fiber.HeaderIfNoneMatch, `"2", W/"3"`, http.StatusNotModified},
+		{"stale etag", fiber.HeaderIfNoneMatch, `"2"`, http.StatusOK},
+		{"not modified since", fiber.HeaderIfModifiedSince, "Sun, 27 Oct 2024 14:00:00 GMT", http.StatusNotModified},
+		{"modified since", fiber.HeaderIfModifiedSince, "Sun, 27 Oct 2024 13:59:59 GMT", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/orders/1", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			resp, err := app.Test(req, -1)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, `"3"`, resp.Header.Get(fiber.HeaderETag))
+			assert.Equal(t, "Sun, 27 Oct 2024 14:00:00 GMT", resp.Header.Get(fiber.HeaderLastModified))
+		})
+	}
+}
+
+// TestListValidators tests that a list ETag changes when an order is saved, added or removed
+func TestListValidators(t *testing.T) {
+	orders := []dto.OrderResponse{{OrderID: "a", Version: 1}, {OrderID: "b", Version: 1}}
+	etag := ListValidators(orders).ETag
+
+	assert.Equal(t, etag, ListValidators([]dto.OrderResponse{{OrderID: "a", Version: 1}, {OrderID: "b", Version: 1}}).ETag)
+	assert.NotEqual(t, etag, ListValidators([]dto.OrderResponse{{OrderID: "a", Version: 2}, {OrderID: "b", Version: 1}}).ETag)
+	assert.NotEqual(t, etag, ListValidators(orders[:1]).ETag)
+}
+
+// TestIfMatch tests that writes only go ahead when If-Match names the current version
+func TestIfMatch(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Put("/orders/1", func(c *fiber.Ctx) error {
+		if err := repositories.CheckExpectedVersion(IfMatch(c), 3); err != nil {
+			return err
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	tests := []struct {
+		ifMatch string
+		status  int
+	}{
+		{"", http.StatusNoContent},
+		{"*", http.StatusNoContent},
+		{`"3"`, http.StatusNoContent},
+		{`"2", "3"`, http.StatusNoContent},
+		{`"2"`, http.StatusPreconditionFailed},
+		{`W/"3"`, http.StatusPreconditionFailed},
+		{"3", http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("PUT", "/orders/1", nil)
+		if tt.ifMatch != "" {
+			req.Header.Set(fiber.HeaderIfMatch, tt.ifMatch)
+		}
+		resp, err := app.Test(req, -1)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.status, resp.StatusCode, tt.ifMatch)
+	}
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"order-service/internal/application/policy"
+	"order-service/internal/domain/repositories"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ReadYourWritesHeader carries the read-your-writes token between client and server.
+// Successful writes return a signed token holding the time until which reads are pinned
+// to the primary; clients echo it back on follow-up requests. Tokens the server did not
+// sign are ignored. The value "always" pins the request unconditionally, but only for
+// callers the policy allows to pin reads to the primary.
+const ReadYourWritesHeader = "X-Read-Your-Writes"
+
+// ReadYourWrites configures the read-your-writes middleware
+type ReadYourWrites struct {
+	// Window is how long after a write its token pins reads to the primary
+	Window time.Duration
+	// Secret signs the tokens. Instances behind one load balancer must share it, or a token is
+	// only honoured by the instance that issued it.
+	Secret []byte
+	// Authorizer decides who may send "always"
+	Authorizer *policy.Authorizer
+}
+
+// NewReadYourWritesMiddleware pins requests to the primary database for a window after a write.
+// It must run after authentication, so "always" can be checked against the caller.
+func NewReadYourWritesMiddleware(config ReadYourWrites) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if config.pinned(c) {
+			c.SetUserContext(repositories.WithReadYourWrites(c.UserContext()))
+		}
+
+		err := c.Next()
+
+		if err == nil && isWrite(c.Method()) && c.Response().StatusCode() < fiber.StatusBadRequest {
+			c.Set(ReadYourWritesHeader, config.issue(time.Now().Add(config.Window)))
+		}
+		return err
+	}
+}
+
+func (r ReadYourWrites) pinned(c *fiber.Ctx) bool {
+	token := c.Get(ReadYourWritesHeader)
+	if token == "always" {
+		return r.Authorizer.Authorize(c.UserContext(), policy.PinPrimary, policy.System()) == nil
+	}
+	return r.valid(token)
+}
+
+// issue signs a token that pins reads until the given time
+func (r ReadYourWrites) issue(until time.Time) string {
+	value := strconv.FormatInt(until.UnixMilli(), 10)
+	return value + "." + r.sign(value)
+}
+
+// valid reports whether token was signed by the server and is still within its window
+func (r ReadYourWrites) valid(token string) bool {
+	value, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(r.sign(value))) {
+		return false
+	}
+
+	until, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false
+	}
+	remaining := time.Until(time.UnixMilli(until))
+	// Ignore tokens further in the future than the server would ever issue
+	return remaining > 0 && remaining <= r.Window
+}
+
+func (r ReadYourWrites) sign(value string) string {
+	mac := hmac.New(sha256.New, r.Secret)
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func isWrite(method string) bool {
+	switch method {
+	case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		return true
+	}
+	return false
+}
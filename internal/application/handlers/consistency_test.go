@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"io"
+	"net/http/httptest"
+	"order-service/internal/application/auth"
+	"order-service/internal/application/policy"
+	"order-service/internal/domain/repositories"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReadYourWritesMiddleware tests that writes issue a token and echoed tokens pin reads
+func TestReadYourWritesMiddleware(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(NewReadYourWritesMiddleware(ReadYourWrites{
+		Window:     5 * time.Second,
+		Secret:     []byte("test-secret"),
+		Authorizer: policy.NewAuthorizer(policy.Default(), policy.LogAuditor{}),
+	}))
+
+	var pinned bool
+	app.Post("/orders", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusCreated)
+	})
+	app.Get("/orders", func(c *fiber.Ctx) error {
+		pinned = repositories.ReadYourWrites(c.UserContext())
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/orders", nil), -1)
+	assert.NoError(t, err)
+	token := resp.Header.Get(ReadYourWritesHeader)
+	assert.NotEmpty(t, token)
+
+	req := httptest.NewRequest("GET", "/orders", nil)
+	req.Header.Set(ReadYourWritesHeader, token)
+	_, err = app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.True(t, pinned)
+
+	_, err = app.Test(httptest.NewRequest("GET", "/orders", nil), -1)
+	assert.NoError(t, err)
+	assert.False(t, pinned)
+}
+
+// TestReadYourWritesTokens tests that only unexpired tokens the server signed are honoured
+func TestReadYourWritesTokens(t *testing.T) {
+	config := ReadYourWrites{Window: 5 * time.Second, Secret: []byte("test-secret")}
+	other := ReadYourWrites{Window: 5 * time.Second, Secret: []byte("other-secret")}
+	issued := config.issue(time.Now().Add(2 * time.Second))
+	value, _, _ := strings.Cut(issued, ".")
+
+	assert.True(t, config.valid(issued))
+	assert.False(t, config.valid(config.issue(time.Now().Add(-time.Second))), "expired")
+	assert.False(t, config.valid(config.issue(time.Now().Add(time.Hour))), "beyond the window")
+	assert.False(t, config.valid(other.issue(time.Now().Add(2*time.Second))), "signed with another secret")
+	assert.False(t, config.valid(value), "unsigned")
+	assert.False(t, config.valid(strconv.FormatInt(time.Now().Add(time.Hour).UnixMilli(), 10)+"."), "empty signature")
+	assert.False(t, config.valid("garbage"))
+	assert.False(t, config.valid(""))
+}
+
+// TestReadYourWritesAlways tests that only callers the policy allows may pin every read
+func TestReadYourWritesAlways(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(NewAuthMiddleware(Authentication{Verifier: stubVerifier{
+		"admin-token":    {Subject: "root", Roles: []string{auth.RoleAdmin}},
+		"customer-token": {Subject: "42", Roles: []string{auth.RoleCustomer}},
+	}}))
+	app.Use(NewReadYourWritesMiddleware(ReadYourWrites{
+		Window:     5 * time.Second,
+		Secret:     []byte("test-secret"),
+		Authorizer: policy.NewAuthorizer(policy.Default(), policy.LogAuditor{}),
+	}))
+	app.Get("/orders", func(c *fiber.Ctx) error {
+		return c.SendString(strconv.FormatBool(repositories.ReadYourWrites(c.UserContext())))
+	})
+
+	for token, want := range map[string]string{"admin-token": "true", "customer-token": "false"} {
+		req := httptest.NewRequest("GET", "/orders", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		req.Header.Set(ReadYourWritesHeader, "always")
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, want, string(body), token)
+	}
+}
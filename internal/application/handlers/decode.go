@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"order-service/internal/application/validation"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DecodeJSON strictly decodes the request body into v, see validation.DecodeJSON
+func DecodeJSON(c *fiber.Ctx, v interface{}) error {
+	return validation.DecodeJSON(c.Body(), v)
+}
+
+// ParseID reads the ID path parameter called param, reporting it as name when it is invalid
+func ParseID(c *fiber.Ctx, param, name string) (uint, error) {
+	id, err := strconv.ParseUint(c.Params(param), 10, 32)
+	if err != nil {
+		return 0, invalidID(name, err)
+	}
+	return uint(id), nil
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Deprecation describes an API version that clients should move off
+type Deprecation struct {
+	// Since is when the version was deprecated
+	Since time.Time
+	// Sunset is when the version stops being served, if that has been decided
+	Sunset time.Time
+	// Successor is the base path of the version that replaces it
+	Successor string
+	// Next skips the middleware when it returns true
+	Next func(c *fiber.Ctx) bool
+}
+
+// NewDeprecationMiddleware announces a deprecated API version on every response with the
+// Deprecation (RFC 9745) and Sunset (RFC 8594) headers and a link to the successor version
+func NewDeprecationMiddleware(deprecation Deprecation) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if deprecation.Next != nil && deprecation.Next(c) {
+			return c.Next()
+		}
+
+		c.Set("Deprecation", fmt.Sprintf("@%d", deprecation.Since.Unix()))
+		if !deprecation.Sunset.IsZero() {
+			c.Set("Sunset", deprecation.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if deprecation.Successor != "" {
+			c.Append(fiber.HeaderLink, fmt.Sprintf(`<%s>; rel="successor-version"`, deprecation.Successor))
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDeprecationMiddleware tests that deprecated routes announce their sunset, also on errors
+func TestDeprecationMiddleware(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	v1 := app.Group("/v1", NewDeprecationMiddleware(Deprecation{
+		Since:     time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+		Sunset:    time.Date(2027, 4, 19, 0, 0, 0, 0, time.UTC),
+		Successor: "/v2",
+	}))
+	v1.Get("/orders", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+	v1.Get("/orders/:id", func(c *fiber.Ctx) error { return fiber.ErrNotFound })
+	app.Get("/v2/orders", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	for _, path := range []string{"/v1/orders", "/v1/orders/1"} {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil), -1)
+		assert.NoError(t, err)
+		assert.Equal(t, "@1792368000", resp.Header.Get("Deprecation"))
+		assert.Equal(t, "Mon, 19 Apr 2027 00:00:00 GMT", resp.Header.Get("Sunset"))
+		assert.Equal(t, `</v2>; rel="successor-version"`, resp.Header.Get(fiber.HeaderLink))
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/v2/orders", nil), -1)
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Header.Get("Deprecation"))
+	assert.Empty(t, resp.Header.Get("Sunset"))
+}
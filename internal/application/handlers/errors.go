@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"order-service/internal/application/dto"
+	"order-service/internal/domain/domainerr"
+	"order-service/internal/domain/requestid"
+	"order-service/internal/infrastructure/logging"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+const (
+	// ProblemContentType is the media type of every error response
+	ProblemContentType = "application/problem+json"
+	// CorrelationIDHeader carries the ID that ties a response to its log lines and traces
+	CorrelationIDHeader = "X-Correlation-ID"
+
+	// problemTypeBase prefixes the error code to form a problem type URI
+	problemTypeBase = "/problems/"
+	// internalErrorCode is reported for every failure that is not a domain error
+	internalErrorCode = "internal_error"
+)
+
+// kindStatus maps each domain error kind to its HTTP status
+var kindStatus = map[domainerr.Kind]int{
+	domainerr.KindNotFound:              fiber.StatusNotFound,
+	domainerr.KindValidation:            fiber.StatusBadRequest,
+	domainerr.KindConflict:              fiber.StatusConflict,
+	domainerr.KindBusinessRuleViolation: fiber.StatusUnprocessableEntity,
+	domainerr.KindUnauthorized:          fiber.StatusUnauthorized,
+	domainerr.KindForbidden:             fiber.StatusForbidden,
+	domainerr.KindPreconditionFailed:    fiber.StatusPreconditionFailed,
+	domainerr.KindNotSupported:          fiber.StatusNotImplemented,
+}
+
+// kindTitle is the problem title of each domain error kind
+var kindTitle = map[domainerr.Kind]string{
+	domainerr.KindNotFound:              "Not Found",
+	domainerr.KindValidation:            "Validation Failed",
+	domainerr.KindConflict:              "Conflict",
+	domainerr.KindBusinessRuleViolation: "Business Rule Violation",
+	domainerr.KindUnauthorized:          "Unauthorized",
+	domainerr.KindForbidden:             "Forbidden",
+	domainerr.KindPreconditionFailed:    "Precondition Failed",
+	domainerr.KindNotSupported:          "Not Implemented",
+}
+
+// ErrorHandler is the Fiber error handler for every route. It answers with RFC 7807 problem
+// details: domain errors get the status and title of their kind and their code, Fiber errors
+// keep their status, and anything else is logged and answered with a generic 500 so database
+// and driver details never reach clients.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	problem := problemDetails(err)
+	problem.Instance = c.Path()
+	problem.CorrelationID = correlationID(c)
+
+	if problem.Status >= fiber.StatusInternalServerError {
+		logging.Ctx(c.UserContext()).Error().Err(err).Str("method", c.Method()).Str("path", c.Path()).
+			Str("correlation_id", problem.CorrelationID).Str("principal", ClientID(c)).Msg("request failed")
+	}
+	return c.Status(problem.Status).JSON(problem, ProblemContentType)
+}
+
+func problemDetails(err error) dto.ProblemDetails {
+	if domainErr, ok := domainerr.As(err); ok {
+		if status, known := kindStatus[domainErr.Kind]; known {
+			problem := dto.ProblemDetails{
+				Type:   problemTypeBase + domainErr.Code,
+				Title:  kindTitle[domainErr.Kind],
+				Status: status,
+				Detail: domainErr.Message,
+				Code:   domainErr.Code,
+			}
+			for _, field := range domainErr.Fields {
+				problem.Errors = append(problem.Errors, dto.FieldError{Field: field.Field, Message: field.Message})
+			}
+			return problem
+		}
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) && fiberErr.Code < fiber.StatusInternalServerError {
+		title := utils.StatusMessage(fiberErr.Code)
+		return dto.ProblemDetails{
+			Type:   "about:blank",
+			Title:  title,
+			Status: fiberErr.Code,
+			Detail: fiberErr.Message,
+			Code:   strings.ReplaceAll(strings.ToLower(title), " ", "_"),
+		}
+	}
+
+	return dto.ProblemDetails{
+		Type:   "about:blank",
+		Title:  utils.StatusMessage(fiber.StatusInternalServerError),
+		Status: fiber.StatusInternalServerError,
+		Detail: "the request could not be completed",
+		Code:   internalErrorCode,
+	}
+}
+
+// correlationID returns the request ID of the request, or the caller's correlation ID or a new one
+// where no request ID middleware runs, and echoes it
+func correlationID(c *fiber.Ctx) string {
+	id := requestid.From(c.UserContext())
+	if id == "" {
+		id = c.Get(CorrelationIDHeader)
+	}
+	if id == "" {
+		id = utils.UUIDv4()
+	}
+	c.Set(CorrelationIDHeader, id)
+	return id
+}
+
+// invalidID reports a path parameter that is not a valid ID
+func invalidID(name string, err error) error {
+	return domainerr.Validation("invalid_"+name, name+" must be a positive integer").
+		WithFields(domainerr.FieldError{Field: name, Message: "must be a positive integer"}).
+		Wrap(err)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"order-service/internal/application/dto"
+	"order-service/internal/domain/domainerr"
+	"order-service/internal/domain/models"
+	"order-service/internal/domain/repositories"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// TestErrorHandler tests that each kind of error is answered with its problem details
+func TestErrorHandler(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		title   string
+		code    string
+		problem string
+		detail  string
+	}{
+		{"not found", repositories.ErrOrderNotFound, http.StatusNotFound, "Not Found", "order_not_found", "/problems/order_not_found", "order not found"},
+		{"wrapped not found", fmt.Errorf("loading order: %w", repositories.ErrOrderNotFound.Wrap(gorm.ErrRecordNotFound)), http.StatusNotFound, "Not Found", "order_not_found", "/problems/order_not_found", "order not found"},
+		{"validation", domainerr.Validation("invalid_order", "customer ID is required"), http.StatusBadRequest, "Validation Failed", "invalid_order", "/problems/invalid_order", "customer ID is required"},
+		{"conflict", repositories.ErrVersionConflict, http.StatusConflict, "Conflict", "version_conflict", "/problems/version_conflict", "order was modified concurrently"},
+		{"forbidden", domainerr.Forbidden("forbidden", "staff only"), http.StatusForbidden, "Forbidden", "forbidden", "/problems/forbidden", "staff only"},
+		{"precondition", repositories.ErrOrderModified, http.StatusPreconditionFailed, "Precondition Failed", "order_modified", "/problems/order_modified", "order has changed since it was read"},
+		{"business rule", models.ErrOrderCancelled, http.StatusUnprocessableEntity, "Business Rule Violation", "order_cancelled", "/problems/order_cancelled", "order is cancelled"},
+		{"not supported", domainerr.NotSupported("search_not_supported", "order search is not available"), http.StatusNotImplemented, "Not Implemented", "search_not_supported", "/problems/search_not_supported", "order search is not available"},
+		{"fiber error", fiber.ErrMethodNotAllowed, http.StatusMethodNotAllowed, "Method Not Allowed", "method_not_allowed", "about:blank", "Method Not Allowed"},
+		{"raw gorm error", gorm.ErrInvalidTransaction, http.StatusInternalServerError, "Internal Server Error", "internal_error", "about:blank", "the request could not be completed"},
+		{"other error", errors.New("pq: connection refused"), http.StatusInternalServerError, "Internal Server Error", "internal_error", "about:blank", "the request could not be completed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+			app.Get("/orders/1", func(c *fiber.Ctx) error { return tt.err })
+
+			req := httptest.NewRequest("GET", "/orders/1", nil)
+			req.Header.Set(CorrelationIDHeader, "corr-1")
+			resp, err := app.Test(req, -1)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, ProblemContentType, resp.Header.Get(fiber.HeaderContentType))
+			assert.Equal(t, "corr-1", resp.Header.Get(CorrelationIDHeader))
+
+			var body dto.ProblemDetails
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, dto.ProblemDetails{
+				Type:          tt.problem,
+				Title:         tt.title,
+				Status:        tt.status,
+				Detail:        tt.detail,
+				Instance:      "/orders/1",
+				Code:          tt.code,
+				CorrelationID: "corr-1",
+			}, body)
+		})
+	}
+}
+
+// TestErrorHandlerFieldErrors tests that invalid fields are listed and a correlation ID is generated
+func TestErrorHandlerFieldErrors(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Get("/orders/:id", func(c *fiber.Ctx) error { return invalidID("id", errors.New("invalid syntax")) })
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/orders/abc", nil), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	var body dto.ProblemDetails
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, []dto.FieldError{{Field: "id", Message: "must be a positive integer"}}, body.Errors)
+	assert.NotEmpty(t, body.CorrelationID)
+	assert.Equal(t, body.CorrelationID, resp.Header.Get(CorrelationIDHeader))
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"order-service/internal/application/auth"
+	"order-service/internal/application/repository"
+	"order-service/internal/domain/domainerr"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// IdempotencyKeyHeader lets clients retry a POST or PATCH without repeating its effect
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks a response replayed from an earlier request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// NewIdempotencyMiddleware replays the stored response for POST and PATCH requests that repeat an
+// Idempotency-Key within ttl. It is mounted on the order routes only: creating, importing,
+// adding an item, cancelling and patching orders, whose responses hold nothing secret. A key
+// reused with a different request is rejected with 422 and a key whose first request is still
+// running with 409. Server errors and responses marked Cache-Control: no-store, which hold
+// secrets, release the key instead of being stored.
+// Keys are scoped to the authenticated caller, so a caller never replays another caller's response.
+func NewIdempotencyMiddleware(store repository.IdempotencyStore, ttl time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		if key == "" || (c.Method() != fiber.MethodPost && c.Method() != fiber.MethodPatch) {
+			return c.Next()
+		}
+		// Fingerprinting a streamed bulk import would buffer the whole upload. Imports are safe to
+		// retry without a key: orders whose IDs were already imported are rejected.
+		if c.Context().RequestBodyStream() != nil {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return domainerr.Validation("idempotency_key_too_long", "Idempotency-Key is too long")
+		}
+
+		key = callerIdempotencyKey(c, key)
+		now := time.Now()
+		fingerprint := requestFingerprint(c)
+		existing, err := store.Reserve(c.UserContext(), repository.IdempotencyRecord{
+			Key:         key,
+			Fingerprint: fingerprint,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		})
+		if err != nil {
+			return err
+		}
+
+		if existing != nil {
+			switch {
+			case existing.Fingerprint != fingerprint:
+				return domainerr.BusinessRuleViolation("idempotency_key_reused", "Idempotency-Key was already used for a different request")
+			case !existing.Completed:
+				return domainerr.Conflict("idempotency_key_in_progress", "a request with this Idempotency-Key is still in progress")
+			}
+			c.Set(IdempotentReplayedHeader, "true")
+			c.Set(fiber.HeaderContentType, existing.ContentType)
+			return c.Status(existing.StatusCode).Send(existing.Body)
+		}
+
+		// Render handler errors here so error responses are stored like any other response
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				_ = store.Release(c.UserContext(), key)
+				return err
+			}
+		}
+
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError || noStore(c) {
+			return store.Release(c.UserContext(), key)
+		}
+		return store.Complete(c.UserContext(), key, status, string(c.Response().Header.ContentType()), c.Response().Body())
+	}
+}
+
+// noStore reports whether the response forbids keeping a copy of it
+func noStore(c *fiber.Ctx) bool {
+	for _, directive := range strings.Split(string(c.Response().Header.Peek(fiber.HeaderCacheControl)), ",") {
+		if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+			return true
+		}
+	}
+	return false
+}
+
+// callerIdempotencyKey prefixes key with a hash of the caller's subject. The hash has a fixed length
+// and no separator, so no subject and key can collide with another pair.
+func callerIdempotencyKey(c *fiber.Ctx, key string) string {
+	principal, _ := auth.PrincipalFrom(c.UserContext())
+	subject := sha256.Sum256([]byte(principal.Subject))
+	return hex.EncodeToString(subject[:]) + ":" + key
+}
+
+// requestFingerprint identifies the method, path and body a key was first used with
+func requestFingerprint(c *fiber.Ctx) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Method()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(c.OriginalURL()))
+	hash.Write([]byte{0})
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
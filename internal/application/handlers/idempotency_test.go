@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"order-service/internal/application/auth"
+	"order-service/internal/infrastructure/persistence"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func newIdempotentApp(calls *int) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(NewIdempotencyMiddleware(persistence.NewInMemoryIdempotencyStore(), time.Hour))
+	app.Post("/orders", func(c *fiber.Ctx) error {
+		*calls++
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"call": *calls})
+	})
+	return app
+}
+
+func postOrder(t *testing.T, app *fiber.App, key, body string) (*http.Response, string) {
+	req := httptest.NewRequest("POST", "/orders", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	payload, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	return resp, string(payload)
+}
+
+// TestIdempotencyReplay tests that a retried request replays the original response
+func TestIdempotencyReplay(t *testing.T) {
+	calls := 0
+	app := newIdempotentApp(&calls)
+
+	first, firstBody := postOrder(t, app, "key-1", `{"OrderID":"a"}`)
+	second, secondBody := postOrder(t, app, "key-1", `{"OrderID":"a"}`)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusCreated, first.StatusCode)
+	assert.Equal(t, http.StatusCreated, second.StatusCode)
+	assert.Equal(t, firstBody, secondBody)
+	assert.Equal(t, "true", second.Header.Get(IdempotentReplayedHeader))
+}
+
+// TestIdempotencyKeyReuse tests that a key cannot be reused for a different payload
+func TestIdempotencyKeyReuse(t *testing.T) {
+	calls := 0
+	app := newIdempotentApp(&calls)
+
+	postOrder(t, app, "key-1", `{"OrderID":"a"}`)
+	resp, _ := postOrder(t, app, "key-1", `{"OrderID":"b"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Equal(t, 1, calls)
+}
+
+// TestIdempotencyWithoutKey tests that requests without a key are never deduplicated
+func TestIdempotencyWithoutKey(t *testing.T) {
+	calls := 0
+	app := newIdempotentApp(&calls)
+
+	postOrder(t, app, "", `{"OrderID":"a"}`)
+	postOrder(t, app, "", `{"OrderID":"a"}`)
+
+	assert.Equal(t, 2, calls)
+}
+
+// TestIdempotencyKeyPerCaller tests that two callers using the same key never see each other's responses
+func TestIdempotencyKeyPerCaller(t *testing.T) {
+	calls := 0
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(func(c *fiber.Ctx) error {
+		return authenticated(c, auth.Principal{Subject: c.Get("X-Subject")})
+	})
+	app.Use(NewIdempotencyMiddleware(persistence.NewInMemoryIdempotencyStore(), time.Hour))
+	app.Post("/orders", func(c *fiber.Ctx) error {
+		calls++
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"call": calls, "subject": c.Get("X-Subject")})
+	})
+	post := func(subject string) (*http.Response, string) {
+		req := httptest.NewRequest("POST", "/orders", strings.NewReader(`{"OrderID":"a"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		req.Header.Set("X-Subject", subject)
+		resp, err := app.Test(req, -1)
+		assert.NoError(t, err)
+		payload, err := io.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		return resp, string(payload)
+	}
+
+	_, aliceBody := post("alice")
+	bob, bobBody := post("bob")
+	aliceAgain, aliceAgainBody := post("alice")
+
+	assert.Equal(t, 2, calls, "each caller's first request runs")
+	assert.Equal(t, http.StatusCreated, bob.StatusCode)
+	assert.Empty(t, bob.Header.Get(IdempotentReplayedHeader))
+	assert.Contains(t, bobBody, `"subject":"bob"`)
+	assert.NotEqual(t, aliceBody, bobBody)
+	assert.Equal(t, "true", aliceAgain.Header.Get(IdempotentReplayedHeader))
+	assert.Equal(t, aliceBody, aliceAgainBody)
+}
+
+// TestIdempotencyStreamedImport tests that streamed imports are passed through while other requests are still replayed
+func TestIdempotencyStreamedImport(t *testing.T) {
+	calls := 0
+	app := fiber.New(fiber.Config{StreamRequestBody: true, ErrorHandler: ErrorHandler})
+	app.Use(NewBodyLimitMiddleware(fiber.DefaultBodyLimit, IsBulkImport))
+	app.Use(NewIdempotencyMiddleware(persistence.NewInMemoryIdempotencyStore(), time.Hour))
+	handler := func(c *fiber.Ctx) error {
+		calls++
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"call": calls})
+	}
+	app.Post("/orders", handler)
+	app.Post("/orders/import", handler)
+
+	for _, path := range []string{"/orders/import", "/orders/import", "/orders", "/orders"} {
+		req := httptest.NewRequest("POST", path, strings.NewReader(`{"OrderID":"a"}`))
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		_, err := app.Test(req, -1)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 3, calls, "both imports run and the order is created once")
+}
+
+// TestIdempotencyNoStore tests that responses marked no-store, such as issued API keys, are never stored or replayed
+func TestIdempotencyNoStore(t *testing.T) {
+	calls := 0
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(NewIdempotencyMiddleware(persistence.NewInMemoryIdempotencyStore(), time.Hour))
+	app.Post("/orders", func(c *fiber.Ctx) error {
+		calls++
+		c.Set(fiber.HeaderCacheControl, "private, no-store")
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"secret": calls})
+	})
+
+	first, firstBody := postOrder(t, app, "key-1", `{"OrderID":"a"}`)
+	second, secondBody := postOrder(t, app, "key-1", `{"OrderID":"a"}`)
+
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, http.StatusCreated, first.StatusCode)
+	assert.Empty(t, second.Header.Get(IdempotentReplayedHeader))
+	assert.NotEqual(t, firstBody, secondBody)
+}
@@ -2,130 +2,196 @@ package handlers
 
 import (
 	"order-service/internal/application/dto"
+	"order-service/internal/application/patch"
 	"order-service/internal/application/services"
-	"strconv"
+	"order-service/internal/domain/domainerr"
+	"order-service/internal/domain/repositories"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// OrderHandler handles order-related API requests
+// OrderRepresentation maps service results to the responses of one API version
+type OrderRepresentation struct {
+	Order   func(order dto.OrderResponse) interface{}
+	List    func(orders []dto.OrderResponse) interface{}
+	Search  func(results dto.OrderSearchResponse) interface{}
+	History func(history []dto.CustomerOrderHistoryResponse) interface{}
+	// EmptyListNotFound answers an empty order list with 404 and the empty list
+	EmptyListNotFound bool
+}
+
+// OrderHandler serves the order requests of every API version. Commands go to service, reads are
+// served from the read model through queries, and responses are shaped by the version's
+// representation. Each version package registers its routes and documents them.
 type OrderHandler struct {
-	service services.OrderService
+	service        services.OrderService
+	queries        services.OrderQueryService
+	representation OrderRepresentation
 }
 
-// NewOrderHandler initializes the order handler with routes
-func NewOrderHandler(app *fiber.App, service services.OrderService) {
-	handler := &OrderHandler{service: service}
-	app.Post("/orders", handler.CreateOrder)
-	app.Get("/orders/:id", handler.GetOrderByID)
-	app.Get("/orders", handler.GetAllOrders)
-	app.Post("/orders/:id/items", handler.AddItemToOrder)
+// NewOrderHandler creates the shared order handler for the API version described by representation
+func NewOrderHandler(service services.OrderService, queries services.OrderQueryService, representation OrderRepresentation) *OrderHandler {
+	return &OrderHandler{service: service, queries: queries, representation: representation}
 }
 
-// CreateOrder godoc
-// @Summary Create a new order
-// @Description Create a new order with items
-// @Tags orders
-// @Accept json
-// @Produce json
-// @Param order body models.Order true "Order"
-// @Success 201 {object} dto.OrderResponse
-// @Failure 400 {object} dto.ErrorResponse
-// @Failure 500 {object} dto.ErrorResponse
-// @Router /orders [post]
 func (h *OrderHandler) CreateOrder(c *fiber.Ctx) error {
 	var order dto.OrderCreateDto
 	order.NewOrderCreateDto()
 
-	var orderResponse dto.OrderResponse
-	if err := c.BodyParser(&order); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{Error: err.Error()})
+	if err := DecodeJSON(c, &order); err != nil {
+		return err
 	}
 
-	orderResponse, err := h.service.CreateOrder(order)
+	orderResponse, err := h.service.CreateOrder(c.UserContext(), order)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{Error: err.Error()})
+		return err
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(orderResponse)
+	OrderValidators(orderResponse).Set(c)
+	return c.Status(fiber.StatusCreated).JSON(h.representation.Order(orderResponse))
 }
 
-// GetOrderByID godoc
-// @Summary Get order by ID
-// @Description Get order details by ID
-// @Tags orders
-// @Produce json
-// @Param id path int true "Order ID"
-// @Success 200 {object} dto.OrderResponse
-// @Success 404 {object} dto.OrderResponse
-// @Failure 400 {object} dto.ErrorResponse
-// @Failure 500 {object} dto.ErrorResponse
-// @Router /orders/{id} [get]
 func (h *OrderHandler) GetOrderByID(c *fiber.Ctx) error {
-	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	id, err := ParseID(c, "id", "id")
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{Error: err.Error()})
+		return err
 	}
-	order, err := h.service.GetOrderByID(uint(id))
+	order, err := h.queries.GetOrderByID(c.UserContext(), id)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{Error: err.Error()})
+		return err
 	}
 	if order == nil {
-		return c.Status(fiber.StatusNotFound).JSON(order)
+		return repositories.ErrOrderNotFound
 	}
 
-	return c.Status(fiber.StatusOK).JSON(order)
+	return SendConditional(c, OrderValidators(*order), h.representation.Order(*order))
 }
 
-// GetAllOrders godoc
-// @Summary Get all orders
-// @Description Get a list of all orders
-// @Tags orders
-// @Produce json
-// @Success 200 {array} dto.OrderResponse
-// @Success 404 {array} dto.OrderResponse
-// @Failure 500 {object} dto.ErrorResponse
-// @Router /orders [get]
 func (h *OrderHandler) GetAllOrders(c *fiber.Ctx) error {
-	orders, err := h.service.GetAllOrders()
+	orders, err := h.queries.GetAllOrders(c.UserContext())
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{Error: err.Error()})
+		return err
+	}
+	if len(orders) == 0 && h.representation.EmptyListNotFound {
+		return c.Status(fiber.StatusNotFound).JSON(h.representation.List(orders))
+	}
+
+	return SendConditional(c, ListValidators(orders), h.representation.List(orders))
+}
+
+func (h *OrderHandler) SearchOrders(c *fiber.Ctx) error {
+	text := strings.TrimSpace(c.Query("q"))
+	if text == "" {
+		return domainerr.Validation("query_required", "query parameter q is required")
 	}
-	if len(orders) == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(orders)
+
+	results, err := h.service.SearchOrders(c.UserContext(), text, c.QueryInt("page", 1), c.QueryInt("page_size", 0))
+	if err != nil {
+		return err
 	}
 
-	return c.Status(fiber.StatusOK).JSON(orders)
+	return c.Status(fiber.StatusOK).JSON(h.representation.Search(results))
 }
 
-// AddItemToOrder godoc
-// @Summary Add item to order
-// @Description Add a new item to an existing order
-// @Tags orders
-// @Accept json
-// @Produce json
-// @Param id path int true "Order ID"
-// @Param item body models.OrderItem true "Order Item"
-// @Success 200 {object} dto.OrderResponse
-// @Failure 400 {object} dto.ErrorResponse
-// @Failure 500 {object} dto.ErrorResponse
-// @Router /orders/{id}/items [post]
 func (h *OrderHandler) AddItemToOrder(c *fiber.Ctx) error {
-	orderID, err := strconv.ParseUint(c.Params("id"), 10, 32)
-	id := uint(orderID)
+	id, err := ParseID(c, "id", "id")
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{Error: err.Error()})
+		return err
 	}
 
 	var item dto.OrderItemDto
-	if err := c.BodyParser(&item); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{Error: err.Error()})
+	if err := DecodeJSON(c, &item); err != nil {
+		return err
+	}
+
+	response, err := h.service.AddItemToOrder(IfMatch(c), id, item)
+	if err != nil {
+		return err
+	}
+
+	OrderValidators(*response).Set(c)
+	return c.Status(fiber.StatusCreated).JSON(h.representation.Order(*response))
+}
+
+func (h *OrderHandler) CancelOrder(c *fiber.Ctx) error {
+	id, err := ParseID(c, "id", "id")
+	if err != nil {
+		return err
+	}
+
+	response, err := h.service.CancelOrder(IfMatch(c), id)
+	if err != nil {
+		return err
+	}
+
+	OrderValidators(*response).Set(c)
+	return c.Status(fiber.StatusOK).JSON(h.representation.Order(*response))
+}
+
+func (h *OrderHandler) UpdateOrder(c *fiber.Ctx) error {
+	id, err := ParseID(c, "id", "id")
+	if err != nil {
+		return err
+	}
+
+	var order dto.OrderUpdateDto
+	if err := DecodeJSON(c, &order); err != nil {
+		return err
+	}
+
+	response, err := h.service.UpdateOrder(IfMatch(c), id, order)
+	if err != nil {
+		return err
+	}
+
+	OrderValidators(*response).Set(c)
+	return c.Status(fiber.StatusOK).JSON(h.representation.Order(*response))
+}
+
+func (h *OrderHandler) PatchOrder(c *fiber.Ctx) error {
+	id, err := ParseID(c, "id", "id")
+	if err != nil {
+		return err
+	}
+
+	format, err := patch.ParseFormat(c.Get(fiber.HeaderContentType))
+	if err != nil {
+		return fiber.NewError(fiber.StatusUnsupportedMediaType, err.Error())
+	}
+
+	response, err := h.service.PatchOrder(IfMatch(c), id, patch.Patch{Format: format, Document: c.Body()})
+	if err != nil {
+		return err
+	}
+
+	OrderValidators(*response).Set(c)
+	return c.Status(fiber.StatusOK).JSON(h.representation.Order(*response))
+}
+
+func (h *OrderHandler) DeleteOrder(c *fiber.Ctx) error {
+	id, err := ParseID(c, "id", "id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.DeleteOrder(IfMatch(c), id); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *OrderHandler) GetCustomerOrderHistory(c *fiber.Ctx) error {
+	customerID, err := ParseID(c, "id", "customer_id")
+	if err != nil {
+		return err
 	}
 
-	response, err := h.service.AddItemToOrder(id, item)
+	history, err := h.queries.GetCustomerOrderHistory(c.UserContext(), customerID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{Error: err.Error()})
+		return err
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(response)
+	return c.Status(fiber.StatusOK).JSON(h.representation.History(history))
 }
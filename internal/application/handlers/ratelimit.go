@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"order-service/internal/infrastructure/logging"
+	"order-service/internal/infrastructure/ratelimit"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// clientIDLocal is the Fiber local holding the authenticated API client of a request
+const clientIDLocal = "client_id"
+
+// versionPrefix matches the API version segment that rule paths leave out
+var versionPrefix = regexp.MustCompile(`^/v[0-9]+(/|$)`)
+
+// SetClientID records the authenticated API client of the request
+func SetClientID(c *fiber.Ctx, id string) {
+	c.Locals(clientIDLocal, id)
+}
+
+// ClientID returns the authenticated API client of the request, or "" for anonymous requests
+func ClientID(c *fiber.Ctx) string {
+	id, _ := c.Locals(clientIDLocal).(string)
+	return id
+}
+
+// NewRateLimitMiddleware counts every request against the rules it matches and refuses it with
+// 429 Too Many Requests once one of them is exhausted. A refused request does not count against
+// any rule. Responses carry the RateLimit headers of the most restrictive rule. When the store
+// fails the request is let through, so an outage of the store does not take the API down with it.
+func NewRateLimitMiddleware(store ratelimit.Store, rules []ratelimit.Rule) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		client := ClientID(c)
+		path := unversionedPath(c.Path())
+		now := time.Now()
+
+		var tightest *ratelimit.Result
+		var policies []string
+		var counted []rateLimitCount
+		for _, rule := range rules {
+			if !matchesRule(rule, c.Method(), path) {
+				continue
+			}
+			limit := rule.Limit(client)
+			key := rateLimitKey(rule, c, client, path)
+			result, err := store.Allow(c.UserContext(), key, limit, now)
+			if err != nil {
+				logging.Ctx(c.UserContext()).Warn().Err(err).Str("rule", rule.Name).Msg("rate limit store unavailable")
+				continue
+			}
+			policies = append(policies, fmt.Sprintf("%d;w=%d", limit.Requests, int(limit.Window.Seconds())))
+			if tightest == nil || result.Remaining < tightest.Remaining {
+				tightest = &result
+			}
+
+			if !result.Allowed {
+				refund(c, store, counted, now)
+				setRateLimitHeaders(c, result, policies)
+				c.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds(result.Reset)))
+				return fiber.NewError(fiber.StatusTooManyRequests, fmt.Sprintf("rate limit %s exceeded", rule.Name))
+			}
+			counted = append(counted, rateLimitCount{rule: rule.Name, key: key, limit: limit})
+		}
+
+		if tightest != nil {
+			setRateLimitHeaders(c, *tightest, policies)
+		}
+		return c.Next()
+	}
+}
+
+// rateLimitCount is a request counted against a rule
+type rateLimitCount struct {
+	rule  string
+	key   string
+	limit ratelimit.Limit
+}
+
+// refund takes back the counts of a request a later rule refused
+func refund(c *fiber.Ctx, store ratelimit.Store, counted []rateLimitCount, now time.Time) {
+	for _, count := range counted {
+		if err := store.Refund(c.UserContext(), count.key, count.limit, now); err != nil {
+			logging.Ctx(c.UserContext()).Warn().Err(err).Str("rule", count.rule).Msg("rate limit refund failed")
+		}
+	}
+}
+
+func setRateLimitHeaders(c *fiber.Ctx, result ratelimit.Result, policies []string) {
+	c.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Set("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+	c.Set("RateLimit-Policy", strings.Join(policies, ", "))
+}
+
+// rateLimitKey names the counter of a rule for this request. Anonymous clients are told apart
+// by their IP address.
+func rateLimitKey(rule ratelimit.Rule, c *fiber.Ctx, client, path string) string {
+	key := rule.Name
+	for _, part := range rule.By {
+		switch part {
+		case ratelimit.ByClient:
+			if client != "" {
+				key += "|client=" + client
+			} else {
+				key += "|ip=" + c.IP()
+			}
+		case ratelimit.ByIP:
+			key += "|ip=" + c.IP()
+		case ratelimit.ByRoute:
+			key += "|route=" + c.Method() + " " + routeTemplate(rule, path)
+		}
+	}
+	return key
+}
+
+// matchesRule reports whether a request is subject to rule. Path segments starting with a colon
+// match any segment and a trailing * matches the rest of the path.
+func matchesRule(rule ratelimit.Rule, method, path string) bool {
+	if rule.Method != "" && !strings.EqualFold(rule.Method, method) {
+		return false
+	}
+	if rule.Path == "" {
+		return true
+	}
+
+	pattern := strings.Split(strings.Trim(rule.Path, "/"), "/")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, want := range pattern {
+		if want == "*" && i == len(pattern)-1 {
+			return true
+		}
+		if i >= len(segments) || (!strings.HasPrefix(want, ":") && want != segments[i]) {
+			return false
+		}
+	}
+	return len(pattern) == len(segments)
+}
+
+// routeTemplate returns the route a request is counted under: the rule's path, or for rules
+// covering every path the request path with its numeric IDs replaced, so each order does not
+// get a counter of its own
+func routeTemplate(rule ratelimit.Rule, path string) string {
+	if rule.Path != "" {
+		return rule.Path
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if _, err := strconv.ParseUint(segment, 10, 64); err == nil {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// unversionedPath strips the API version prefix, so rules apply to every version
+func unversionedPath(path string) string {
+	if prefix := versionPrefix.FindString(path); prefix != "" {
+		return "/" + strings.TrimPrefix(path, prefix)
+	}
+	return path
+}
+
+// seconds rounds a duration up to whole seconds for headers
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
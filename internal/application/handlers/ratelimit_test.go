@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"order-service/internal/infrastructure/ratelimit"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRateLimitMiddleware tests that matching requests are counted per client across versions
+// and refused with 429 once a rule is exhausted
+func TestRateLimitMiddleware(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(func(c *fiber.Ctx) error {
+		SetClientID(c, c.Get("X-Test-Client"))
+		return c.Next()
+	})
+	app.Use(NewRateLimitMiddleware(ratelimit.NewMemoryStore(), []ratelimit.Rule{
+		{Name: "create-orders", Method: "POST", Path: "/orders", By: []string{ratelimit.ByClient}, Requests: 2, Window: time.Minute},
+		{Name: "daily-quota", By: []string{ratelimit.ByClient}, Requests: 100, Window: 24 * time.Hour, Fixed: true},
+	}))
+	app.Post("/orders", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) })
+	app.Post("/v2/orders", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) })
+	app.Get("/v2/orders/:id", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	send := func(method, path, client string) *http.Response {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("X-Test-Client", client)
+		resp, err := app.Test(req, -1)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	resp := send("POST", "/orders", "acme")
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get("RateLimit-Limit"))
+	assert.Equal(t, "1", resp.Header.Get("RateLimit-Remaining"))
+	assert.Equal(t, "2;w=60, 100;w=86400", resp.Header.Get("RateLimit-Policy"))
+
+	assert.Equal(t, http.StatusCreated, send("POST", "/v2/orders", "acme").StatusCode)
+	resp = send("POST", "/orders", "acme")
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "0", resp.Header.Get("RateLimit-Remaining"))
+	assert.NotEmpty(t, resp.Header.Get(fiber.HeaderRetryAfter))
+
+	assert.Equal(t, http.StatusCreated, send("POST", "/orders", "globex").StatusCode, "clients are limited separately")
+
+	resp = send("GET", "/v2/orders/1", "acme")
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "other routes only count against the quota")
+	assert.Equal(t, "100", resp.Header.Get("RateLimit-Limit"))
+	assert.Equal(t, "97", resp.Header.Get("RateLimit-Remaining"), "refused requests do not use up the quota")
+}
+
+// TestRateLimitRefusedByLaterRule tests that a request refused by a later rule is not counted
+// against the earlier rules it passed
+func TestRateLimitRefusedByLaterRule(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(NewRateLimitMiddleware(ratelimit.NewMemoryStore(), []ratelimit.Rule{
+		{Name: "per-ip", By: []string{ratelimit.ByIP}, Requests: 10, Window: time.Minute},
+		{Name: "create-orders", Method: "POST", Path: "/orders", By: []string{ratelimit.ByIP}, Requests: 1, Window: time.Minute},
+	}))
+	app.Post("/orders", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) })
+	app.Get("/orders", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	send := func(method string) *http.Response {
+		resp, err := app.Test(httptest.NewRequest(method, "/orders", nil), -1)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	assert.Equal(t, http.StatusCreated, send("POST").StatusCode)
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusTooManyRequests, send("POST").StatusCode)
+	}
+
+	resp := send("GET")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "8", resp.Header.Get("RateLimit-Remaining"), "only the created order and this request count per IP")
+}
+
+// TestMatchesRule tests rule paths against unversioned request paths
+func TestMatchesRule(t *testing.T) {
+	tests := []struct {
+		rule   ratelimit.Rule
+		method string
+		path   string
+		match  bool
+	}{
+		{ratelimit.Rule{}, "GET", "/orders/1", true},
+		{ratelimit.Rule{Method: "POST", Path: "/orders"}, "POST", "/orders", true},
+		{ratelimit.Rule{Method: "POST", Path: "/orders"}, "GET", "/orders", false},
+		{ratelimit.Rule{Path: "/orders"}, "GET", "/orders/1", false},
+		{ratelimit.Rule{Path: "/orders/:id"}, "GET", "/orders/1", true},
+		{ratelimit.Rule{Path: "/orders/*"}, "POST", "/orders/1/items", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.match, matchesRule(tt.rule, tt.method, tt.path), "%s %s", tt.method, tt.path)
+	}
+	assert.Equal(t, "/orders/1", unversionedPath("/v2/orders/1"))
+	assert.Equal(t, "/orders/:id/items", routeTemplate(ratelimit.Rule{}, "/orders/42/items"))
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"order-service/internal/domain/requestid"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader carries the request ID in requests and responses
+const RequestIDHeader = "X-Request-ID"
+
+// NewRequestIDMiddleware gives every request an ID, taken from X-Request-ID or X-Correlation-ID
+// when the caller sent a valid one and generated otherwise. The ID is echoed in the response and
+// carried by the request context, so log lines, spans and events of the request can be found by it.
+func NewRequestIDMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = c.Get(CorrelationIDHeader)
+		}
+		if !validRequestID(id) {
+			id = utils.UUIDv4()
+		}
+
+		c.Set(RequestIDHeader, id)
+		c.SetUserContext(requestid.With(c.UserContext(), id))
+		trace.SpanFromContext(c.UserContext()).SetAttributes(attribute.String("request.id", id))
+		return c.Next()
+	}
+}
+
+// validRequestID reports whether id is safe to accept from a caller: 1 to 128 letters, digits and . _ : -
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_', r == ':', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
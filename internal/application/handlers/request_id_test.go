@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"order-service/internal/application/dto"
+	"order-service/internal/domain/domainerr"
+	"order-service/internal/domain/requestid"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRequestIDMiddleware tests that valid caller IDs are kept, others replaced, and errors report the ID
+func TestRequestIDMiddleware(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(NewRequestIDMiddleware())
+	app.Get("/id", func(c *fiber.Ctx) error {
+		return c.SendString(requestid.From(c.UserContext()))
+	})
+	app.Get("/fail", func(c *fiber.Ctx) error {
+		return domainerr.NotFound("order_not_found", "order not found")
+	})
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{"request ID", map[string]string{RequestIDHeader: "req-1"}, "req-1"},
+		{"correlation ID", map[string]string{CorrelationIDHeader: "corr-1"}, "corr-1"},
+		{"request ID wins", map[string]string{RequestIDHeader: "req-1", CorrelationIDHeader: "corr-1"}, "req-1"},
+		{"unsafe ID", map[string]string{RequestIDHeader: "req-1\nforged=1"}, ""},
+		{"no ID", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/id", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			resp, err := app.Test(req, -1)
+			require.NoError(t, err)
+			id := resp.Header.Get(RequestIDHeader)
+			assert.True(t, validRequestID(id))
+			if tt.want != "" {
+				assert.Equal(t, tt.want, id)
+			} else {
+				assert.Len(t, id, 36, "a UUID is generated")
+			}
+		})
+	}
+
+	req := httptest.NewRequest("GET", "/fail", nil)
+	req.Header.Set(RequestIDHeader, "req-2")
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	var problem dto.ProblemDetails
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+	assert.Equal(t, "req-2", problem.CorrelationID)
+	assert.Equal(t, "req-2", resp.Header.Get(CorrelationIDHeader))
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"order-service/internal/application/dto"
+	"order-service/internal/application/policy"
+	"order-service/internal/application/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RetentionHandler handles legal hold and retention reporting requests
+type RetentionHandler struct {
+	service services.RetentionService
+}
+
+// NewRetentionHandler initializes the retention handler with routes, which the policy limits to staff
+func NewRetentionHandler(router fiber.Router, service services.RetentionService, authorizer *policy.Authorizer) {
+	handler := &RetentionHandler{service: service}
+	router.Put("/orders/:id/legal-hold", Authorize(authorizer, policy.SetLegalHold), handler.SetLegalHold)
+	router.Get("/retention/report", Authorize(authorizer, policy.RetentionReport), handler.GetRetentionReport)
+}
+
+// SetLegalHold godoc
+// @Summary Place or release a legal hold
+// @Description Orders on legal hold are never purged by the retention policy
+// @Tags retention
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param hold body dto.LegalHoldDto true "Legal hold"
+// @Success 204
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails "The order changed while the hold was placed, retry"
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /orders/{id}/legal-hold [put]
+func (h *RetentionHandler) SetLegalHold(c *fiber.Ctx) error {
+	id, err := ParseID(c, "id", "id")
+	if err != nil {
+		return err
+	}
+
+	var body dto.LegalHoldDto
+	if err := DecodeJSON(c, &body); err != nil {
+		return err
+	}
+
+	if err := h.service.SetLegalHold(c.UserContext(), id, body.Hold); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetRetentionReport godoc
+// @Summary Preview the retention policy
+// @Description Dry run of the retention policy listing the orders it would purge now
+// @Tags retention
+// @Produce json
+// @Success 200 {object} dto.RetentionReport
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /retention/report [get]
+func (h *RetentionHandler) GetRetentionReport(c *fiber.Ctx) error {
+	report, err := h.service.Run(c.UserContext(), true)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(report)
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"order-service/internal/domain/domainerr"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestTracingMiddleware tests that every request gets a server span named after its route that
+// continues the caller's trace and reports the response status
+func TestTracingMiddleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Use(NewTracingMiddleware())
+	app.Use(NewRequestIDMiddleware())
+	var handlerSpan trace.SpanContext
+	app.Get("/v1/orders/:id", func(c *fiber.Ctx) error {
+		handlerSpan = trace.SpanContextFromContext(c.UserContext())
+		if c.Params("id") == "404" {
+			return domainerr.NotFound("order_not_found", "order not found")
+		}
+		if c.Params("id") == "500" {
+			return errors.New("database unavailable")
+		}
+		return c.SendString("order")
+	})
+
+	request := func(path string, header map[string]string) *http.Response {
+		req := httptest.NewRequest("GET", path, nil)
+		for name, value := range header {
+			req.Header.Set(name, value)
+		}
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := request("/v1/orders/1", map[string]string{
+		"traceparent":   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		RequestIDHeader: "req-1",
+	})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp = request("/v1/orders/404", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp = request("/v1/orders/500", nil)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode, "the error handler still writes the response")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	ok := spans[0]
+	assert.Equal(t, "GET /v1/orders/:id", ok.Name())
+	assert.Equal(t, trace.SpanKindServer, ok.SpanKind())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", ok.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", ok.Parent().SpanID().String())
+	assert.Contains(t, ok.Attributes(), attribute.String("http.route", "/v1/orders/:id"))
+	assert.Contains(t, ok.Attributes(), attribute.Int("http.response.status_code", http.StatusOK))
+	assert.Contains(t, ok.Attributes(), attribute.String("request.id", "req-1"))
+
+	notFound := spans[1]
+	assert.Contains(t, notFound.Attributes(), attribute.Int("http.response.status_code", http.StatusNotFound))
+	assert.Equal(t, codes.Unset, notFound.Status().Code, "client errors do not fail the server span")
+	assert.False(t, notFound.Parent().IsValid(), "requests without traceparent start a trace")
+
+	failed := spans[2]
+	assert.Equal(t, failed.SpanContext(), handlerSpan, "handlers run inside the span")
+	assert.Contains(t, failed.Attributes(), attribute.Int("http.response.status_code", http.StatusInternalServerError))
+	assert.Equal(t, codes.Error, failed.Status().Code)
+	require.Len(t, failed.Events(), 1)
+	assert.Equal(t, "exception", failed.Events()[0].Name)
+}
@@ -0,0 +1,26 @@
+// Package v1 serves the first version of the order API under /v1, and at the root for clients
+// that predate versioning. It is deprecated in favour of v2 and answers with Deprecation and
+// Sunset headers.
+//
+// @title Order Service API
+// @version 1.0
+// @description This is an API for managing orders. Version 1 is deprecated, use version 2.
+// @termsOfService http://swagger.io/terms/
+// @contact.name API Support
+// @contact.url http://www.swagger.io/support
+// @contact.email support@swagger.io
+// @license.name Apache 2.0
+// @license.url http://www.apache.org/licenses/LICENSE-2.0.html
+// @host localhost:8080
+// @BasePath /v1
+//
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description JWT bearer token, sent as "Bearer <token>". Customers reach only their own orders.
+//
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key of a service-to-service caller, limited to its scopes
+package v1
@@ -0,0 +1,268 @@
+package v1
+
+import (
+	"order-service/internal/application/dto"
+	"order-service/internal/application/handlers"
+	"order-service/internal/application/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// OrderHandler documents the order-related v1 API and serves it through the shared handler
+// with v1 representations
+type OrderHandler struct {
+	orders *handlers.OrderHandler
+}
+
+// NewOrderHandler initializes the order handler with routes on router. Commands go to service,
+// reads are served from the read model through queries.
+func NewOrderHandler(router fiber.Router, service services.OrderService, queries services.OrderQueryService) {
+	handler := &OrderHandler{
+		orders: handlers.NewOrderHandler(service, queries, handlers.OrderRepresentation{
+			Order:   func(order dto.OrderResponse) interface{} { return NewOrderResponse(order) },
+			List:    func(orders []dto.OrderResponse) interface{} { return NewOrderResponses(orders) },
+			Search:  func(results dto.OrderSearchResponse) interface{} { return NewOrderSearchResponse(results) },
+			History: func(history []dto.CustomerOrderHistoryResponse) interface{} { return history },
+			// v1 answers an empty order list with 404, v2 with an empty list
+			EmptyListNotFound: true,
+		}),
+	}
+	router.Post("/orders", handler.CreateOrder)
+	router.Get("/orders/search", handler.SearchOrders)
+	router.Get("/orders/:id", handler.GetOrderByID)
+	router.Get("/orders", handler.GetAllOrders)
+	router.Post("/orders/:id/items", handler.AddItemToOrder)
+	router.Post("/orders/:id/cancel", handler.CancelOrder)
+	router.Put("/orders/:id", handler.UpdateOrder)
+	router.Patch("/orders/:id", handler.PatchOrder)
+	router.Delete("/orders/:id", handler.DeleteOrder)
+	router.Get("/customers/:id/orders", handler.GetCustomerOrderHistory)
+}
+
+// CreateOrder godoc
+// @Summary Create a new order
+// @Description Create a new order with items
+// @Tags orders
+// @Deprecated
+// @Accept json
+// @Produce json
+// @Param order body dto.OrderCreateDto true "Order"
+// @Param Idempotency-Key header string false "Retries with the same key replay the first response"
+// @Success 201 {object} OrderResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /orders [post]
+func (h *OrderHandler) CreateOrder(c *fiber.Ctx) error {
+	return h.orders.CreateOrder(c)
+}
+
+// GetOrderByID godoc
+// @Summary Get order by ID
+// @Description Get order details by ID
+// @Tags orders
+// @Deprecated
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param If-None-Match header string false "ETag of a cached copy, answered with 304 while it is current"
+// @Success 200 {object} OrderResponse
+// @Success 304
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /orders/{id} [get]
+func (h *OrderHandler) GetOrderByID(c *fiber.Ctx) error {
+	return h.orders.GetOrderByID(c)
+}
+
+// GetAllOrders godoc
+// @Summary Get all orders
+// @Description Get a list of all orders
+// @Tags orders
+// @Deprecated
+// @Produce json
+// @Param If-None-Match header string false "ETag of a cached copy, answered with 304 while it is current"
+// @Success 200 {array} OrderResponse
+// @Success 304
+// @Success 404 {array} OrderResponse
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /orders [get]
+func (h *OrderHandler) GetAllOrders(c *fiber.Ctx) error {
+	return h.orders.GetAllOrders(c)
+}
+
+// SearchOrders godoc
+// @Summary Search orders
+// @Description Full-text search over order references, customer names and products, ranked by relevance
+// @Tags orders
+// @Deprecated
+// @Produce json
+// @Param q query string true "Search text, each word matches as a prefix"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Results per page, at most 100"
+// @Success 200 {object} OrderSearchResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Failure 501 {object} dto.ProblemDetails "Orders are stored in DynamoDB, which has no search index"
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /orders/search [get]
+func (h *OrderHandler) SearchOrders(c *fiber.Ctx) error {
+	return h.orders.SearchOrders(c)
+}
+
+// AddItemToOrder godoc
+// @Summary Add item to order
+// @Description Add a new item to an existing order
+// @Tags orders
+// @Deprecated
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param If-Match header string false "ETag of the order the change is based on, answered with 412 once it is stale"
+// @Param Idempotency-Key header string false "Retries with the same key replay the first response"
+// @Param item body dto.OrderItemDto true "Order Item"
+// @Success 200 {object} OrderResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /orders/{id}/items [post]
+func (h *OrderHandler) AddItemToOrder(c *fiber.Ctx) error {
+	return h.orders.AddItemToOrder(c)
+}
+
+// CancelOrder godoc
+// @Summary Cancel an order
+// @Description Cancel a pending order, cancelled orders no longer accept items
+// @Tags orders
+// @Deprecated
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param If-Match header string false "ETag of the order the change is based on, answered with 412 once it is stale"
+// @Param Idempotency-Key header string false "Retries with the same key replay the first response"
+// @Success 200 {object} OrderResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /orders/{id}/cancel [post]
+func (h *OrderHandler) CancelOrder(c *fiber.Ctx) error {
+	return h.orders.CancelOrder(c)
+}
+
+// UpdateOrder godoc
+// @Summary Replace an order's mutable fields
+// @Description Replace the customer name and items of a pending order
+// @Tags orders
+// @Deprecated
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param If-Match header string false "ETag of the order the change is based on, answered with 412 once it is stale"
+// @Param order body dto.OrderUpdateDto true "Mutable order fields"
+// @Success 200 {object} OrderResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /orders/{id} [put]
+func (h *OrderHandler) UpdateOrder(c *fiber.Ctx) error {
+	return h.orders.UpdateOrder(c)
+}
+
+// PatchOrder godoc
+// @Summary Patch an order's mutable fields
+// @Description Apply a JSON Merge Patch (application/merge-patch+json) or a JSON Patch (application/json-patch+json) to the customer name and items of a pending order
+// @Tags orders
+// @Deprecated
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param If-Match header string false "ETag of the order the change is based on, answered with 412 once it is stale"
+// @Param Idempotency-Key header string false "Retries with the same key replay the first response"
+// @Param patch body object true "Merge patch object or array of JSON Patch operations"
+// @Success 200 {object} OrderResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 415 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /orders/{id} [patch]
+func (h *OrderHandler) PatchOrder(c *fiber.Ctx) error {
+	return h.orders.PatchOrder(c)
+}
+
+// DeleteOrder godoc
+// @Summary Delete an order
+// @Description Soft-delete an order; it is kept for auditing but no longer returned or changed
+// @Tags orders
+// @Deprecated
+// @Param id path int true "Order ID"
+// @Param If-Match header string false "ETag of the order the change is based on, answered with 412 once it is stale"
+// @Success 204
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /orders/{id} [delete]
+func (h *OrderHandler) DeleteOrder(c *fiber.Ctx) error {
+	return h.orders.DeleteOrder(c)
+}
+
+// GetCustomerOrderHistory godoc
+// @Summary Get a customer's order history
+// @Description Get the orders placed by a customer, newest first
+// @Tags orders
+// @Deprecated
+// @Produce json
+// @Param id path int true "Customer ID"
+// @Success 200 {array} dto.CustomerOrderHistoryResponse
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 401 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Router /customers/{id}/orders [get]
+func (h *OrderHandler) GetCustomerOrderHistory(c *fiber.Ctx) error {
+	return h.orders.GetCustomerOrderHistory(c)
+}
@@ -0,0 +1,299 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"order-service/internal/application/dto"
+	"order-service/internal/application/handlers"
+	"order-service/internal/application/patch"
+	"order-service/internal/domain/repositories"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOrderService is a mock implementation of the OrderService
+type MockOrderService struct {
+	mock.Mock
+}
+
+func (m *MockOrderService) GetOrderByID(ctx context.Context, id uint) (*dto.OrderResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*dto.OrderResponse), args.Error(1)
+}
+
+func (m *MockOrderService) CreateOrder(ctx context.Context, order dto.OrderCreateDto) (dto.OrderResponse, error) {
+	args := m.Called(ctx, order)
+	return args.Get(0).(dto.OrderResponse), args.Error(1)
+}
+
+func (m *MockOrderService) AddItemToOrder(ctx context.Context, orderID uint, item dto.OrderItemDto) (*dto.OrderResponse, error) {
+	args := m.Called(ctx, orderID, item)
+	return args.Get(0).(*dto.OrderResponse), args.Error(1)
+}
+
+func (m *MockOrderService) CancelOrder(ctx context.Context, id uint) (*dto.OrderResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*dto.OrderResponse), args.Error(1)
+}
+
+func (m *MockOrderService) UpdateOrder(ctx context.Context, id uint, order dto.OrderUpdateDto) (*dto.OrderResponse, error) {
+	args := m.Called(ctx, id, order)
+	return args.Get(0).(*dto.OrderResponse), args.Error(1)
+}
+
+func (m *MockOrderService) PatchOrder(ctx context.Context, id uint, orderPatch patch.Patch) (*dto.OrderResponse, error) {
+	args := m.Called(ctx, id, orderPatch)
+	return args.Get(0).(*dto.OrderResponse), args.Error(1)
+}
+
+func (m *MockOrderService) DeleteOrder(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockOrderService) SearchOrders(ctx context.Context, text string, page, pageSize int) (dto.OrderSearchResponse, error) {
+	args := m.Called(ctx, text, page, pageSize)
+	return args.Get(0).(dto.OrderSearchResponse), args.Error(1)
+}
+
+// MockOrderQueryService is a mock implementation of the OrderQueryService
+type MockOrderQueryService struct {
+	mock.Mock
+}
+
+func (m *MockOrderQueryService) GetOrderByID(ctx context.Context, id uint) (*dto.OrderResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*dto.OrderResponse), args.Error(1)
+}
+
+func (m *MockOrderQueryService) GetAllOrders(ctx context.Context) ([]dto.OrderResponse, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]dto.OrderResponse), args.Error(1)
+}
+
+func (m *MockOrderQueryService) GetCustomerOrderHistory(ctx context.Context, customerID uint) ([]dto.CustomerOrderHistoryResponse, error) {
+	args := m.Called(ctx, customerID)
+	return args.Get(0).([]dto.CustomerOrderHistoryResponse), args.Error(1)
+}
+
+// TestGetOrderByID tests the GetOrderByID handler for a successful case
+func TestGetOrderByID(t *testing.T) {
+	// Create a new Fiber app
+	app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler})
+
+	// Create mock order command and query services
+	mockService := new(MockOrderService)
+	mockQueries := new(MockOrderQueryService)
+
+	// Create a sample order response
+	sampleOrder := &dto.OrderResponse{
+		OrderID:    "Test-123",
+		CustomerID: 123,
+		Items: []dto.OrderItemResponse{
+			{ProductID: 1, Quantity: 2, Price: 9.99},
+		},
+		TotalAmount: 19.98,
+		Status:      "pending",
+	}
+
+	// Set up mock expectations
+	mockQueries.On("GetOrderByID", mock.Anything, uint(1)).Return(sampleOrder, nil)
+
+	// Initialize the order handler with routes
+	NewOrderHandler(app, mockService, mockQueries)
+
+	// Create a new HTTP request
+	req := httptest.NewRequest("GET", "/orders/1", nil)
+	req.Header.Set("Content-Type", "application/json")
+
+	// Create a new HTTP response recorder
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Verify the response body keeps the v1 shape
+	var orderResponse map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&orderResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"order_id":     "Test-123",
+		"customer_id":  float64(123),
+		"items":        []interface{}{map[string]interface{}{"product_id": float64(1), "quantity": float64(2), "price": 9.99}},
+		"total_amount": 19.98,
+	}, orderResponse)
+
+	// Assert that the expectations were met
+	mockQueries.AssertExpectations(t)
+}
+
+// TestGetOrderByIDNotFound tests that a missing order is answered with 404 and its error code
+func TestGetOrderByIDNotFound(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler})
+	mockQueries := new(MockOrderQueryService)
+	mockQueries.On("GetOrderByID", mock.Anything, uint(7)).Return((*dto.OrderResponse)(nil), repositories.ErrOrderNotFound)
+
+	NewOrderHandler(app, new(MockOrderService), mockQueries)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/orders/7", nil), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	var body dto.ProblemDetails
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "order_not_found", body.Code)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/orders/abc", nil), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	mockQueries.AssertExpectations(t)
+}
+
+// TestGetAllOrdersEmpty tests that v1 still answers an empty order list with 404 and an empty array
+func TestGetAllOrdersEmpty(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler})
+	mockQueries := new(MockOrderQueryService)
+	mockQueries.On("GetAllOrders", mock.Anything).Return([]dto.OrderResponse{}, nil)
+	NewOrderHandler(app, new(MockOrderService), mockQueries)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/orders", nil), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	var body []OrderResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, []OrderResponse{}, body)
+	mockQueries.AssertExpectations(t)
+}
+
+// TestGetOrderByIDNotModified tests that a client holding the current ETag gets 304 without a body
+func TestGetOrderByIDNotModified(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler})
+	mockQueries := new(MockOrderQueryService)
+	mockQueries.On("GetOrderByID", mock.Anything, uint(1)).Return(&dto.OrderResponse{OrderID: "Test-123", Version: 4}, nil)
+	NewOrderHandler(app, new(MockOrderService), mockQueries)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/orders/1", nil), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	etag := resp.Header.Get("ETag")
+	assert.Equal(t, `"4"`, etag)
+
+	req := httptest.NewRequest("GET", "/orders/1", nil)
+	req.Header.Set("If-None-Match", etag)
+	resp, err = app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+	mockQueries.AssertExpectations(t)
+}
+
+// TestCreateOrderRejectsInvalidJSON tests that unknown fields and wrong types are reported per field
+func TestCreateOrderRejectsInvalidJSON(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler})
+	mockService := new(MockOrderService)
+	NewOrderHandler(app, mockService, new(MockOrderQueryService))
+
+	tests := []struct {
+		body  string
+		field dto.FieldError
+	}{
+		{`{"OrderID":"a","Discount":5}`, dto.FieldError{Field: "Discount", Message: "is not a known field"}},
+		{`{"OrderID":"a","OrderItems":[{"Quantity":"two"}]}`, dto.FieldError{Field: "OrderItems[0].Quantity", Message: "must be a int"}},
+		{`{"OrderID":"a","OrderDate":"27/10/2024"}`, dto.FieldError{Message: "timestamps must use RFC 3339, such as 2024-10-27T14:00:00Z"}},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("POST", "/orders", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var body dto.ProblemDetails
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, "validation_failed", body.Code)
+		assert.Equal(t, []dto.FieldError{tt.field}, body.Errors)
+	}
+
+	mockService.AssertNotCalled(t, "CreateOrder", mock.Anything, mock.Anything)
+}
+
+// TestPatchOrder tests that the patch format follows the Content-Type and other types are rejected
+func TestPatchOrder(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler})
+	mockService := new(MockOrderService)
+	NewOrderHandler(app, mockService, new(MockOrderQueryService))
+
+	document := `{"CustomerName":"Jane Doe"}`
+	expected := patch.Patch{Format: patch.FormatMergePatch, Document: []byte(document)}
+	mockService.On("PatchOrder", mock.Anything, uint(1), expected).Return(&dto.OrderResponse{OrderID: "Test-123", CustomerName: "Jane Doe"}, nil)
+
+	req := httptest.NewRequest("PATCH", "/orders/1", strings.NewReader(document))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	req = httptest.NewRequest("PATCH", "/orders/1", strings.NewReader(document))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestDeleteOrder tests that deleting answers 204 and a missing order 404
+func TestDeleteOrder(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler})
+	mockService := new(MockOrderService)
+	NewOrderHandler(app, mockService, new(MockOrderQueryService))
+
+	mockService.On("DeleteOrder", mock.Anything, uint(1)).Return(nil)
+	mockService.On("DeleteOrder", mock.Anything, uint(2)).Return(repositories.ErrOrderNotFound)
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/orders/1", nil), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("DELETE", "/orders/2", nil), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	mockService.AssertExpectations(t)
+}
+
+// TestSearchOrders tests that search query parameters reach the service
+func TestSearchOrders(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler})
+	mockService := new(MockOrderService)
+
+	results := dto.OrderSearchResponse{
+		Results:  []dto.OrderSearchResult{{Order: dto.OrderResponse{OrderID: "Test-123"}, Rank: 0.5}},
+		Total:    1,
+		Page:     2,
+		PageSize: 10,
+	}
+	mockService.On("SearchOrders", mock.Anything, "jane widget", 2, 10).Return(results, nil)
+
+	NewOrderHandler(app, mockService, new(MockOrderQueryService))
+
+	req := httptest.NewRequest("GET", "/orders/search?q=jane+widget&page=2&page_size=10", nil)
+	resp, err := app.Test(req, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body OrderSearchResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, NewOrderSearchResponse(results), body)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/orders/search", nil), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	mockService.AssertExpectations(t)
+}
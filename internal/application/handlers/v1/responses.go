@@ -0,0 +1,78 @@
+package v1
+
+import "order-service/internal/application/dto"
+
+// OrderResponse represents an order response
+type OrderResponse struct {
+	OrderID      string              `json:"order_id"`
+	CustomerID   uint                `json:"customer_id"`
+	CustomerName string              `json:"customer_name,omitempty"`
+	Items        []OrderItemResponse `json:"items"`
+	TotalAmount  float64             `json:"total_amount"`
+}
+
+// OrderItemResponse represents an order item response
+type OrderItemResponse struct {
+	ProductID   uint    `json:"product_id"`
+	ProductName string  `json:"product_name,omitempty"`
+	Quantity    int     `json:"quantity"`
+	Price       float64 `json:"price"`
+}
+
+// OrderSearchResponse represents one page of ranked order search results
+type OrderSearchResponse struct {
+	Results  []OrderSearchResult `json:"results"`
+	Total    int64               `json:"total"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"page_size"`
+}
+
+// OrderSearchResult represents a matching order and its relevance
+type OrderSearchResult struct {
+	Order OrderResponse `json:"order"`
+	Rank  float64       `json:"rank"`
+}
+
+// NewOrderResponse maps an order to its v1 representation
+func NewOrderResponse(order dto.OrderResponse) OrderResponse {
+	items := make([]OrderItemResponse, len(order.Items))
+	for i, item := range order.Items {
+		items[i] = OrderItemResponse{
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			Price:       item.Price,
+		}
+	}
+
+	return OrderResponse{
+		OrderID:      order.OrderID,
+		CustomerID:   order.CustomerID,
+		CustomerName: order.CustomerName,
+		Items:        items,
+		TotalAmount:  order.TotalAmount,
+	}
+}
+
+// NewOrderResponses maps a list of orders to their v1 representation
+func NewOrderResponses(orders []dto.OrderResponse) []OrderResponse {
+	response := make([]OrderResponse, len(orders))
+	for i, order := range orders {
+		response[i] = NewOrderResponse(order)
+	}
+	return response
+}
+
+// NewOrderSearchResponse maps a page of search results to its v1 representation
+func NewOrderSearchResponse(results dto.OrderSearchResponse) OrderSearchResponse {
+	response := OrderSearchResponse{
+		Results:  make([]OrderSearchResult, len(results.Results)),
+		Total:    results.Total,
+		Page:     results.Page,
+		PageSize: results.PageSize,
+	}
+	for i, result := range results.Results {
+		response.Results[i] = OrderSearchResult{Order: NewOrderResponse(result.Order), Rank: result.Rank}
+	}
+	return response
+}
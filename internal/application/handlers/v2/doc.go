@@ -0,0 +1,25 @@
+// Package v2 serves the second version of the order API under /v2. Amounts are money objects
+// with a currency, and orders report their status and date.
+//
+// @title Order Service API
+// @version 2.0
+// @description This is an API for managing orders
+// @termsOfService http://swagger.io/terms/
+// @contact.name API Support
+// @contact.url http://www.swagger.io/support
+// @contact.email support@swagger.io
+// @license.name Apache 2.0
+// @license.url http://www.apache.org/licenses/LICENSE-2.0.html
+// @host localhost:8080
+// @BasePath /v2
+//
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description JWT bearer token, sent as "Bearer <token>". Customers reach only their own orders.
+//
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key of a service-to-service caller, limited to its scopes
+package v2
@@ -0,0 +1,138 @@
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"order-service/internal/application/dto"
+	"order-service/internal/application/handlers"
+	"order-service/internal/application/patch"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOrderService is a mock implementation of the OrderService
+type MockOrderService struct {
+	mock.Mock
+}
+
+func (m *MockOrderService) GetOrderByID(ctx context.Context, id uint) (*dto.OrderResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*dto.OrderResponse), args.Error(1)
+}
+
+func (m *MockOrderService) CreateOrder(ctx context.Context, order dto.OrderCreateDto) (dto.OrderResponse, error) {
+	args := m.Called(ctx, order)
+	return args.Get(0).(dto.OrderResponse), args.Error(1)
+}
+
+func (m *MockOrderService) AddItemToOrder(ctx context.Context, orderID uint, item dto.OrderItemDto) (*dto.OrderResponse, error) {
+	args := m.Called(ctx, orderID, item)
+	return args.Get(0).(*dto.OrderResponse), args.Error(1)
+}
+
+func (m *MockOrderService) CancelOrder(ctx context.Context, id uint) (*dto.OrderResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*dto.OrderResponse), args.Error(1)
+}
+
+func (m *MockOrderService) UpdateOrder(ctx context.Context, id uint, order dto.OrderUpdateDto) (*dto.OrderResponse, error) {
+	args := m.Called(ctx, id, order)
+	return args.Get(0).(*dto.OrderResponse), args.Error(1)
+}
+
+func (m *MockOrderService) PatchOrder(ctx context.Context, id uint, orderPatch patch.Patch) (*dto.OrderResponse, error) {
+	args := m.Called(ctx, id, orderPatch)
+	return args.Get(0).(*dto.OrderResponse), args.Error(1)
+}
+
+func (m *MockOrderService) DeleteOrder(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockOrderService) SearchOrders(ctx context.Context, text string, page, pageSize int) (dto.OrderSearchResponse, error) {
+	args := m.Called(ctx, text, page, pageSize)
+	return args.Get(0).(dto.OrderSearchResponse), args.Error(1)
+}
+
+// MockOrderQueryService is a mock implementation of the OrderQueryService
+type MockOrderQueryService struct {
+	mock.Mock
+}
+
+func (m *MockOrderQueryService) GetOrderByID(ctx context.Context, id uint) (*dto.OrderResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*dto.OrderResponse), args.Error(1)
+}
+
+func (m *MockOrderQueryService) GetAllOrders(ctx context.Context) ([]dto.OrderResponse, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]dto.OrderResponse), args.Error(1)
+}
+
+func (m *MockOrderQueryService) GetCustomerOrderHistory(ctx context.Context, customerID uint) ([]dto.CustomerOrderHistoryResponse, error) {
+	args := m.Called(ctx, customerID)
+	return args.Get(0).([]dto.CustomerOrderHistoryResponse), args.Error(1)
+}
+
+// TestGetOrderByID tests that v2 answers with money objects and the order status
+func TestGetOrderByID(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler})
+	mockQueries := new(MockOrderQueryService)
+	orderDate := time.Date(2024, 10, 27, 14, 0, 0, 0, time.UTC)
+	mockQueries.On("GetOrderByID", mock.Anything, uint(1)).Return(&dto.OrderResponse{
+		OrderID:    "Test-123",
+		CustomerID: 123,
+		Items: []dto.OrderItemResponse{
+			{ProductID: 1, Quantity: 3, Price: 0.1},
+		},
+		TotalAmount: 0.3,
+		Status:      "pending",
+		OrderDate:   orderDate,
+	}, nil)
+	NewOrderHandler(app, new(MockOrderService), mockQueries)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/orders/1", nil), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body OrderResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, OrderResponse{
+		OrderID:    "Test-123",
+		CustomerID: 123,
+		Status:     "pending",
+		OrderDate:  orderDate,
+		Items: []OrderItemResponse{{
+			ProductID: 1,
+			Quantity:  3,
+			UnitPrice: Money{Amount: "0.10", Currency: "USD"},
+			LineTotal: Money{Amount: "0.30", Currency: "USD"},
+		}},
+		Total: Money{Amount: "0.30", Currency: "USD"},
+	}, body)
+	mockQueries.AssertExpectations(t)
+}
+
+// TestGetAllOrdersEmpty tests that v2 answers an empty order list with 200 rather than 404
+func TestGetAllOrdersEmpty(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler})
+	mockQueries := new(MockOrderQueryService)
+	mockQueries.On("GetAllOrders", mock.Anything).Return([]dto.OrderResponse{}, nil)
+	NewOrderHandler(app, new(MockOrderService), mockQueries)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/orders", nil), -1)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body OrderListResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, OrderListResponse{Orders: []OrderResponse{}}, body)
+	mockQueries.AssertExpectations(t)
+}
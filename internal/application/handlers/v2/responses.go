@@ -0,0 +1,130 @@
+package v2
+
+import (
+	"order-service/internal/application/dto"
+	"strconv"
+	"time"
+)
+
+// Currency is the ISO 4217 code of every amount; orders are priced in a single currency
+const Currency = "USD"
+
+// Money is an amount of Currency. Amount is a decimal string so it survives clients that
+// parse JSON numbers as binary floating point.
+type Money struct {
+	Amount   string `json:"amount" example:"19.98"`
+	Currency string `json:"currency" example:"USD"`
+}
+
+// OrderResponse represents an order
+type OrderResponse struct {
+	OrderID      string              `json:"order_id"`
+	CustomerID   uint                `json:"customer_id"`
+	CustomerName string              `json:"customer_name,omitempty"`
+	Status       string              `json:"status" example:"pending"`
+	OrderDate    time.Time           `json:"order_date"`
+	Items        []OrderItemResponse `json:"items"`
+	Total        Money               `json:"total"`
+}
+
+// OrderItemResponse represents an order line
+type OrderItemResponse struct {
+	ProductID   uint   `json:"product_id"`
+	ProductName string `json:"product_name,omitempty"`
+	Quantity    int    `json:"quantity"`
+	UnitPrice   Money  `json:"unit_price"`
+	LineTotal   Money  `json:"line_total"`
+}
+
+// OrderListResponse represents a list of orders
+type OrderListResponse struct {
+	Orders []OrderResponse `json:"orders"`
+}
+
+// OrderSearchResponse represents one page of ranked order search results
+type OrderSearchResponse struct {
+	Results  []OrderSearchResult `json:"results"`
+	Total    int64               `json:"total"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"page_size"`
+}
+
+// OrderSearchResult represents a matching order and its relevance
+type OrderSearchResult struct {
+	Order OrderResponse `json:"order"`
+	Rank  float64       `json:"rank"`
+}
+
+// NewMoney returns amount as Money rounded to cents
+func NewMoney(amount float64) Money {
+	return Money{Amount: strconv.FormatFloat(amount, 'f', 2, 64), Currency: Currency}
+}
+
+// NewOrderResponse maps an order to its v2 representation
+func NewOrderResponse(order dto.OrderResponse) OrderResponse {
+	items := make([]OrderItemResponse, len(order.Items))
+	for i, item := range order.Items {
+		items[i] = OrderItemResponse{
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			UnitPrice:   NewMoney(item.Price),
+			LineTotal:   NewMoney(item.Price * float64(item.Quantity)),
+		}
+	}
+
+	return OrderResponse{
+		OrderID:      order.OrderID,
+		CustomerID:   order.CustomerID,
+		CustomerName: order.CustomerName,
+		Status:       order.Status,
+		OrderDate:    order.OrderDate,
+		Items:        items,
+		Total:        NewMoney(order.TotalAmount),
+	}
+}
+
+// NewOrderListResponse maps a list of orders to its v2 representation
+func NewOrderListResponse(orders []dto.OrderResponse) OrderListResponse {
+	response := OrderListResponse{Orders: make([]OrderResponse, len(orders))}
+	for i, order := range orders {
+		response.Orders[i] = NewOrderResponse(order)
+	}
+	return response
+}
+
+// NewOrderSearchResponse maps a page of search results to its v2 representation
+func NewOrderSearchResponse(results dto.OrderSearchResponse) OrderSearchResponse {
+	response := OrderSearchResponse{
+		Results:  make([]OrderSearchResult, len(results.Results)),
+		Total:    results.Total,
+		Page:     results.Page,
+		PageSize: results.PageSize,
+	}
+	for i, result := range results.Results {
+		response.Results[i] = OrderSearchResult{Order: NewOrderResponse(result.Order), Rank: result.Rank}
+	}
+	return response
+}
+
+// CustomerOrderHistoryResponse represents one order in a customer's history
+type CustomerOrderHistoryResponse struct {
+	OrderID   string    `json:"order_id"`
+	ItemCount int       `json:"item_count"`
+	Total     Money     `json:"total"`
+	OrderDate time.Time `json:"order_date"`
+}
+
+// NewCustomerOrderHistoryResponse maps a customer's order history to its v2 representation
+func NewCustomerOrderHistoryResponse(history []dto.CustomerOrderHistoryResponse) []CustomerOrderHistoryResponse {
+	response := make([]CustomerOrderHistoryResponse, len(history))
+	for i, entry := range history {
+		response[i] = CustomerOrderHistoryResponse{
+			OrderID:   entry.OrderID,
+			ItemCount: entry.ItemCount,
+			Total:     NewMoney(entry.TotalAmount),
+			OrderDate: entry.OrderDate,
+		}
+	}
+	return response
+}
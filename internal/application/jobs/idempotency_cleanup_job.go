@@ -0,0 +1,40 @@
+package jobs
+
+import (
+	"context"
+	"order-service/internal/application/repository"
+	"order-service/internal/infrastructure/logging"
+	"time"
+)
+
+// IdempotencyCleanupJob periodically deletes expired idempotency records
+type IdempotencyCleanupJob struct {
+	store    repository.IdempotencyStore
+	interval time.Duration
+}
+
+func NewIdempotencyCleanupJob(store repository.IdempotencyStore, interval time.Duration) *IdempotencyCleanupJob {
+	return &IdempotencyCleanupJob{store: store, interval: interval}
+}
+
+// Start runs the job in the background until ctx is cancelled
+func (j *IdempotencyCleanupJob) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				deleted, err := j.store.DeleteExpired(ctx, now)
+				if err != nil {
+					logging.Logger.Error().Msgf("failed to delete expired idempotency keys: %v", err)
+					continue
+				}
+				logging.Logger.Debug().Int64("deleted", deleted).Msg("expired idempotency keys deleted")
+			}
+		}
+	}()
+}
@@ -0,0 +1,64 @@
+package jobs
+
+import (
+	"context"
+	"order-service/internal/application/repository"
+	"order-service/internal/infrastructure/logging"
+	"time"
+)
+
+// PartitionMaintenanceJob keeps monthly order partitions created ahead of time and archives old ones
+type PartitionMaintenanceJob struct {
+	store       repository.OrderPartitionStore
+	interval    time.Duration
+	monthsAhead int
+	// archiveAfter is how many whole months stay attached before the current one, zero keeps all
+	archiveAfter int
+}
+
+// NewPartitionMaintenanceJob creates a job running every interval that creates partitions for the
+// current month and monthsAhead more, and archives partitions older than archiveAfter months
+func NewPartitionMaintenanceJob(store repository.OrderPartitionStore, interval time.Duration, monthsAhead, archiveAfter int) *PartitionMaintenanceJob {
+	return &PartitionMaintenanceJob{store: store, interval: interval, monthsAhead: monthsAhead, archiveAfter: archiveAfter}
+}
+
+// Start runs the job once immediately and then in the background until ctx is cancelled
+func (j *PartitionMaintenanceJob) Start(ctx context.Context) {
+	go func() {
+		j.RunOnce(ctx, time.Now())
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				j.RunOnce(ctx, now)
+			}
+		}
+	}()
+}
+
+// RunOnce creates missing partitions and archives expired ones as of now
+func (j *PartitionMaintenanceJob) RunOnce(ctx context.Context, now time.Time) {
+	created, err := j.store.EnsurePartitions(ctx, now, j.monthsAhead+1)
+	if err != nil {
+		logging.Logger.Error().Msgf("failed to create order partitions: %v", err)
+	} else if len(created) > 0 {
+		logging.Logger.Info().Int("created", len(created)).Msg("order partitions created")
+	}
+
+	if j.archiveAfter <= 0 {
+		return
+	}
+	archived, err := j.store.ArchivePartitions(ctx, now.AddDate(0, -j.archiveAfter, 0))
+	if err != nil {
+		logging.Logger.Error().Msgf("failed to archive order partitions: %v", err)
+		return
+	}
+	if len(archived) > 0 {
+		logging.Logger.Info().Int("archived", len(archived)).Msg("order partitions archived")
+	}
+}
@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"context"
+	"order-service/internal/application/services"
+	"order-service/internal/infrastructure/logging"
+	"time"
+)
+
+// RetentionJob periodically applies the retention policy
+type RetentionJob struct {
+	service  services.RetentionService
+	interval time.Duration
+	dryRun   bool
+}
+
+// NewRetentionJob creates a job running every interval; in dry-run mode it only logs reports
+func NewRetentionJob(service services.RetentionService, interval time.Duration, dryRun bool) *RetentionJob {
+	return &RetentionJob{service: service, interval: interval, dryRun: dryRun}
+}
+
+// Start runs the job in the background until ctx is cancelled
+func (j *RetentionJob) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				j.RunOnce(ctx)
+			}
+		}
+	}()
+}
+
+// RunOnce applies the retention policy a single time and logs the outcome
+func (j *RetentionJob) RunOnce(ctx context.Context) {
+	report, err := j.service.Run(ctx, j.dryRun)
+	if err != nil {
+		logging.Logger.Error().Msgf("retention run failed after purging %d order(s): %v", report.Purged, err)
+		return
+	}
+	logging.Logger.Info().
+		Bool("dry_run", report.DryRun).
+		Str("action", report.Action).
+		Time("cutoff", report.Cutoff).
+		Int("matched", report.Matched).
+		Int("purged", report.Purged).
+		Strs("order_ids", report.OrderIDs).
+		Msg("retention run completed")
+}
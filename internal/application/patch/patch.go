@@ -0,0 +1,113 @@
+// Package patch applies JSON Merge Patch (RFC 7386) and JSON Patch (RFC 6902) documents.
+package patch
+
+import (
+	"encoding/json"
+	"mime"
+	"order-service/internal/application/validation"
+	"order-service/internal/domain/domainerr"
+	"reflect"
+)
+
+// Format is the media type of a patch document
+type Format string
+
+const (
+	FormatMergePatch Format = "application/merge-patch+json"
+	FormatJSONPatch  Format = "application/json-patch+json"
+)
+
+// ErrUnsupportedFormat is returned for a patch whose media type is neither format
+var ErrUnsupportedFormat = domainerr.Validation("unsupported_patch_format",
+	"PATCH bodies must be application/merge-patch+json or application/json-patch+json")
+
+// ErrTestFailed is returned when a JSON Patch test operation does not match the current document
+var ErrTestFailed = domainerr.Conflict("patch_test_failed", "a test operation of the patch did not match")
+
+// Patch is a patch document together with its format
+type Patch struct {
+	Format   Format
+	Document []byte
+}
+
+// ParseFormat returns the format named by a Content-Type header
+func ParseFormat(contentType string) (Format, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", ErrUnsupportedFormat
+	}
+	switch format := Format(mediaType); format {
+	case FormatMergePatch, FormatJSONPatch:
+		return format, nil
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// Apply patches the value target points to. The value is encoded to JSON, patched and strictly
+// decoded back, so a patch cannot introduce fields the value does not have.
+func (p Patch) Apply(target interface{}) error {
+	encoded, err := json.Marshal(target)
+	if err != nil {
+		return err
+	}
+	var document interface{}
+	if err := json.Unmarshal(encoded, &document); err != nil {
+		return err
+	}
+
+	switch p.Format {
+	case FormatMergePatch:
+		var mergePatch interface{}
+		if err := json.Unmarshal(p.Document, &mergePatch); err != nil {
+			return invalidPatch("merge patch is not valid JSON", err)
+		}
+		document = MergePatch(document, mergePatch)
+	case FormatJSONPatch:
+		var operations []Operation
+		if err := validation.DecodeJSON(p.Document, &operations); err != nil {
+			return invalidPatch("JSON Patch must be an array of operations", err)
+		}
+		if document, err = ApplyOperations(document, operations); err != nil {
+			return err
+		}
+	default:
+		return ErrUnsupportedFormat
+	}
+
+	patched, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+	// Decode into a zero value so members the patch removed do not keep their old values
+	result := reflect.New(reflect.TypeOf(target).Elem())
+	if err := validation.DecodeJSON(patched, result.Interface()); err != nil {
+		return err
+	}
+	reflect.ValueOf(target).Elem().Set(result.Elem())
+	return nil
+}
+
+// MergePatch applies an RFC 7386 merge patch: objects are merged recursively, null removes a
+// member, and any other value replaces the target
+func MergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = MergePatch(targetObject[name], value)
+	}
+	return targetObject
+}
+
+func invalidPatch(message string, err error) error {
+	return domainerr.Validation("invalid_patch", message).Wrap(err)
+}
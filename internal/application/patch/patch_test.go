@@ -0,0 +1,98 @@
+package patch
+
+import (
+	"errors"
+	"order-service/internal/application/dto"
+	"order-service/internal/domain/domainerr"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func currentOrder() dto.OrderUpdateDto {
+	return dto.OrderUpdateDto{
+		CustomerName: "Jane",
+		OrderItems: []dto.OrderItemDto{
+			{ProductID: 1, ProductName: "Widget", Quantity: 2, Price: 9.99},
+			{ProductID: 2, ProductName: "Gadget", Quantity: 1, Price: 5},
+		},
+	}
+}
+
+// TestMergePatch tests that a merge patch replaces named members and leaves the rest alone
+func TestMergePatch(t *testing.T) {
+	order := currentOrder()
+	err := Patch{Format: FormatMergePatch, Document: []byte(`{"CustomerName":"Jane Doe"}`)}.Apply(&order)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane Doe", order.CustomerName)
+	assert.Equal(t, currentOrder().OrderItems, order.OrderItems)
+
+	err = Patch{Format: FormatMergePatch, Document: []byte(`{"OrderItems":[{"ProductID":3,"Quantity":1,"Price":2}],"CustomerName":null}`)}.Apply(&order)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.OrderUpdateDto{OrderItems: []dto.OrderItemDto{{ProductID: 3, Quantity: 1, Price: 2}}}, order)
+}
+
+// TestJSONPatch tests add, replace, remove, move, copy and test operations
+func TestJSONPatch(t *testing.T) {
+	order := currentOrder()
+	err := Patch{Format: FormatJSONPatch, Document: []byte(`[
+		{"op":"test","path":"/CustomerName","value":"Jane"},
+		{"op":"replace","path":"/OrderItems/0/Quantity","value":5},
+		{"op":"add","path":"/OrderItems/-","value":{"ProductID":3,"ProductName":"Gizmo","Quantity":1,"Price":1.5}},
+		{"op":"remove","path":"/OrderItems/1"},
+		{"op":"copy","from":"/OrderItems/1/ProductName","path":"/CustomerName"},
+		{"op":"move","from":"/OrderItems/1","path":"/OrderItems/0"}
+	]`)}.Apply(&order)
+
+	assert.NoError(t, err)
+	assert.Equal(t, dto.OrderUpdateDto{
+		CustomerName: "Gizmo",
+		OrderItems: []dto.OrderItemDto{
+			{ProductID: 3, ProductName: "Gizmo", Quantity: 1, Price: 1.5},
+			{ProductID: 1, ProductName: "Widget", Quantity: 5, Price: 9.99},
+		},
+	}, order)
+}
+
+// TestJSONPatchFailures tests that failing patches leave the target unchanged and report why
+func TestJSONPatchFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		code     string
+	}{
+		{"failed test", `[{"op":"test","path":"/CustomerName","value":"John"}]`, "patch_test_failed"},
+		{"missing member", `[{"op":"remove","path":"/Discount"}]`, "invalid_patch"},
+		{"index out of range", `[{"op":"replace","path":"/OrderItems/5/Quantity","value":1}]`, "invalid_patch"},
+		{"unknown operation", `[{"op":"increment","path":"/OrderItems/0/Quantity"}]`, "invalid_patch"},
+		{"not an array", `{"op":"remove","path":"/CustomerName"}`, "invalid_patch"},
+		{"unknown field added", `[{"op":"add","path":"/Discount","value":5}]`, "validation_failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := currentOrder()
+			err := Patch{Format: FormatJSONPatch, Document: []byte(tt.document)}.Apply(&order)
+
+			var domainErr *domainerr.Error
+			assert.True(t, errors.As(err, &domainErr))
+			assert.Equal(t, tt.code, domainErr.Code)
+			assert.Equal(t, currentOrder(), order)
+		})
+	}
+}
+
+// TestParseFormat tests that only the two patch media types are accepted
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("application/merge-patch+json; charset=utf-8")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatMergePatch, format)
+
+	format, err = ParseFormat("application/json-patch+json")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatJSONPatch, format)
+
+	_, err = ParseFormat("application/json")
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
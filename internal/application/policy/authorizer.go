@@ -0,0 +1,92 @@
+package policy
+
+import (
+	"context"
+	"order-service/internal/application/auth"
+	"order-service/internal/domain/domainerr"
+	"order-service/internal/domain/repositories"
+	"order-service/internal/infrastructure/logging"
+)
+
+// ErrDenied is returned when the policy does not grant the action to the principal
+var ErrDenied = domainerr.Forbidden("forbidden", "you are not allowed to perform this request")
+
+// ErrScopeMissing is returned when an API key lacks the scope a request needs
+var ErrScopeMissing = domainerr.Forbidden("insufficient_scope", "the API key does not have the scope this request needs")
+
+// Denial is an audited refusal
+type Denial struct {
+	Principal auth.Principal
+	Action    Action
+	Resource  Resource
+	Reason    Reason
+}
+
+// Auditor records every request the policy refuses
+type Auditor interface {
+	Denied(ctx context.Context, denial Denial)
+}
+
+// LogAuditor writes denials to the log
+type LogAuditor struct{}
+
+func (LogAuditor) Denied(ctx context.Context, denial Denial) {
+	logging.Ctx(ctx).Warn().
+		Str("audit", "authorization_denied").
+		Str("principal", denial.Principal.Subject).
+		Strs("roles", denial.Principal.Roles).
+		Str("action", string(denial.Action)).
+		Str("resource_type", string(denial.Resource.Type)).
+		Uint("resource_id", denial.Resource.ID).
+		Str("reason", string(denial.Reason)).
+		Msg("authorization denied")
+}
+
+// Authorizer applies a policy to the principal of a request and audits what it refuses
+type Authorizer struct {
+	policy  *Policy
+	auditor Auditor
+}
+
+func NewAuthorizer(policy *Policy, auditor Auditor) *Authorizer {
+	return &Authorizer{policy: policy, auditor: auditor}
+}
+
+// Authorize returns nil when the caller in ctx may perform action on resource. Another customer's
+// order is reported as not found, so customers cannot probe which orders exist.
+func (a *Authorizer) Authorize(ctx context.Context, action Action, resource Resource) error {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return auth.ErrUnauthenticated
+	}
+
+	decision := a.policy.Evaluate(principal, action, resource)
+	if decision.Allowed {
+		return nil
+	}
+	a.auditor.Denied(ctx, Denial{Principal: principal, Action: action, Resource: resource, Reason: decision.Reason})
+
+	switch {
+	case decision.Reason == ReasonScopeMissing:
+		return ErrScopeMissing
+	case decision.Reason == ReasonNotOwner && resource.Type == ResourceOrder && resource.ID != 0:
+		return repositories.ErrOrderNotFound
+	}
+	return ErrDenied
+}
+
+// Scope returns whose resources the caller in ctx may perform action on: all is true when the
+// action is granted on every resource, and otherwise customerID is the only owner it is granted on
+func (a *Authorizer) Scope(ctx context.Context, action Action) (customerID uint, all bool, err error) {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok {
+		return 0, false, auth.ErrUnauthenticated
+	}
+	if a.policy.Evaluate(principal, action, Resource{}).Allowed {
+		return 0, true, nil
+	}
+	if err := a.Authorize(ctx, action, Resource{OwnerID: principal.CustomerID()}); err != nil {
+		return 0, false, err
+	}
+	return principal.CustomerID(), false, nil
+}
@@ -0,0 +1,247 @@
+// Package policy decides which principal may perform which action on which resource.
+//
+// A policy grants actions to roles. A grant either covers every resource or, with owner "self",
+// only the resources of the customer the principal acts for. Roles may inherit the grants of
+// other roles, and a principal without roles has the customer role. Principals authenticated
+// by an API key are further limited to the actions the key's scopes allow. Actions in grants
+// and scopes may end in * to match every action with that prefix, and * alone matches every
+// action.
+//
+//	{
+//	  "roles": {
+//	    "customer": {"grants": [{"actions": ["orders:read"], "owner": "self"}]},
+//	    "staff": {"grants": [{"actions": ["orders:*"]}]},
+//	    "admin": {"inherits": ["staff"], "grants": [{"actions": ["api_keys:manage"]}]}
+//	  },
+//	  "scopes": {"orders:read": ["orders:read", "orders:list"]}
+//	}
+package policy
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"order-service/internal/application/auth"
+	"strings"
+)
+
+// Action is an operation a principal asks to perform
+type Action string
+
+const (
+	CreateOrder  Action = "orders:create"
+	ReadOrder    Action = "orders:read"
+	ListOrders   Action = "orders:list"
+	SearchOrders Action = "orders:search"
+	AddItem      Action = "orders:add_item"
+	CancelOrder  Action = "orders:cancel"
+	UpdateOrder  Action = "orders:update"
+	DeleteOrder  Action = "orders:delete"
+	OrderHistory Action = "orders:history"
+	ImportOrders Action = "orders:import"
+	ExportOrders Action = "orders:export"
+
+	SetLegalHold    Action = "retention:legal_hold"
+	RetentionReport Action = "retention:report"
+
+	ManageAPIKeys Action = "api_keys:manage"
+	PinPrimary    Action = "database:pin_primary"
+	ReadMetrics   Action = "metrics:read"
+)
+
+// ResourceType names a kind of resource
+type ResourceType string
+
+const (
+	ResourceOrder    ResourceType = "order"
+	ResourceCustomer ResourceType = "customer"
+	ResourceSystem   ResourceType = "system"
+)
+
+// Resource is what an action is performed on. OwnerID is the customer it belongs to, or 0
+// when it belongs to no customer or is not known yet.
+type Resource struct {
+	Type    ResourceType
+	ID      uint
+	OwnerID uint
+}
+
+// Order is the order id of customer ownerID, or any order when id is 0
+func Order(id, ownerID uint) Resource {
+	return Resource{Type: ResourceOrder, ID: id, OwnerID: ownerID}
+}
+
+// Customer is the customer id and everything that belongs to them
+func Customer(id uint) Resource {
+	return Resource{Type: ResourceCustomer, ID: id, OwnerID: id}
+}
+
+// System is a resource that belongs to no customer, such as the API keys or a bulk export
+func System() Resource {
+	return Resource{Type: ResourceSystem}
+}
+
+// Reason explains a decision
+type Reason string
+
+const (
+	ReasonGranted      Reason = "granted"
+	ReasonNotGranted   Reason = "not_granted"
+	ReasonNotOwner     Reason = "not_owner"
+	ReasonScopeMissing Reason = "scope_missing"
+)
+
+// Decision is the outcome of evaluating a request
+type Decision struct {
+	Allowed bool
+	Reason  Reason
+}
+
+// OwnerSelf limits a grant to the resources of the customer the principal acts for
+const OwnerSelf = "self"
+
+// Grant allows actions, on every resource or only on the principal's own when Owner is "self"
+type Grant struct {
+	Actions []string `json:"actions"`
+	Owner   string   `json:"owner,omitempty"`
+}
+
+// Role is a named set of grants
+type Role struct {
+	Inherits []string `json:"inherits,omitempty"`
+	Grants   []Grant  `json:"grants"`
+}
+
+// Policy holds the grants of every role and the actions of every API key scope
+type Policy struct {
+	Roles  map[string]Role     `json:"roles"`
+	Scopes map[string][]string `json:"scopes"`
+}
+
+//go:embed default_policy.json
+var defaultPolicy []byte
+
+// Default returns the built-in policy: customers manage their own orders, staff and services
+// manage every order, and admins also manage API keys
+func Default() *Policy {
+	policy, err := Parse(defaultPolicy)
+	if err != nil {
+		panic(fmt.Sprintf("policy: default policy is invalid: %v", err))
+	}
+	return policy
+}
+
+// Parse reads a policy from JSON and checks that every inherited role exists and that the
+// inheritance has no cycles
+func Parse(data []byte) (*Policy, error) {
+	var policy Policy
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("invalid policy: %w", err)
+	}
+	if len(policy.Roles) == 0 {
+		return nil, fmt.Errorf("invalid policy: no roles are defined")
+	}
+
+	for name, role := range policy.Roles {
+		for _, grant := range role.Grants {
+			if grant.Owner != "" && grant.Owner != OwnerSelf {
+				return nil, fmt.Errorf("invalid policy: role %s has unknown owner %q", name, grant.Owner)
+			}
+		}
+		if err := policy.checkInheritance(name, map[string]bool{}); err != nil {
+			return nil, err
+		}
+	}
+	return &policy, nil
+}
+
+func (p *Policy) checkInheritance(name string, visiting map[string]bool) error {
+	if visiting[name] {
+		return fmt.Errorf("invalid policy: role %s inherits itself", name)
+	}
+	visiting[name] = true
+	defer delete(visiting, name)
+
+	for _, parent := range p.Roles[name].Inherits {
+		if _, ok := p.Roles[parent]; !ok {
+			return fmt.Errorf("invalid policy: role %s inherits unknown role %s", name, parent)
+		}
+		if err := p.checkInheritance(parent, visiting); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Evaluate decides whether principal may perform action on resource
+func (p *Policy) Evaluate(principal auth.Principal, action Action, resource Resource) Decision {
+	if principal.APIKeyID != 0 && !p.scopesAllow(principal.Scopes, action) {
+		return Decision{Reason: ReasonScopeMissing}
+	}
+
+	all, own := p.reach(principal, action)
+	switch {
+	case all:
+		return Decision{Allowed: true, Reason: ReasonGranted}
+	case own && resource.OwnerID != 0 && resource.OwnerID == principal.CustomerID():
+		return Decision{Allowed: true, Reason: ReasonGranted}
+	case own:
+		return Decision{Reason: ReasonNotOwner}
+	}
+	return Decision{Reason: ReasonNotGranted}
+}
+
+// reach reports whether the principal's roles grant action on every resource, or only on their own
+func (p *Policy) reach(principal auth.Principal, action Action) (all, own bool) {
+	seen := map[string]bool{}
+	var visit func(name string)
+	visit = func(name string) {
+		role, ok := p.Roles[name]
+		if !ok || seen[name] {
+			return
+		}
+		seen[name] = true
+		for _, grant := range role.Grants {
+			if matchesAny(grant.Actions, action) {
+				if grant.Owner == OwnerSelf {
+					own = true
+				} else {
+					all = true
+				}
+			}
+		}
+		for _, parent := range role.Inherits {
+			visit(parent)
+		}
+	}
+	roles := principal.Roles
+	if len(roles) == 0 {
+		roles = []string{auth.RoleCustomer}
+	}
+	for _, role := range roles {
+		visit(role)
+	}
+	return all, own
+}
+
+func (p *Policy) scopesAllow(scopes []string, action Action) bool {
+	for _, scope := range scopes {
+		if matchesAny(p.Scopes[scope], action) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(patterns []string, action Action) bool {
+	for _, pattern := range patterns {
+		if pattern == string(action) || pattern == "*" ||
+			(strings.HasSuffix(pattern, "*") && strings.HasPrefix(string(action), strings.TrimSuffix(pattern, "*"))) {
+			return true
+		}
+	}
+	return false
+}
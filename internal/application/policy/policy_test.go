@@ -0,0 +1,126 @@
+package policy_test
+
+import (
+	"context"
+	"order-service/internal/application/auth"
+	"order-service/internal/application/policy"
+	"order-service/internal/application/policy/policytest"
+	"order-service/internal/domain/repositories"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	customer   = auth.Principal{Subject: "42", Roles: []string{auth.RoleCustomer}}
+	noRoles    = auth.Principal{Subject: "42"}
+	nonNumeric = auth.Principal{Subject: "alice", Roles: []string{auth.RoleCustomer}}
+	staff      = auth.Principal{Subject: "bob", Roles: []string{auth.RoleStaff}}
+	admin      = auth.Principal{Subject: "root", Roles: []string{auth.RoleAdmin}}
+	readKey    = auth.Principal{Subject: "apikey:1", Roles: []string{auth.RoleService}, Scopes: []string{auth.ScopeOrdersRead}, APIKeyID: 1}
+	adminKey   = auth.Principal{Subject: "apikey:2", Roles: []string{auth.RoleService, auth.RoleAdmin}, Scopes: []string{auth.ScopeAdmin}, APIKeyID: 2}
+)
+
+// TestDefaultPolicy tests that customers reach their own orders and staff, services and admins every order
+func TestDefaultPolicy(t *testing.T) {
+	policytest.Run(t, policy.Default(), []policytest.Case{
+		policytest.Allow("customer reads own order", customer, policy.ReadOrder, policy.Order(1, 42)),
+		policytest.Deny("customer reads another customer's order", customer, policy.ReadOrder, policy.Order(2, 7)),
+		policytest.Allow("customer creates own order", customer, policy.CreateOrder, policy.Order(0, 42)),
+		policytest.Deny("customer creates an order for another customer", customer, policy.CreateOrder, policy.Order(0, 7)),
+		policytest.Allow("customer cancels own order", customer, policy.CancelOrder, policy.Order(1, 42)),
+		policytest.Deny("customer deletes another customer's order", customer, policy.DeleteOrder, policy.Order(2, 7)),
+		policytest.Allow("customer reads own history", customer, policy.OrderHistory, policy.Customer(42)),
+		policytest.Deny("customer reads another customer's history", customer, policy.OrderHistory, policy.Customer(7)),
+		policytest.Deny("customer searches every order", customer, policy.SearchOrders, policy.Order(0, 0)),
+		policytest.Deny("customer exports", customer, policy.ExportOrders, policy.System()),
+		policytest.Allow("principal without roles is a customer", noRoles, policy.ReadOrder, policy.Order(1, 42)),
+		policytest.Deny("non-numeric subject owns nothing", nonNumeric, policy.ReadOrder, policy.Order(0, 0)),
+		policytest.Allow("staff reads any order", staff, policy.ReadOrder, policy.Order(2, 7)),
+		policytest.Allow("staff searches", staff, policy.SearchOrders, policy.Order(0, 0)),
+		policytest.Allow("staff reads the retention report", staff, policy.RetentionReport, policy.System()),
+		policytest.Deny("staff manages API keys", staff, policy.ManageAPIKeys, policy.System()),
+		policytest.Allow("admin manages API keys", admin, policy.ManageAPIKeys, policy.System()),
+		policytest.Deny("staff pins reads to the primary", staff, policy.PinPrimary, policy.System()),
+		policytest.Allow("admin pins reads to the primary", admin, policy.PinPrimary, policy.System()),
+		policytest.Deny("staff reads the expvar metrics", staff, policy.ReadMetrics, policy.System()),
+		policytest.Allow("admin reads the expvar metrics", admin, policy.ReadMetrics, policy.System()),
+		policytest.Allow("admin deletes any order", admin, policy.DeleteOrder, policy.Order(2, 7)),
+		policytest.Allow("read key reads", readKey, policy.ReadOrder, policy.Order(2, 7)),
+		policytest.Allow("read key exports", readKey, policy.ExportOrders, policy.System()),
+		policytest.Deny("read key writes", readKey, policy.CancelOrder, policy.Order(2, 7)),
+		policytest.Deny("read key manages API keys", readKey, policy.ManageAPIKeys, policy.System()),
+		policytest.Allow("admin key manages API keys", adminKey, policy.ManageAPIKeys, policy.System()),
+		policytest.Allow("admin key imports", adminKey, policy.ImportOrders, policy.System()),
+	})
+}
+
+// TestParse tests that policies with unknown fields, owners or roles are rejected
+func TestParse(t *testing.T) {
+	custom, err := policy.Parse([]byte(`{
+		"roles": {
+			"auditor": {"grants": [{"actions": ["orders:read", "orders:list", "retention:*"]}]},
+			"support": {"inherits": ["auditor"], "grants": [{"actions": ["orders:cancel"]}]}
+		}
+	}`))
+	require.NoError(t, err)
+	support := auth.Principal{Subject: "sam", Roles: []string{"support"}}
+	policytest.Run(t, custom, []policytest.Case{
+		policytest.Allow("inherited grant", support, policy.RetentionReport, policy.System()),
+		policytest.Allow("own grant", support, policy.CancelOrder, policy.Order(2, 7)),
+		policytest.Deny("not granted", support, policy.DeleteOrder, policy.Order(2, 7)),
+		policytest.Deny("unknown role", staff, policy.ReadOrder, policy.Order(2, 7)),
+	})
+
+	for name, document := range map[string]string{
+		"not JSON":      `roles`,
+		"no roles":      `{"roles": {}}`,
+		"unknown field": `{"roles": {"staff": {"grants": [{"actions": ["*"], "where": "x"}]}}}`,
+		"unknown owner": `{"roles": {"staff": {"grants": [{"actions": ["*"], "owner": "team"}]}}}`,
+		"unknown role":  `{"roles": {"staff": {"inherits": ["manager"]}}}`,
+		"cycle":         `{"roles": {"a": {"inherits": ["b"]}, "b": {"inherits": ["a"]}}}`,
+	} {
+		_, err := policy.Parse([]byte(document))
+		assert.Error(t, err, name)
+	}
+}
+
+type recordingAuditor struct {
+	denials []policy.Denial
+}
+
+func (r *recordingAuditor) Denied(_ context.Context, denial policy.Denial) {
+	r.denials = append(r.denials, denial)
+}
+
+// TestAuthorizer tests that denials are audited and reported with the right error
+func TestAuthorizer(t *testing.T) {
+	auditor := &recordingAuditor{}
+	authorizer := policy.NewAuthorizer(policy.Default(), auditor)
+	as := func(principal auth.Principal) context.Context {
+		return auth.WithPrincipal(context.Background(), principal)
+	}
+
+	assert.NoError(t, authorizer.Authorize(as(customer), policy.ReadOrder, policy.Order(1, 42)))
+	assert.ErrorIs(t, authorizer.Authorize(as(customer), policy.ReadOrder, policy.Order(2, 7)), repositories.ErrOrderNotFound)
+	assert.ErrorIs(t, authorizer.Authorize(as(customer), policy.CreateOrder, policy.Order(0, 7)), policy.ErrDenied)
+	assert.ErrorIs(t, authorizer.Authorize(as(readKey), policy.CancelOrder, policy.Order(2, 7)), policy.ErrScopeMissing)
+	assert.ErrorIs(t, authorizer.Authorize(context.Background(), policy.ReadOrder, policy.Order(1, 42)), auth.ErrUnauthenticated)
+
+	require.Len(t, auditor.denials, 3)
+	assert.Equal(t, policy.Denial{Principal: customer, Action: policy.ReadOrder, Resource: policy.Order(2, 7), Reason: policy.ReasonNotOwner}, auditor.denials[0])
+	assert.Equal(t, policy.ReasonScopeMissing, auditor.denials[2].Reason)
+
+	customerID, all, err := authorizer.Scope(as(customer), policy.ListOrders)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(42), customerID)
+	assert.False(t, all)
+
+	_, all, err = authorizer.Scope(as(staff), policy.ListOrders)
+	assert.NoError(t, err)
+	assert.True(t, all)
+
+	_, _, err = authorizer.Scope(as(nonNumeric), policy.ListOrders)
+	assert.ErrorIs(t, err, policy.ErrDenied)
+}
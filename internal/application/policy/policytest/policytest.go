@@ -0,0 +1,42 @@
+// Package policytest checks policies against tables of allowed and denied requests.
+package policytest
+
+import (
+	"order-service/internal/application/auth"
+	"order-service/internal/application/policy"
+	"testing"
+)
+
+// Case is one request and whether the policy should allow it
+type Case struct {
+	Name      string
+	Principal auth.Principal
+	Action    policy.Action
+	Resource  policy.Resource
+	Allowed   bool
+}
+
+// Allow is a case the policy should allow
+func Allow(name string, principal auth.Principal, action policy.Action, resource policy.Resource) Case {
+	return Case{Name: name, Principal: principal, Action: action, Resource: resource, Allowed: true}
+}
+
+// Deny is a case the policy should deny
+func Deny(name string, principal auth.Principal, action policy.Action, resource policy.Resource) Case {
+	return Case{Name: name, Principal: principal, Action: action, Resource: resource}
+}
+
+// Run evaluates every case against p as a subtest and fails those decided the other way
+func Run(t *testing.T, p *policy.Policy, cases []Case) {
+	t.Helper()
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			decision := p.Evaluate(tc.Principal, tc.Action, tc.Resource)
+			if decision.Allowed != tc.Allowed {
+				t.Errorf("%s on %s %d (owner %d) by %q with roles %v: allowed = %t (%s), want %t",
+					tc.Action, tc.Resource.Type, tc.Resource.ID, tc.Resource.OwnerID,
+					tc.Principal.Subject, tc.Principal.Roles, decision.Allowed, decision.Reason, tc.Allowed)
+			}
+		})
+	}
+}
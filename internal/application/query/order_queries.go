@@ -0,0 +1,101 @@
+package query
+
+import (
+	"context"
+	"order-service/internal/application/dto"
+	"order-service/internal/application/policy"
+)
+
+// OrderQueries serves order reads from the read model instead of the aggregate. Every read is
+// authorized by the policy, so customers only see their own orders.
+type OrderQueries struct {
+	readModel  ReadModel
+	authorizer *policy.Authorizer
+}
+
+func NewOrderQueries(readModel ReadModel, authorizer *policy.Authorizer) *OrderQueries {
+	return &OrderQueries{readModel: readModel, authorizer: authorizer}
+}
+
+func (q *OrderQueries) GetOrderByID(ctx context.Context, id uint) (*dto.OrderResponse, error) {
+	summary, err := q.readModel.FindOrderSummary(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizer.Authorize(ctx, policy.ReadOrder, policy.Order(id, summary.CustomerID)); err != nil {
+		return nil, err
+	}
+
+	response := convertToOrderResponse(*summary)
+	return &response, nil
+}
+
+func (q *OrderQueries) GetAllOrders(ctx context.Context) ([]dto.OrderResponse, error) {
+	customerID, all, err := q.authorizer.Scope(ctx, policy.ListOrders)
+	if err != nil {
+		return nil, err
+	}
+	var summaries []OrderSummary
+	if all {
+		summaries, err = q.readModel.FindOrderSummaries(ctx)
+	} else {
+		summaries, err = q.readModel.FindCustomerOrderSummaries(ctx, customerID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ordersResponse := make([]dto.OrderResponse, len(summaries))
+	for i, summary := range summaries {
+		ordersResponse[i] = convertToOrderResponse(summary)
+	}
+
+	return ordersResponse, nil
+}
+
+func (q *OrderQueries) GetCustomerOrderHistory(ctx context.Context, customerID uint) ([]dto.CustomerOrderHistoryResponse, error) {
+	if err := q.authorizer.Authorize(ctx, policy.OrderHistory, policy.Customer(customerID)); err != nil {
+		return nil, err
+	}
+
+	history, err := q.readModel.FindCustomerOrderHistory(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.CustomerOrderHistoryResponse, len(history))
+	for i, entry := range history {
+		response[i] = dto.CustomerOrderHistoryResponse{
+			OrderID:     entry.OrderID,
+			ItemCount:   entry.ItemCount,
+			TotalAmount: entry.TotalAmount,
+			OrderDate:   entry.OrderDate,
+		}
+	}
+
+	return response, nil
+}
+
+func convertToOrderResponse(summary OrderSummary) dto.OrderResponse {
+	items := make([]dto.OrderItemResponse, len(summary.Items))
+	for i, item := range summary.Items {
+		items[i] = dto.OrderItemResponse{
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			Price:       item.Price,
+		}
+	}
+
+	return dto.OrderResponse{
+		OrderID:      summary.OrderID,
+		CustomerID:   summary.CustomerID,
+		CustomerName: summary.CustomerName,
+		Items:        items,
+		TotalAmount:  summary.TotalAmount,
+		Status:       summary.Status,
+		OrderDate:    summary.OrderDate,
+		Version:      summary.Version,
+		UpdatedAt:    summary.UpdatedAt,
+	}
+}
@@ -0,0 +1,130 @@
+package query
+
+import (
+	"context"
+	"order-service/internal/application/auth"
+	"order-service/internal/application/dto"
+	"order-service/internal/application/policy"
+	"order-service/internal/domain/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockReadModel is a mock implementation of the ReadModel interface
+type MockReadModel struct {
+	mock.Mock
+}
+
+func (m *MockReadModel) FindOrderSummary(ctx context.Context, id uint) (*OrderSummary, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*OrderSummary), args.Error(1)
+}
+
+func (m *MockReadModel) FindOrderSummaries(ctx context.Context) ([]OrderSummary, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]OrderSummary), args.Error(1)
+}
+
+func (m *MockReadModel) FindCustomerOrderSummaries(ctx context.Context, customerID uint) ([]OrderSummary, error) {
+	args := m.Called(ctx, customerID)
+	return args.Get(0).([]OrderSummary), args.Error(1)
+}
+
+func (m *MockReadModel) FindCustomerOrderHistory(ctx context.Context, customerID uint) ([]CustomerOrderHistory, error) {
+	args := m.Called(ctx, customerID)
+	return args.Get(0).([]CustomerOrderHistory), args.Error(1)
+}
+
+var (
+	staffCtx    = auth.WithPrincipal(context.Background(), auth.Principal{Subject: "staff-1", Roles: []string{auth.RoleStaff}})
+	customerCtx = auth.WithPrincipal(context.Background(), auth.Principal{Subject: "123", Roles: []string{auth.RoleCustomer}})
+	authorizer  = policy.NewAuthorizer(policy.Default(), policy.LogAuditor{})
+)
+
+// TestGetOrderByID tests that an order summary is served as an order response
+func TestGetOrderByID(t *testing.T) {
+	readModel := new(MockReadModel)
+	readModel.On("FindOrderSummary", mock.Anything, uint(1)).Return(&OrderSummary{
+		ID:          1,
+		OrderID:     "test-1",
+		CustomerID:  123,
+		ItemCount:   1,
+		TotalAmount: 19.98,
+		Items:       []OrderSummaryItem{{ProductID: 1, Quantity: 2, Price: 9.99}},
+	}, nil)
+
+	queries := NewOrderQueries(readModel, authorizer)
+
+	order, err := queries.GetOrderByID(staffCtx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, &dto.OrderResponse{
+		OrderID:     "test-1",
+		CustomerID:  123,
+		Items:       []dto.OrderItemResponse{{ProductID: 1, Quantity: 2, Price: 9.99}},
+		TotalAmount: 19.98,
+	}, order)
+
+	readModel.AssertExpectations(t)
+}
+
+// TestGetCustomerOrderHistory tests that history rows are returned in read model order
+func TestGetCustomerOrderHistory(t *testing.T) {
+	newer := time.Date(2025, time.January, 8, 0, 0, 0, 0, time.UTC)
+	older := newer.AddDate(0, -1, 0)
+
+	readModel := new(MockReadModel)
+	readModel.On("FindCustomerOrderHistory", mock.Anything, uint(123)).Return([]CustomerOrderHistory{
+		{CustomerID: 123, OrderID: "test-2", ItemCount: 1, TotalAmount: 5, OrderDate: newer},
+		{CustomerID: 123, OrderID: "test-1", ItemCount: 2, TotalAmount: 19.98, OrderDate: older},
+	}, nil)
+
+	history, err := NewOrderQueries(readModel, authorizer).GetCustomerOrderHistory(customerCtx, 123)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, "test-2", history[0].OrderID)
+	assert.Equal(t, older, history[1].OrderDate)
+
+	readModel.AssertExpectations(t)
+}
+
+// TestGetOrderByIDOtherCustomer tests that a customer cannot tell another customer's order exists
+func TestGetOrderByIDOtherCustomer(t *testing.T) {
+	readModel := new(MockReadModel)
+	readModel.On("FindOrderSummary", mock.Anything, uint(1)).Return(&OrderSummary{ID: 1, OrderID: "test-1", CustomerID: 456}, nil)
+
+	queries := NewOrderQueries(readModel, authorizer)
+
+	_, err := queries.GetOrderByID(customerCtx, 1)
+	assert.ErrorIs(t, err, repositories.ErrOrderNotFound)
+
+	_, err = queries.GetOrderByID(context.Background(), 1)
+	assert.ErrorIs(t, err, auth.ErrUnauthenticated)
+}
+
+// TestGetAllOrdersScope tests that customers list only their own orders while staff list every order
+func TestGetAllOrdersScope(t *testing.T) {
+	readModel := new(MockReadModel)
+	readModel.On("FindCustomerOrderSummaries", mock.Anything, uint(123)).Return([]OrderSummary{{ID: 1, OrderID: "test-1", CustomerID: 123}}, nil)
+	readModel.On("FindOrderSummaries", mock.Anything).Return([]OrderSummary{
+		{ID: 1, OrderID: "test-1", CustomerID: 123},
+		{ID: 2, OrderID: "test-2", CustomerID: 456},
+	}, nil)
+
+	queries := NewOrderQueries(readModel, authorizer)
+
+	orders, err := queries.GetAllOrders(customerCtx)
+	assert.NoError(t, err)
+	assert.Len(t, orders, 1)
+
+	orders, err = queries.GetAllOrders(staffCtx)
+	assert.NoError(t, err)
+	assert.Len(t, orders, 2)
+
+	_, err = queries.GetCustomerOrderHistory(customerCtx, 456)
+	assert.ErrorIs(t, err, policy.ErrDenied)
+
+	readModel.AssertExpectations(t)
+}
@@ -0,0 +1,46 @@
+package query
+
+import (
+	"context"
+	"time"
+)
+
+// OrderSummary is the denormalized read model for a single order
+type OrderSummary struct {
+	ID           uint   `gorm:"primaryKey;autoIncrement:false"`
+	OrderID      string `gorm:"uniqueIndex"`
+	CustomerID   uint
+	CustomerName string
+	ItemCount    int
+	TotalAmount  float64
+	Items        []OrderSummaryItem `gorm:"serializer:json"`
+	Status       string
+	OrderDate    time.Time
+	Version      uint
+	UpdatedAt    time.Time
+}
+
+// OrderSummaryItem is an order line stored inside an OrderSummary
+type OrderSummaryItem struct {
+	ProductID   uint    `json:"product_id"`
+	ProductName string  `json:"product_name,omitempty"`
+	Quantity    int     `json:"quantity"`
+	Price       float64 `json:"price"`
+}
+
+// CustomerOrderHistory is one row of a customer's order history
+type CustomerOrderHistory struct {
+	CustomerID  uint   `gorm:"primaryKey;autoIncrement:false"`
+	OrderID     string `gorm:"primaryKey"`
+	ItemCount   int
+	TotalAmount float64
+	OrderDate   time.Time
+}
+
+// ReadModel is the query-side store kept up to date by the order projections
+type ReadModel interface {
+	FindOrderSummary(ctx context.Context, id uint) (*OrderSummary, error)
+	FindOrderSummaries(ctx context.Context) ([]OrderSummary, error)
+	FindCustomerOrderSummaries(ctx context.Context, customerID uint) ([]OrderSummary, error)
+	FindCustomerOrderHistory(ctx context.Context, customerID uint) ([]CustomerOrderHistory, error)
+}
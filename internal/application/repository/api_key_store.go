@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"order-service/internal/domain/domainerr"
+	"time"
+)
+
+// ErrAPIKeyNotFound is returned when no API key has the requested ID or prefix
+var ErrAPIKeyNotFound = domainerr.NotFound("api_key_not_found", "API key not found")
+
+// APIKey is a credential of a service-to-service caller. Only the SHA-256 hash of its secret is
+// stored; Prefix is the public part of the key that finds it.
+type APIKey struct {
+	ID        uint `gorm:"primaryKey"`
+	Name      string
+	Prefix    string `gorm:"uniqueIndex"`
+	Hash      string
+	Scopes    []string `gorm:"serializer:json"`
+	CreatedAt time.Time
+	ExpiresAt *time.Time
+	RevokedAt *time.Time
+	// RotatedFromID is the key this one replaced, if it was issued by a rotation
+	RotatedFromID *uint
+}
+
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// Active reports whether the key may authenticate requests at now
+func (k APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+type APIKeyStore interface {
+	Create(ctx context.Context, key *APIKey) error
+	FindByID(ctx context.Context, id uint) (*APIKey, error)
+	FindByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+	List(ctx context.Context) ([]APIKey, error)
+	// Rotate creates replacement and moves the expiry of the key it replaces to expiresAt, atomically
+	Rotate(ctx context.Context, replacement *APIKey, expiresAt time.Time) error
+	Revoke(ctx context.Context, id uint, at time.Time) error
+}
@@ -0,0 +1,35 @@
+// Package repository holds the ports for application-level state that is not part of the domain.
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// IdempotencyRecord is a stored request and, once completed, the response to replay for it
+type IdempotencyRecord struct {
+	Key         string `gorm:"primaryKey"`
+	Fingerprint string
+	Completed   bool
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+func (IdempotencyRecord) TableName() string {
+	return "idempotency_keys"
+}
+
+type IdempotencyStore interface {
+	// Reserve claims record.Key for a new request. If an unexpired record already holds
+	// the key it is returned and nothing is stored.
+	Reserve(ctx context.Context, record IdempotencyRecord) (*IdempotencyRecord, error)
+	// Complete stores the response for a reserved key
+	Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error
+	// Release drops a reservation so the request can be retried
+	Release(ctx context.Context, key string) error
+	// DeleteExpired removes every expired record and returns how many were removed
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// OrderPartitionStore maintains the monthly partitions of the orders tables
+type OrderPartitionStore interface {
+	// EnsurePartitions creates the partitions for the month of from and the following months,
+	// returning the months that did not exist yet
+	EnsurePartitions(ctx context.Context, from time.Time, months int) ([]time.Time, error)
+	// ArchivePartitions detaches the partitions of every month before the month of before
+	// and moves them to archive storage, returning the archived months
+	ArchivePartitions(ctx context.Context, before time.Time) ([]time.Time, error)
+}
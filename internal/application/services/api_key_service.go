@@ -0,0 +1,15 @@
+package services
+
+import (
+	"context"
+	"order-service/internal/application/auth"
+	"order-service/internal/application/repository"
+	"time"
+)
+
+type APIKeyService interface {
+	Create(ctx context.Context, name string, scopes []string, expiresAt *time.Time) (auth.IssuedAPIKey, error)
+	Rotate(ctx context.Context, id uint, gracePeriod time.Duration) (auth.IssuedAPIKey, error)
+	Revoke(ctx context.Context, id uint) error
+	List(ctx context.Context) ([]repository.APIKey, error)
+}
@@ -0,0 +1,13 @@
+package services
+
+import (
+	"context"
+	"order-service/internal/application/dto"
+)
+
+type BulkOrderService interface {
+	// Import creates the orders returned by next until it returns io.EOF
+	Import(ctx context.Context, next func() (dto.ImportRow, error)) (dto.ImportReport, error)
+	// Export passes every order to write
+	Export(ctx context.Context, write func(dto.OrderRecord) error) error
+}
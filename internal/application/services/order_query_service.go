@@ -0,0 +1,12 @@
+package services
+
+import (
+	"context"
+	"order-service/internal/application/dto"
+)
+
+type OrderQueryService interface {
+	GetOrderByID(ctx context.Context, id uint) (*dto.OrderResponse, error)
+	GetAllOrders(ctx context.Context) ([]dto.OrderResponse, error)
+	GetCustomerOrderHistory(ctx context.Context, customerID uint) ([]dto.CustomerOrderHistoryResponse, error)
+}
@@ -1,12 +1,18 @@
 package services
 
 import (
+	"context"
 	"order-service/internal/application/dto"
+	"order-service/internal/application/patch"
 )
 
 type OrderService interface {
-	CreateOrder(order dto.OrderCreateDto) (dto.OrderResponse, error)
-	GetOrderByID(id uint) (*dto.OrderResponse, error)
-	GetAllOrders() ([]dto.OrderResponse, error)
-	AddItemToOrder(id uint, item dto.OrderItemDto) (*dto.OrderResponse, error)
+	CreateOrder(ctx context.Context, order dto.OrderCreateDto) (dto.OrderResponse, error)
+	GetOrderByID(ctx context.Context, id uint) (*dto.OrderResponse, error)
+	AddItemToOrder(ctx context.Context, id uint, item dto.OrderItemDto) (*dto.OrderResponse, error)
+	CancelOrder(ctx context.Context, id uint) (*dto.OrderResponse, error)
+	UpdateOrder(ctx context.Context, id uint, order dto.OrderUpdateDto) (*dto.OrderResponse, error)
+	PatchOrder(ctx context.Context, id uint, orderPatch patch.Patch) (*dto.OrderResponse, error)
+	DeleteOrder(ctx context.Context, id uint) error
+	SearchOrders(ctx context.Context, text string, page, pageSize int) (dto.OrderSearchResponse, error)
 }
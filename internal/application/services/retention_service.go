@@ -0,0 +1,11 @@
+package services
+
+import (
+	"context"
+	"order-service/internal/application/dto"
+)
+
+type RetentionService interface {
+	Run(ctx context.Context, dryRun bool) (dto.RetentionReport, error)
+	SetLegalHold(ctx context.Context, id uint, hold bool) error
+}
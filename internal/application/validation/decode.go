@@ -0,0 +1,63 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"order-service/internal/domain/domainerr"
+	"strings"
+	"time"
+)
+
+// DecodeJSON strictly decodes data into v. Unknown fields, values of the wrong type, malformed
+// timestamps and trailing data are rejected with the offending field named where the decoder
+// reports it.
+func DecodeJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(v)
+	if err == nil && decoder.More() {
+		err = errors.New("unexpected data after the JSON value")
+	}
+	if err == nil {
+		return nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	var timeErr *time.ParseError
+	switch {
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return invalidFields(err, domainerr.FieldError{Field: fieldPath(typeErr.Field), Message: "must be a " + typeErr.Type.String()})
+	case errors.As(err, &timeErr):
+		return invalidFields(err, domainerr.FieldError{Message: "timestamps must use RFC 3339, such as 2024-10-27T14:00:00Z"})
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return invalidFields(err, domainerr.FieldError{Field: field, Message: "is not a known field"})
+	case errors.Is(err, io.EOF):
+		return domainerr.Validation("invalid_body", "request body is empty")
+	}
+	return domainerr.Validation("invalid_body", "request body is not valid JSON for this request").Wrap(err)
+}
+
+func invalidFields(err error, fields ...domainerr.FieldError) error {
+	return domainerr.Validation("validation_failed", "request has invalid fields").WithFields(fields...).Wrap(err)
+}
+
+// fieldPath turns the decoder's OrderItems.0.Quantity into OrderItems[0].Quantity, the form
+// Validate reports
+func fieldPath(path string) string {
+	var b strings.Builder
+	for i, part := range strings.Split(path, ".") {
+		switch {
+		case part != "" && strings.Trim(part, "0123456789") == "":
+			b.WriteString("[" + part + "]")
+		case i > 0:
+			b.WriteString("." + part)
+		default:
+			b.WriteString(part)
+		}
+	}
+	return b.String()
+}
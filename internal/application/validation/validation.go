@@ -0,0 +1,133 @@
+// Package validation checks request DTOs against rules declared in `validate` struct tags.
+//
+// Rules are separated by commas:
+//
+//	required   the value must not be the zero value; a slice must not be empty
+//	min=N      numbers must be at least N, strings and slices must have at least N elements
+//	max=N      numbers must be at most N, strings and slices must have at most N elements
+//	gt=N       numbers must be greater than N
+//
+// Nested structs and slices of structs are validated field by field.
+package validation
+
+import (
+	"fmt"
+	"order-service/internal/domain/domainerr"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Validate checks v and returns a validation error listing every invalid field, or nil
+func Validate(v interface{}) error {
+	fields := Fields(v)
+	if len(fields) == 0 {
+		return nil
+	}
+	return domainerr.Validation("validation_failed", "request has invalid fields").WithFields(fields...)
+}
+
+// Fields returns every invalid field of v, named by its path such as OrderItems[0].Quantity
+func Fields(v interface{}) []domainerr.FieldError {
+	var fields []domainerr.FieldError
+	validateValue(reflect.ValueOf(v), "", &fields)
+	return fields
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func validateValue(value reflect.Value, path string, fields *[]domainerr.FieldError) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	switch {
+	case value.Kind() == reflect.Struct && value.Type() != timeType:
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldPath := join(path, field.Name)
+			for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+				if rule == "" {
+					continue
+				}
+				if message := check(rule, value.Field(i)); message != "" {
+					*fields = append(*fields, domainerr.FieldError{Field: fieldPath, Message: message})
+					break
+				}
+			}
+			validateValue(value.Field(i), fieldPath, fields)
+		}
+	case value.Kind() == reflect.Slice || value.Kind() == reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			validateValue(value.Index(i), fmt.Sprintf("%s[%d]", path, i), fields)
+		}
+	}
+}
+
+// check applies one rule to value and returns why it fails, or "" when it holds
+func check(rule string, value reflect.Value) string {
+	name, arg, _ := strings.Cut(rule, "=")
+	switch name {
+	case "required":
+		if value.IsZero() || (value.Kind() == reflect.Slice && value.Len() == 0) {
+			return "is required"
+		}
+		return ""
+	case "min", "max", "gt":
+		limit, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			panic(fmt.Sprintf("validation: rule %q needs a numeric argument", rule))
+		}
+		return checkLimit(name, limit, value)
+	}
+	panic(fmt.Sprintf("validation: unknown rule %q", rule))
+}
+
+func checkLimit(name string, limit float64, value reflect.Value) string {
+	var n float64
+	unit := ""
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = value.Float()
+	case reflect.String:
+		n, unit = float64(utf8.RuneCountInString(value.String())), " characters"
+	case reflect.Slice, reflect.Array:
+		n, unit = float64(value.Len()), " elements"
+	default:
+		panic(fmt.Sprintf("validation: rule %s does not apply to %s", name, value.Kind()))
+	}
+
+	limitText := strconv.FormatFloat(limit, 'f', -1, 64)
+	switch {
+	case name == "min" && n < limit && unit != "":
+		return "must have at least " + limitText + unit
+	case name == "min" && n < limit:
+		return "must be at least " + limitText
+	case name == "max" && n > limit && unit != "":
+		return "must have at most " + limitText + unit
+	case name == "max" && n > limit:
+		return "must be at most " + limitText
+	case name == "gt" && n <= limit:
+		return "must be greater than " + limitText
+	}
+	return ""
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
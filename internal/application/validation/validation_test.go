@@ -0,0 +1,52 @@
+package validation
+
+import (
+	"order-service/internal/application/dto"
+	"order-service/internal/domain/domainerr"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateOrderCreateDto tests that every invalid field of an order is reported with its path
+func TestValidateOrderCreateDto(t *testing.T) {
+	order := dto.OrderCreateDto{
+		CustomerName: strings.Repeat("x", 256),
+		OrderItems: []dto.OrderItemDto{
+			{ProductID: 1, Quantity: 2, Price: 9.99},
+			{Quantity: -1, Price: 0},
+		},
+	}
+
+	err := Validate(order)
+
+	domainErr, ok := domainerr.As(err)
+	assert.True(t, ok)
+	assert.Equal(t, domainerr.KindValidation, domainErr.Kind)
+	assert.Equal(t, []domainerr.FieldError{
+		{Field: "OrderID", Message: "is required"},
+		{Field: "CustomerID", Message: "is required"},
+		{Field: "CustomerName", Message: "must have at most 255 characters"},
+		{Field: "OrderItems[1].ProductID", Message: "is required"},
+		{Field: "OrderItems[1].Quantity", Message: "must be greater than 0"},
+		{Field: "OrderItems[1].Price", Message: "must be greater than 0"},
+		{Field: "OrderDate", Message: "is required"},
+	}, domainErr.Fields)
+}
+
+// TestValidateValidOrder tests that a valid order passes
+func TestValidateValidOrder(t *testing.T) {
+	order := dto.OrderCreateDto{
+		OrderID:    "ORD-1",
+		CustomerID: 7,
+		OrderItems: []dto.OrderItemDto{{ProductID: 1, Quantity: 1, Price: 5}},
+		OrderDate:  time.Now(),
+	}
+
+	assert.NoError(t, Validate(order))
+	assert.Equal(t, []domainerr.FieldError{{Field: "OrderItems", Message: "is required"}}, Fields(dto.OrderCreateDto{
+		OrderID: "ORD-1", CustomerID: 7, OrderDate: time.Now(),
+	}))
+}
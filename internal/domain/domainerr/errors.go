@@ -0,0 +1,121 @@
+// Package domainerr classifies domain failures so the API can answer them with the right status.
+package domainerr
+
+import "errors"
+
+// Kind is the category of a domain failure
+type Kind string
+
+const (
+	KindNotFound              Kind = "not_found"
+	KindValidation            Kind = "validation"
+	KindConflict              Kind = "conflict"
+	KindBusinessRuleViolation Kind = "business_rule_violation"
+	KindUnauthorized          Kind = "unauthorized"
+	KindForbidden             Kind = "forbidden"
+	KindPreconditionFailed    Kind = "precondition_failed"
+	KindNotSupported          Kind = "not_supported"
+)
+
+// Error is a domain failure with a stable machine-readable Code and a Message that is safe to
+// show to clients. Fields lists the invalid fields of a validation failure. Err keeps the
+// underlying cause for logs and is never shown to clients.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+// FieldError is one invalid field of a validation failure
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches any domain error with the same kind and code, so a sentinel still matches after Wrap
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Kind == e.Kind && t.Code == e.Code
+}
+
+// Wrap returns a copy of err that records cause
+func (e *Error) Wrap(cause error) *Error {
+	wrapped := *e
+	wrapped.Err = cause
+	return &wrapped
+}
+
+// WithFields returns a copy of err that lists the invalid fields
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	withFields := *e
+	withFields.Fields = fields
+	return &withFields
+}
+
+// NotFound reports that a requested resource does not exist
+func NotFound(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+// Validation reports input that is malformed or incomplete
+func Validation(code, message string) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message}
+}
+
+// Conflict reports a request that clashes with the current state, such as a duplicate or stale write
+func Conflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+// BusinessRuleViolation reports a well-formed request that a domain rule forbids
+func BusinessRuleViolation(code, message string) *Error {
+	return &Error{Kind: KindBusinessRuleViolation, Code: code, Message: message}
+}
+
+// Unauthorized reports a caller that is not allowed to perform the request
+func Unauthorized(code, message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Message: message}
+}
+
+// Forbidden reports an authenticated caller whose role does not permit the request
+func Forbidden(code, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+// PreconditionFailed reports a conditional request whose condition no longer holds
+func PreconditionFailed(code, message string) *Error {
+	return &Error{Kind: KindPreconditionFailed, Code: code, Message: message}
+}
+
+// NotSupported reports a feature the configured stores cannot provide
+func NotSupported(code, message string) *Error {
+	return &Error{Kind: KindNotSupported, Code: code, Message: message}
+}
+
+// As returns the domain error in err's chain, if any
+func As(err error) (*Error, bool) {
+	var domainErr *Error
+	ok := errors.As(err, &domainErr)
+	return domainErr, ok
+}
+
+// Message returns the client-safe message of a domain error, or fallback for any other error
+func Message(err error, fallback string) string {
+	if domainErr, ok := As(err); ok {
+		return domainErr.Message
+	}
+	return fallback
+}
@@ -0,0 +1,27 @@
+package events
+
+import (
+	"context"
+	"order-service/internal/domain/requestid"
+	"time"
+)
+
+// Metadata describes where and when an event was published
+type Metadata struct {
+	RequestID  string    `json:"request_id,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// Envelope is an event together with its metadata, as it leaves the service
+type Envelope struct {
+	Metadata Metadata    `json:"metadata"`
+	Event    interface{} `json:"event"`
+}
+
+// NewEnvelope wraps event with the request ID carried by ctx, if any
+func NewEnvelope(ctx context.Context, event interface{}) Envelope {
+	return Envelope{
+		Metadata: Metadata{RequestID: requestid.From(ctx), OccurredAt: time.Now().UTC()},
+		Event:    event,
+	}
+}
@@ -0,0 +1,10 @@
+package events
+
+import "time"
+
+type OrderCancelledEvent struct {
+	OrderID   uint
+	Reference string
+	Version   uint
+	UpdatedAt time.Time
+}
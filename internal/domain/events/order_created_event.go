@@ -1,7 +1,35 @@
 package events
 
+import (
+	"order-service/internal/domain/models"
+	"time"
+)
+
 type OrderCreatedEvent struct {
-	OrderID     uint
-	CustomerID  uint
-	TotalAmount float64
+	OrderID      uint
+	CustomerID   uint
+	TotalAmount  float64
+	Reference    string
+	CustomerName string
+	Status       models.OrderStatus
+	OrderDate    time.Time
+	Items        []models.OrderItem
+	Version      uint
+	UpdatedAt    time.Time
+}
+
+// NewOrderCreatedEvent builds the event describing a newly saved order
+func NewOrderCreatedEvent(order models.Order) OrderCreatedEvent {
+	return OrderCreatedEvent{
+		OrderID:      order.ID,
+		CustomerID:   order.CustomerID,
+		TotalAmount:  order.TotalAmount,
+		Reference:    order.OrderID,
+		CustomerName: order.CustomerName,
+		Status:       order.Status,
+		OrderDate:    order.OrderDate,
+		Items:        order.OrderItems,
+		Version:      order.Version,
+		UpdatedAt:    order.UpdatedAt,
+	}
 }
@@ -0,0 +1,6 @@
+package events
+
+type OrderDeletedEvent struct {
+	OrderID   uint
+	Reference string
+}
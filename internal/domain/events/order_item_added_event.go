@@ -0,0 +1,15 @@
+package events
+
+import (
+	"order-service/internal/domain/models"
+	"time"
+)
+
+type OrderItemAddedEvent struct {
+	OrderID     uint
+	Reference   string
+	Item        models.OrderItem
+	TotalAmount float64
+	Version     uint
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,11 @@
+package events
+
+import "time"
+
+type OrderLegalHoldChangedEvent struct {
+	OrderID   uint
+	Reference string
+	LegalHold bool
+	Version   uint
+	UpdatedAt time.Time
+}
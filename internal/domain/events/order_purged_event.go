@@ -0,0 +1,6 @@
+package events
+
+type OrderPurgedEvent struct {
+	OrderID uint
+	Action  string
+}
@@ -0,0 +1,17 @@
+package events
+
+import (
+	"order-service/internal/domain/models"
+	"time"
+)
+
+// OrderUpdatedEvent carries the mutable fields of an order after they were replaced
+type OrderUpdatedEvent struct {
+	OrderID      uint
+	Reference    string
+	CustomerName string
+	Items        []models.OrderItem
+	TotalAmount  float64
+	Version      uint
+	UpdatedAt    time.Time
+}
@@ -1,56 +1,149 @@
 package models
 
 import (
-	"errors"
+	"fmt"
+	"order-service/internal/domain/domainerr"
+	"slices"
+	"strings"
 	"time"
 )
 
+// OrderStatus is where an order is in its lifecycle
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "pending"
+	OrderStatusCancelled OrderStatus = "cancelled"
+)
+
+// ErrOrderCancelled is returned when changing an order that was cancelled
+var ErrOrderCancelled = domainerr.BusinessRuleViolation("order_cancelled", "order is cancelled")
+
+// ErrOrderDeleted is returned when changing an order that was deleted
+var ErrOrderDeleted = domainerr.BusinessRuleViolation("order_deleted", "order is deleted")
+
+// Order is keyed by ID and OrderDate because orders are partitioned by month of OrderDate.
+// Version is incremented by every save, and saving a stale version fails.
+// A deleted order keeps its row with DeletedAt set and is hidden from every read.
 type Order struct {
-	ID          uint   `gorm:"primaryKey;autoIncrement"`
-	OrderID     string `gorm:"uniqueIndex"`
-	CustomerID  uint
-	OrderItems  []OrderItem `gorm:"foreignKey:OrderID;references:OrderID"`
-	TotalAmount float64
-	OrderDate   time.Time
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID           uint   `gorm:"primaryKey;autoIncrement"`
+	OrderID      string `gorm:"uniqueIndex"`
+	CustomerID   uint
+	CustomerName string
+	OrderItems   []OrderItem `gorm:"foreignKey:OrderID;references:OrderID"`
+	TotalAmount  float64
+	Status       OrderStatus `gorm:"default:pending"`
+	OrderDate    time.Time   `gorm:"primaryKey"`
+	LegalHold    bool
+	AnonymizedAt *time.Time
+	DeletedAt    *time.Time
+	Version      uint
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
-// OrderItem struct definition
+// OrderItem struct definition. OrderDate copies the order's date so an item lives in its order's partition.
 type OrderItem struct {
-	ID        uint   `gorm:"primaryKey;autoIncrement"`
-	OrderID   string `gorm:"index"`
-	ProductID uint
-	Quantity  int
-	Price     float64
+	ID          uint      `gorm:"primaryKey;autoIncrement"`
+	OrderID     string    `gorm:"index"`
+	OrderDate   time.Time `gorm:"primaryKey"`
+	ProductID   uint
+	ProductName string
+	Quantity    int
+	Price       float64
 }
 
-// Validate method for the Order struct
+// Validate checks the order and reports every violation at once
 func (o *Order) Validate() error {
+	var fields []domainerr.FieldError
+	invalid := func(field, message string) {
+		fields = append(fields, domainerr.FieldError{Field: field, Message: message})
+	}
+
 	if o.CustomerID == 0 {
-		return errors.New("customer ID is required")
+		invalid("CustomerID", "customer ID is required")
 	}
 	if len(o.OrderItems) == 0 {
-		return errors.New("order must contain at least one item")
+		invalid("OrderItems", "order must contain at least one item")
 	}
-	for _, item := range o.OrderItems {
+	for i, item := range o.OrderItems {
 		if item.Quantity <= 0 {
-			return errors.New("order item quantity must be greater than zero")
+			invalid(fmt.Sprintf("OrderItems[%d].Quantity", i), "order item quantity must be greater than zero")
 		}
 		if item.Price <= 0 {
-			return errors.New("order item price must be greater than zero")
+			invalid(fmt.Sprintf("OrderItems[%d].Price", i), "order item price must be greater than zero")
 		}
 	}
 	if o.TotalAmount <= 0 {
-		return errors.New("total amount must be greater than zero")
+		invalid("TotalAmount", "total amount must be greater than zero")
 	}
 	if o.OrderDate.IsZero() {
-		return errors.New("order date is required")
+		invalid("OrderDate", "order date is required")
 	}
-	return nil
+	if len(fields) == 0 {
+		return nil
+	}
+
+	// The summary names each kind of violation once, the fields list every occurrence
+	var messages []string
+	for _, field := range fields {
+		if !slices.Contains(messages, field.Message) {
+			messages = append(messages, field.Message)
+		}
+	}
+	return domainerr.Validation("invalid_order", strings.Join(messages, "; ")).WithFields(fields...)
 }
 
 func (o *Order) AddItem(item OrderItem) {
 	o.OrderItems = append(o.OrderItems, item)
 	o.TotalAmount += item.Price * float64(item.Quantity)
 }
+
+// EnsureModifiable fails when the order can no longer be changed
+func (o *Order) EnsureModifiable() error {
+	if o.DeletedAt != nil {
+		return ErrOrderDeleted
+	}
+	if o.Status == OrderStatusCancelled {
+		return ErrOrderCancelled
+	}
+	return nil
+}
+
+// Update replaces the customer name and the items of a pending order and recomputes its total
+func (o *Order) Update(customerName string, items []OrderItem) error {
+	if err := o.EnsureModifiable(); err != nil {
+		return err
+	}
+	o.CustomerName = customerName
+	o.OrderItems = nil
+	o.TotalAmount = 0
+	for _, item := range items {
+		item.OrderID = o.OrderID
+		o.AddItem(item)
+	}
+	return o.Validate()
+}
+
+// Cancel moves a pending order to cancelled
+func (o *Order) Cancel() error {
+	if err := o.EnsureModifiable(); err != nil {
+		return err
+	}
+	o.Status = OrderStatusCancelled
+	return nil
+}
+
+// SetLegalHold places the order on legal hold, or releases it. Retention never purges an order on hold.
+func (o *Order) SetLegalHold(hold bool) {
+	o.LegalHold = hold
+}
+
+// Delete soft-deletes the order at now; pending and cancelled orders can be deleted
+func (o *Order) Delete(now time.Time) error {
+	if o.DeletedAt != nil {
+		return ErrOrderDeleted
+	}
+	o.DeletedAt = &now
+	return nil
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RetentionAction is what happens to an order once its retention period is over
+type RetentionAction string
+
+const (
+	RetentionDelete    RetentionAction = "delete"
+	RetentionAnonymize RetentionAction = "anonymize"
+)
+
+// RetentionPolicy decides which orders are purged and how
+type RetentionPolicy struct {
+	Period    time.Duration
+	Action    RetentionAction
+	BatchSize int
+}
+
+// Validate method for the RetentionPolicy struct
+func (p RetentionPolicy) Validate() error {
+	if p.Period <= 0 {
+		return errors.New("retention period must be greater than zero")
+	}
+	if p.Action != RetentionDelete && p.Action != RetentionAnonymize {
+		return fmt.Errorf("unknown retention action %q", p.Action)
+	}
+	if p.BatchSize <= 0 {
+		return errors.New("retention batch size must be greater than zero")
+	}
+	return nil
+}
+
+// Cutoff returns the order date before which orders are past retention
+func (p RetentionPolicy) Cutoff(now time.Time) time.Time {
+	return now.Add(-p.Period)
+}
+
+// OrderPurgeAudit records an order deleted or anonymized by the retention policy
+type OrderPurgeAudit struct {
+	ID          uint   `gorm:"primaryKey;autoIncrement"`
+	OrderID     string `gorm:"index"`
+	Action      RetentionAction
+	OrderDate   time.Time
+	ItemCount   int
+	TotalAmount float64
+	PurgedAt    time.Time
+}
+
+// NewOrderPurgeAudit builds the audit record for purging order with action
+func NewOrderPurgeAudit(order Order, action RetentionAction, at time.Time) OrderPurgeAudit {
+	return OrderPurgeAudit{
+		OrderID:     order.OrderID,
+		Action:      action,
+		OrderDate:   order.OrderDate,
+		ItemCount:   len(order.OrderItems),
+		TotalAmount: order.TotalAmount,
+		PurgedAt:    at,
+	}
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import "context"
+
+type readYourWritesKey struct{}
+
+// WithReadYourWrites marks the context so repository reads observe the caller's own recent writes
+func WithReadYourWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readYourWritesKey{}, true)
+}
+
+// ReadYourWrites reports whether reads for this context must go to the primary
+func ReadYourWrites(ctx context.Context) bool {
+	pinned, _ := ctx.Value(readYourWritesKey{}).(bool)
+	return pinned
+}
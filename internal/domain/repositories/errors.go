@@ -0,0 +1,15 @@
+package repositories
+
+import "order-service/internal/domain/domainerr"
+
+// ErrOrderNotFound is returned when no order has the requested ID
+var ErrOrderNotFound = domainerr.NotFound("order_not_found", "order not found")
+
+// ErrOrderIDTaken is returned by Save when another order already uses the OrderID
+var ErrOrderIDTaken = domainerr.Conflict("order_id_taken", "order ID is already taken")
+
+// ErrOrderModified is returned when a write expects a version of the order that is no longer current
+var ErrOrderModified = domainerr.PreconditionFailed("order_modified", "order has changed since it was read")
+
+// ErrVersionConflict is returned by Save when the order was changed since it was loaded
+var ErrVersionConflict = domainerr.Conflict("version_conflict", "order was modified concurrently")
@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"order-service/internal/domain/models"
+)
+
+// OrderBulkRepository writes and reads orders in batches for imports and exports
+type OrderBulkRepository interface {
+	// CreateBatch inserts new orders and their items atomically, assigning their IDs. A store that
+	// cannot insert a batch atomically returns a *PartialBatchError when it fails partway.
+	CreateBatch(ctx context.Context, orders []models.Order) error
+	// FindInBatches calls fn with successive batches of orders, with their items, in ID order where
+	// the store keeps one; DynamoDB returns them in scan order
+	FindInBatches(ctx context.Context, batchSize int, fn func(orders []models.Order) error) error
+}
+
+// PartialBatchError reports a batch that failed after its first Stored orders were created
+type PartialBatchError struct {
+	Stored int
+	Err    error
+}
+
+func (e *PartialBatchError) Error() string {
+	return fmt.Sprintf("batch failed after %d order(s) were stored: %v", e.Stored, e.Err)
+}
+
+func (e *PartialBatchError) Unwrap() error {
+	return e.Err
+}
@@ -1,9 +1,12 @@
 package repositories
 
-import "order-service/internal/domain/models"
+import (
+	"context"
+	"order-service/internal/domain/models"
+)
 
 type OrderRepository interface {
-	Save(order models.Order) error
-	FindByID(id uint) (*models.Order, error)
-	FindAll() ([]models.Order, error)
+	Save(ctx context.Context, order *models.Order) error
+	FindByID(ctx context.Context, id uint) (*models.Order, error)
+	FindAll(ctx context.Context) ([]models.Order, error)
 }
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+	"order-service/internal/domain/models"
+	"time"
+)
+
+type OrderRetentionRepository interface {
+	// FindExpired returns up to limit orders dated before cutoff with an ID above afterID,
+	// skipping orders on legal hold and orders that were already anonymized
+	FindExpired(ctx context.Context, cutoff time.Time, afterID uint, limit int) ([]models.Order, error)
+	// DeleteOrders and AnonymizeOrders purge the orders not on legal hold and return the
+	// audit records written for them
+	DeleteOrders(ctx context.Context, orders []models.Order) ([]models.OrderPurgeAudit, error)
+	AnonymizeOrders(ctx context.Context, orders []models.Order) ([]models.OrderPurgeAudit, error)
+	// SaveLegalHold stores the legal hold of order. Like OrderRepository.Save it increments the
+	// version and fails with ErrVersionConflict when the order changed since it was loaded.
+	SaveLegalHold(ctx context.Context, order *models.Order) error
+}
@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"context"
+	"order-service/internal/domain/models"
+)
+
+// OrderSearchQuery is a free-text search over order references, customer names and products
+type OrderSearchQuery struct {
+	Text     string
+	Page     int
+	PageSize int
+}
+
+// OrderSearchHit is a matching order and its relevance, higher ranks first
+type OrderSearchHit struct {
+	Order models.Order
+	Rank  float64
+}
+
+// OrderSearchPage is one page of ranked search results
+type OrderSearchPage struct {
+	Hits     []OrderSearchHit
+	Total    int64
+	Page     int
+	PageSize int
+}
+
+type OrderSearchRepository interface {
+	Search(ctx context.Context, query OrderSearchQuery) (OrderSearchPage, error)
+}
@@ -0,0 +1,28 @@
+package repositories
+
+import "context"
+
+type expectedVersionsKey struct{}
+
+// WithExpectedVersions marks the context so a write only applies to an order at one of versions.
+// An empty list matches no version.
+func WithExpectedVersions(ctx context.Context, versions []uint) context.Context {
+	if versions == nil {
+		versions = []uint{}
+	}
+	return context.WithValue(ctx, expectedVersionsKey{}, versions)
+}
+
+// CheckExpectedVersion returns ErrOrderModified when the context expects a version other than version
+func CheckExpectedVersion(ctx context.Context, version uint) error {
+	versions, ok := ctx.Value(expectedVersionsKey{}).([]uint)
+	if !ok {
+		return nil
+	}
+	for _, expected := range versions {
+		if expected == version {
+			return nil
+		}
+	}
+	return ErrOrderModified
+}
@@ -0,0 +1,18 @@
+// Package requestid carries the ID of the request being served, so log lines, spans and events
+// caused by one request can be tied together.
+package requestid
+
+import "context"
+
+type contextKey struct{}
+
+// With returns a copy of ctx that carries id
+func With(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// From returns the request ID carried by ctx, or "" outside of a request
+func From(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"order-service/internal/application/dto"
+	"order-service/internal/domain/domainerr"
+	"order-service/internal/domain/events"
+	"order-service/internal/domain/models"
+	"order-service/internal/domain/repositories"
+	"order-service/internal/infrastructure/logging"
+)
+
+const (
+	// defaultBulkBatchSize is how many orders an import inserts and an export reads at a time
+	defaultBulkBatchSize = 500
+	// maxReportedImportErrors caps how many row errors an import report lists
+	maxReportedImportErrors = 1000
+)
+
+type BulkOrderService struct {
+	repo           repositories.OrderBulkRepository
+	eventPublisher EventPublisher
+	batchSize      int
+}
+
+// NewBulkOrderService creates a service importing and exporting batchSize orders at a time,
+// or 500 when batchSize is not positive
+func NewBulkOrderService(repo repositories.OrderBulkRepository, eventPublisher EventPublisher, batchSize int) *BulkOrderService {
+	if batchSize <= 0 {
+		batchSize = defaultBulkBatchSize
+	}
+	return &BulkOrderService{repo: repo, eventPublisher: eventPublisher, batchSize: batchSize}
+}
+
+// pendingOrder is a validated order waiting for its batch to be inserted
+type pendingOrder struct {
+	row   int
+	order models.Order
+}
+
+// Import creates the orders returned by next until it returns io.EOF. Every order is validated
+// like a created order; rows that cannot be read, fail validation or cannot be stored are
+// reported and skipped. Valid orders are inserted in batches, and the orders of a batch that
+// fails, other than those it stored before failing, are retried one at a time so only the
+// offending rows are rejected. Stored orders whose created event cannot be published stay
+// imported and are listed as unpublished. Any other error from next stops the import.
+func (s *BulkOrderService) Import(ctx context.Context, next func() (dto.ImportRow, error)) (dto.ImportReport, error) {
+	report := dto.ImportReport{Errors: []dto.ImportRowError{}}
+	fail := func(row int, orderID string, err error) {
+		report.Failed++
+		if len(report.Errors) == maxReportedImportErrors {
+			report.Truncated = true
+			return
+		}
+		report.Errors = append(report.Errors, dto.ImportRowError{Row: row, OrderID: orderID, Error: domainerr.Message(err, err.Error())})
+	}
+
+	var batch []pendingOrder
+	seen := map[string]int{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		row, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return report, err
+		}
+		if row.Err != nil {
+			fail(row.Row, row.Record.OrderID, row.Err)
+			continue
+		}
+
+		order := buildOrder(recordToCreateDto(row.Record))
+		if err := order.Validate(); err != nil {
+			fail(row.Row, order.OrderID, err)
+			continue
+		}
+		if first, ok := seen[order.OrderID]; ok && order.OrderID != "" {
+			fail(row.Row, order.OrderID, fmt.Errorf("order ID already used on row %d", first))
+			continue
+		}
+		seen[order.OrderID] = row.Row
+
+		batch = append(batch, pendingOrder{row: row.Row, order: order})
+		if len(batch) == s.batchSize {
+			s.insert(ctx, batch, &report, fail)
+			batch = batch[:0]
+		}
+	}
+
+	s.insert(ctx, batch, &report, fail)
+	return report, nil
+}
+
+// insert stores a batch, falling back to one order at a time when the batch is rejected,
+// and publishes a created event for every stored order. Stored orders are imported even when
+// their event cannot be published; the report lists them as unpublished.
+func (s *BulkOrderService) insert(ctx context.Context, batch []pendingOrder, report *dto.ImportReport, fail func(int, string, error)) {
+	if len(batch) == 0 {
+		return
+	}
+
+	orders := make([]models.Order, len(batch))
+	for i, pending := range batch {
+		orders[i] = pending.order
+	}
+
+	saved := len(orders)
+	if err := s.repo.CreateBatch(ctx, orders); err != nil {
+		// A store that saves a batch order by order keeps the orders saved before the failure
+		saved = 0
+		var partial *repositories.PartialBatchError
+		if errors.As(err, &partial) {
+			saved = partial.Stored
+		}
+	}
+	stored := make([]pendingOrder, 0, len(batch))
+	for i := range orders[:saved] {
+		stored = append(stored, pendingOrder{row: batch[i].row, order: orders[i]})
+	}
+	for _, pending := range batch[saved:] {
+		single := []models.Order{pending.order}
+		if err := s.repo.CreateBatch(ctx, single); err != nil {
+			fail(pending.row, pending.order.OrderID, storeError(ctx, pending.order.OrderID, err))
+			continue
+		}
+		stored = append(stored, pendingOrder{row: pending.row, order: single[0]})
+	}
+
+	report.Imported += len(stored)
+	for _, pending := range stored {
+		if err := s.eventPublisher.Publish(ctx, events.NewOrderCreatedEvent(pending.order)); err != nil {
+			logging.Ctx(ctx).Error().Err(err).Msgf("failed to publish the creation of imported order %s", pending.order.OrderID)
+			if len(report.Unpublished) == maxReportedImportErrors {
+				report.Truncated = true
+				continue
+			}
+			report.Unpublished = append(report.Unpublished, dto.ImportRowError{
+				Row:     pending.row,
+				OrderID: pending.order.OrderID,
+				Error:   "order was imported but its created event could not be published",
+			})
+		}
+	}
+}
+
+// storeError keeps storage failures other than domain errors out of the import report,
+// logging them instead
+func storeError(ctx context.Context, orderID string, err error) error {
+	if _, ok := domainerr.As(err); ok {
+		return err
+	}
+	logging.Ctx(ctx).Error().Err(err).Msgf("failed to import order %s", orderID)
+	return errors.New("order could not be stored")
+}
+
+// Export passes every order to write in the repository's batch order, reading them in batches
+func (s *BulkOrderService) Export(ctx context.Context, write func(dto.OrderRecord) error) error {
+	return s.repo.FindInBatches(ctx, s.batchSize, func(orders []models.Order) error {
+		for _, order := range orders {
+			if err := write(convertToOrderRecord(order)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func recordToCreateDto(record dto.OrderRecord) dto.OrderCreateDto {
+	orderDto := dto.OrderCreateDto{
+		OrderID:      record.OrderID,
+		CustomerID:   record.CustomerID,
+		CustomerName: record.CustomerName,
+		OrderDate:    record.OrderDate,
+		OrderItems:   make([]dto.OrderItemDto, len(record.Items)),
+	}
+	for i, item := range record.Items {
+		orderDto.OrderItems[i] = dto.OrderItemDto{
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			Price:       item.Price,
+		}
+	}
+	return orderDto
+}
+
+func convertToOrderRecord(order models.Order) dto.OrderRecord {
+	return dto.OrderRecord{
+		OrderID:      order.OrderID,
+		CustomerID:   order.CustomerID,
+		CustomerName: order.CustomerName,
+		Status:       string(order.Status),
+		OrderDate:    order.OrderDate,
+		TotalAmount:  order.TotalAmount,
+		Items:        convertToOrderItemResponse(order.OrderItems),
+	}
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"order-service/internal/application/dto"
+	"order-service/internal/domain/events"
+	"order-service/internal/domain/models"
+	"order-service/internal/domain/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOrderBulkRepository is a mock implementation of the OrderBulkRepository interface
+type MockOrderBulkRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderBulkRepository) CreateBatch(ctx context.Context, orders []models.Order) error {
+	args := m.Called(ctx, orders)
+	return args.Error(0)
+}
+
+func (m *MockOrderBulkRepository) FindInBatches(ctx context.Context, batchSize int, fn func(orders []models.Order) error) error {
+	args := m.Called(ctx, batchSize, fn)
+	return args.Error(0)
+}
+
+// importRows returns a next function yielding rows and then io.EOF
+func importRows(rows ...dto.ImportRow) func() (dto.ImportRow, error) {
+	return func() (dto.ImportRow, error) {
+		if len(rows) == 0 {
+			return dto.ImportRow{}, io.EOF
+		}
+		row := rows[0]
+		rows = rows[1:]
+		return row, nil
+	}
+}
+
+func importRow(row int, orderID string, quantity int) dto.ImportRow {
+	return dto.ImportRow{Row: row, Record: dto.OrderRecord{
+		OrderID:    orderID,
+		CustomerID: 1,
+		OrderDate:  time.Date(2024, time.October, 27, 0, 0, 0, 0, time.UTC),
+		Items:      []dto.OrderItemResponse{{ProductID: 1, Quantity: quantity, Price: 5}},
+	}}
+}
+
+// TestImportReportsInvalidRows tests that unreadable, invalid and duplicate rows are reported while the rest are batched
+func TestImportReportsInvalidRows(t *testing.T) {
+	mockRepo := new(MockOrderBulkRepository)
+	mockPublisher := new(MockEventPublisher)
+	service := NewBulkOrderService(mockRepo, mockPublisher, 2)
+
+	mockRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(orders []models.Order) bool { return len(orders) == 2 })).Return(nil).Once()
+	mockRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(orders []models.Order) bool { return len(orders) == 1 })).Return(nil).Once()
+	mockPublisher.On("Publish", mock.Anything, mock.AnythingOfType("events.OrderCreatedEvent")).Return(nil).Times(3)
+
+	report, err := service.Import(context.Background(), importRows(
+		importRow(2, "ORD-1", 1),
+		dto.ImportRow{Row: 3, Err: errors.New(`invalid price "x"`)},
+		importRow(4, "ORD-2", 0),
+		importRow(5, "ORD-3", 2),
+		importRow(6, "ORD-1", 1),
+		importRow(7, "ORD-4", 1),
+	))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, report.Imported)
+	assert.Equal(t, 3, report.Failed)
+	assert.Equal(t, []dto.ImportRowError{
+		{Row: 3, Error: `invalid price "x"`},
+		{Row: 4, OrderID: "ORD-2", Error: "order item quantity must be greater than zero; total amount must be greater than zero"},
+		{Row: 6, OrderID: "ORD-1", Error: "order ID already used on row 2"},
+	}, report.Errors)
+	mockRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+}
+
+// TestImportRetriesFailedBatchPerOrder tests that a rejected batch is retried one order at a time and
+// that only domain errors reach the report
+func TestImportRetriesFailedBatchPerOrder(t *testing.T) {
+	mockRepo := new(MockOrderBulkRepository)
+	mockPublisher := new(MockEventPublisher)
+	service := NewBulkOrderService(mockRepo, mockPublisher, 10)
+
+	duplicate := repositories.ErrOrderIDTaken.Wrap(errors.New("duplicate key value violates unique constraint"))
+	isOrder := func(orderID string) interface{} {
+		return mock.MatchedBy(func(orders []models.Order) bool { return len(orders) == 1 && orders[0].OrderID == orderID })
+	}
+	mockRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(orders []models.Order) bool { return len(orders) == 3 })).Return(duplicate).Once()
+	mockRepo.On("CreateBatch", mock.Anything, isOrder("ORD-1")).Return(duplicate).Once()
+	mockRepo.On("CreateBatch", mock.Anything, isOrder("ORD-2")).Return(nil).Once()
+	mockRepo.On("CreateBatch", mock.Anything, isOrder("ORD-3")).Return(errors.New(`pq: value too long for type character varying(255) at "orders"."customer_name"`)).Once()
+	mockPublisher.On("Publish", mock.Anything, mock.MatchedBy(func(event events.OrderCreatedEvent) bool { return event.Reference == "ORD-2" })).Return(nil).Once()
+
+	report, err := service.Import(context.Background(), importRows(importRow(2, "ORD-1", 1), importRow(3, "ORD-2", 1), importRow(4, "ORD-3", 1)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Imported)
+	assert.Equal(t, []dto.ImportRowError{
+		{Row: 2, OrderID: "ORD-1", Error: "order ID is already taken"},
+		{Row: 4, OrderID: "ORD-3", Error: "order could not be stored"},
+	}, report.Errors, "database errors other than domain errors are not reported to the client")
+	mockRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+}
+
+// TestImportRetriesOnlyUnsavedOrders tests that orders a partial batch stored are imported once and only the rest are retried
+func TestImportRetriesOnlyUnsavedOrders(t *testing.T) {
+	mockRepo := new(MockOrderBulkRepository)
+	mockPublisher := new(MockEventPublisher)
+	service := NewBulkOrderService(mockRepo, mockPublisher, 10)
+
+	isOrder := func(orderID string) interface{} {
+		return mock.MatchedBy(func(orders []models.Order) bool { return len(orders) == 1 && orders[0].OrderID == orderID })
+	}
+	isPublished := func(orderID string) interface{} {
+		return mock.MatchedBy(func(event events.OrderCreatedEvent) bool { return event.Reference == orderID })
+	}
+	mockRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(orders []models.Order) bool { return len(orders) == 3 })).
+		Return(&repositories.PartialBatchError{Stored: 1, Err: repositories.ErrOrderIDTaken}).Once()
+	mockRepo.On("CreateBatch", mock.Anything, isOrder("ORD-2")).Return(repositories.ErrOrderIDTaken).Once()
+	mockRepo.On("CreateBatch", mock.Anything, isOrder("ORD-3")).Return(nil).Once()
+	mockPublisher.On("Publish", mock.Anything, isPublished("ORD-1")).Return(nil).Once()
+	mockPublisher.On("Publish", mock.Anything, isPublished("ORD-3")).Return(nil).Once()
+
+	report, err := service.Import(context.Background(), importRows(importRow(2, "ORD-1", 1), importRow(3, "ORD-2", 1), importRow(4, "ORD-3", 1)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.Imported)
+	assert.Equal(t, []dto.ImportRowError{{Row: 3, OrderID: "ORD-2", Error: "order ID is already taken"}}, report.Errors)
+	mockRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+}
+
+// TestImportReportsUnpublishedOrders tests that stored orders whose event fails stay imported and the import goes on
+func TestImportReportsUnpublishedOrders(t *testing.T) {
+	mockRepo := new(MockOrderBulkRepository)
+	mockPublisher := new(MockEventPublisher)
+	service := NewBulkOrderService(mockRepo, mockPublisher, 2)
+
+	isPublished := func(orderID string) interface{} {
+		return mock.MatchedBy(func(event events.OrderCreatedEvent) bool { return event.Reference == orderID })
+	}
+	mockRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(nil).Twice()
+	mockPublisher.On("Publish", mock.Anything, isPublished("ORD-1")).Return(errors.New("broker unavailable")).Once()
+	mockPublisher.On("Publish", mock.Anything, isPublished("ORD-2")).Return(nil).Once()
+	mockPublisher.On("Publish", mock.Anything, isPublished("ORD-3")).Return(nil).Once()
+
+	report, err := service.Import(context.Background(), importRows(importRow(2, "ORD-1", 1), importRow(3, "ORD-2", 1), importRow(4, "ORD-3", 1)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, report.Imported)
+	assert.Equal(t, 0, report.Failed)
+	assert.Equal(t, []dto.ImportRowError{
+		{Row: 2, OrderID: "ORD-1", Error: "order was imported but its created event could not be published"},
+	}, report.Unpublished)
+	mockRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+}
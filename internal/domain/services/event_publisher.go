@@ -1,14 +1,24 @@
 package services
 
-import "log"
+import (
+	"context"
+	"fmt"
+	"order-service/internal/domain/events"
+	"order-service/internal/infrastructure/logging"
+)
 
 type EventPublisher interface {
-	Publish(event interface{}) error
+	Publish(ctx context.Context, event interface{}) error
 }
 
+// LoggerEventPublisher logs every event with the metadata of the request that published it
 type LoggerEventPublisher struct{}
 
-func (p *LoggerEventPublisher) Publish(event interface{}) error {
-	log.Printf("Event published: %+v", event)
+func (p *LoggerEventPublisher) Publish(ctx context.Context, event interface{}) error {
+	envelope := events.NewEnvelope(ctx, event)
+	logging.Ctx(ctx).Info().
+		Str("event_type", fmt.Sprintf("%T", event)).
+		Time("occurred_at", envelope.Metadata.OccurredAt).
+		Msgf("Event published: %+v", envelope)
 	return nil
 }
@@ -1,141 +1,323 @@
 package services
 
 import (
+	"context"
 	"order-service/internal/application/dto"
 	"order-service/internal/domain/events"
 	"order-service/internal/domain/models"
 	"order-service/internal/domain/repositories"
 	"order-service/internal/infrastructure/logging"
+	"time"
+)
+
+const (
+	defaultSearchPageSize = 20
+	maxSearchPageSize     = 100
 )
 
 type OrderService struct {
 	repo           repositories.OrderRepository
+	searchRepo     repositories.OrderSearchRepository
 	eventPublisher EventPublisher
 }
 
-func NewOrderService(repo repositories.OrderRepository, eventPublisher EventPublisher) *OrderService {
-	return &OrderService{repo: repo, eventPublisher: eventPublisher}
+func NewOrderService(repo repositories.OrderRepository, searchRepo repositories.OrderSearchRepository, eventPublisher EventPublisher) *OrderService {
+	return &OrderService{repo: repo, searchRepo: searchRepo, eventPublisher: eventPublisher}
 }
 
-func (s *OrderService) CreateOrder(orderDto dto.OrderCreateDto) (dto.OrderResponse, error) {
-	newOrder := models.Order{
-		OrderID:    orderDto.OrderID,
-		CustomerID: orderDto.CustomerID,
-		OrderDate:  orderDto.OrderDate,
-		CreatedAt:  orderDto.OrderDate,
-		UpdatedAt:  orderDto.OrderDate,
-	}
-
-	for _, item := range orderDto.OrderItems {
-		newOrder.AddItem(
-			models.OrderItem{
-				OrderID:   newOrder.OrderID,
-				ProductID: item.ProductID,
-				Quantity:  item.Quantity,
-				Price:     item.Price,
-			},
-		)
-	}
+func (s *OrderService) CreateOrder(ctx context.Context, orderDto dto.OrderCreateDto) (dto.OrderResponse, error) {
+	newOrder := buildOrder(orderDto)
 
 	if err := newOrder.Validate(); err != nil {
 		return dto.OrderResponse{}, err
 	}
 
-	err := s.repo.Save(newOrder)
+	err := s.repo.Save(ctx, &newOrder)
 	if err != nil {
 		return dto.OrderResponse{}, err
 	}
 
-	event := events.OrderCreatedEvent{
-		OrderID:     newOrder.ID,
-		CustomerID:  newOrder.CustomerID,
-		TotalAmount: newOrder.TotalAmount,
-	}
-
-	err = s.eventPublisher.Publish(event)
+	err = s.eventPublisher.Publish(ctx, events.NewOrderCreatedEvent(newOrder))
 	if err != nil {
 		return dto.OrderResponse{}, err
 	}
 
-	return dto.OrderResponse{
-		OrderID:     newOrder.OrderID,
-		CustomerID:  newOrder.CustomerID,
-		TotalAmount: newOrder.TotalAmount,
-		Items:       convertToOrderItemResponse(newOrder.OrderItems),
-	}, nil
+	return convertToOrderResponse(newOrder), nil
 }
 
-func (s *OrderService) GetOrderByID(id uint) (*dto.OrderResponse, error) {
-	order, err := s.repo.FindByID(id)
-	logging.Logger.Info().Msgf("%v", order)
+func (s *OrderService) GetOrderByID(ctx context.Context, id uint) (*dto.OrderResponse, error) {
+	order, err := s.repo.FindByID(ctx, id)
+	logging.Ctx(ctx).Info().Msgf("%v", order)
 	if err != nil {
 		return nil, err
 	}
 
-	return &dto.OrderResponse{
-		OrderID:     order.OrderID,
-		CustomerID:  order.CustomerID,
-		TotalAmount: order.TotalAmount,
-		Items:       convertToOrderItemResponse(order.OrderItems),
-	}, nil
+	response := convertToOrderResponse(*order)
+	return &response, nil
 }
 
-func (s *OrderService) GetAllOrders() ([]dto.OrderResponse, error) {
-	orders, err := s.repo.FindAll()
+func (s *OrderService) GetAllOrders(ctx context.Context) ([]dto.OrderResponse, error) {
+	orders, err := s.repo.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	ordersResponse := make([]dto.OrderResponse, len(orders))
 	for i, order := range orders {
-		ordersResponse[i] = dto.OrderResponse{
-			OrderID:     order.OrderID,
-			CustomerID:  order.CustomerID,
-			Items:       convertToOrderItemResponse(order.OrderItems),
-			TotalAmount: order.TotalAmount,
-		}
+		ordersResponse[i] = convertToOrderResponse(order)
 	}
 
 	return ordersResponse, nil
 }
 
-func (s *OrderService) AddItemToOrder(id uint, item dto.OrderItemDto) (*dto.OrderResponse, error) {
-	order, err := s.repo.FindByID(id)
+func (s *OrderService) AddItemToOrder(ctx context.Context, id uint, item dto.OrderItemDto) (*dto.OrderResponse, error) {
+	order, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if err := repositories.CheckExpectedVersion(ctx, order.Version); err != nil {
+		return nil, err
+	}
+	if err := order.EnsureModifiable(); err != nil {
+		return nil, err
+	}
 
 	order.AddItem(models.OrderItem{
-		ProductID: item.ProductID,
-		Quantity:  item.Quantity,
-		Price:     item.Price,
+		ProductID:   item.ProductID,
+		ProductName: item.ProductName,
+		Quantity:    item.Quantity,
+		Price:       item.Price,
 	})
 	if err := order.Validate(); err != nil {
 		return nil, err
 	}
 
-	err = s.repo.Save(*order)
+	err = s.repo.Save(ctx, order)
 	if err != nil {
 		return nil, err
 	}
 
-	response := dto.OrderResponse{
-		OrderID:     order.OrderID,
-		CustomerID:  order.CustomerID,
-		Items:       convertToOrderItemResponse(order.OrderItems),
+	err = s.eventPublisher.Publish(ctx, events.OrderItemAddedEvent{
+		OrderID:     order.ID,
+		Reference:   order.OrderID,
+		Item:        order.OrderItems[len(order.OrderItems)-1],
 		TotalAmount: order.TotalAmount,
+		Version:     order.Version,
+		UpdatedAt:   order.UpdatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := convertToOrderResponse(*order)
+
+	return &response, nil
+}
+
+func (s *OrderService) CancelOrder(ctx context.Context, id uint) (*dto.OrderResponse, error) {
+	order, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := repositories.CheckExpectedVersion(ctx, order.Version); err != nil {
+		return nil, err
+	}
+
+	if err := order.Cancel(); err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Save(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.eventPublisher.Publish(ctx, events.OrderCancelledEvent{
+		OrderID:   order.ID,
+		Reference: order.OrderID,
+		Version:   order.Version,
+		UpdatedAt: order.UpdatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := convertToOrderResponse(*order)
+	return &response, nil
+}
+
+// UpdateOrder replaces the mutable fields of a pending order
+func (s *OrderService) UpdateOrder(ctx context.Context, id uint, update dto.OrderUpdateDto) (*dto.OrderResponse, error) {
+	return s.PatchOrder(ctx, id, func(dto.OrderUpdateDto) (dto.OrderUpdateDto, error) {
+		return update, nil
+	})
+}
+
+// PatchOrder passes the mutable fields of a pending order to patch and stores what it returns
+func (s *OrderService) PatchOrder(ctx context.Context, id uint, patch func(current dto.OrderUpdateDto) (dto.OrderUpdateDto, error)) (*dto.OrderResponse, error) {
+	order, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := repositories.CheckExpectedVersion(ctx, order.Version); err != nil {
+		return nil, err
+	}
+	if err := order.EnsureModifiable(); err != nil {
+		return nil, err
+	}
+
+	update, err := patch(convertToOrderUpdateDto(*order))
+	if err != nil {
+		return nil, err
+	}
+	if err := order.Update(update.CustomerName, buildOrderItems(update.OrderItems)); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Save(ctx, order); err != nil {
+		return nil, err
 	}
 
+	err = s.eventPublisher.Publish(ctx, events.OrderUpdatedEvent{
+		OrderID:      order.ID,
+		Reference:    order.OrderID,
+		CustomerName: order.CustomerName,
+		Items:        order.OrderItems,
+		TotalAmount:  order.TotalAmount,
+		Version:      order.Version,
+		UpdatedAt:    order.UpdatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := convertToOrderResponse(*order)
 	return &response, nil
 }
 
+// DeleteOrder soft-deletes an order so it disappears from every read
+func (s *OrderService) DeleteOrder(ctx context.Context, id uint) error {
+	order, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := repositories.CheckExpectedVersion(ctx, order.Version); err != nil {
+		return err
+	}
+
+	if err := order.Delete(time.Now()); err != nil {
+		return err
+	}
+
+	if err := s.repo.Save(ctx, order); err != nil {
+		return err
+	}
+
+	return s.eventPublisher.Publish(ctx, events.OrderDeletedEvent{OrderID: order.ID, Reference: order.OrderID})
+}
+
+func (s *OrderService) SearchOrders(ctx context.Context, text string, page, pageSize int) (dto.OrderSearchResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultSearchPageSize
+	}
+	if pageSize > maxSearchPageSize {
+		pageSize = maxSearchPageSize
+	}
+
+	results, err := s.searchRepo.Search(ctx, repositories.OrderSearchQuery{Text: text, Page: page, PageSize: pageSize})
+	if err != nil {
+		return dto.OrderSearchResponse{}, err
+	}
+
+	response := dto.OrderSearchResponse{
+		Results:  make([]dto.OrderSearchResult, len(results.Hits)),
+		Total:    results.Total,
+		Page:     page,
+		PageSize: pageSize,
+	}
+	for i, hit := range results.Hits {
+		response.Results[i] = dto.OrderSearchResult{
+			Order: convertToOrderResponse(hit.Order),
+			Rank:  hit.Rank,
+		}
+	}
+
+	return response, nil
+}
+
+// buildOrder builds a pending order with its items from orderDto
+func buildOrder(orderDto dto.OrderCreateDto) models.Order {
+	order := models.Order{
+		OrderID:      orderDto.OrderID,
+		CustomerID:   orderDto.CustomerID,
+		CustomerName: orderDto.CustomerName,
+		Status:       models.OrderStatusPending,
+		OrderDate:    orderDto.OrderDate,
+		CreatedAt:    orderDto.OrderDate,
+		UpdatedAt:    orderDto.OrderDate,
+	}
+
+	for _, item := range buildOrderItems(orderDto.OrderItems) {
+		item.OrderID = order.OrderID
+		order.AddItem(item)
+	}
+	return order
+}
+
+func buildOrderItems(items []dto.OrderItemDto) []models.OrderItem {
+	orderItems := make([]models.OrderItem, len(items))
+	for i, item := range items {
+		orderItems[i] = models.OrderItem{
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			Price:       item.Price,
+		}
+	}
+	return orderItems
+}
+
+func convertToOrderUpdateDto(order models.Order) dto.OrderUpdateDto {
+	update := dto.OrderUpdateDto{
+		CustomerName: order.CustomerName,
+		OrderItems:   make([]dto.OrderItemDto, len(order.OrderItems)),
+	}
+	for i, item := range order.OrderItems {
+		update.OrderItems[i] = dto.OrderItemDto{
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			Price:       item.Price,
+		}
+	}
+	return update
+}
+
+func convertToOrderResponse(order models.Order) dto.OrderResponse {
+	return dto.OrderResponse{
+		OrderID:      order.OrderID,
+		CustomerID:   order.CustomerID,
+		CustomerName: order.CustomerName,
+		Items:        convertToOrderItemResponse(order.OrderItems),
+		TotalAmount:  order.TotalAmount,
+		Status:       string(order.Status),
+		OrderDate:    order.OrderDate,
+		Version:      order.Version,
+		UpdatedAt:    order.UpdatedAt,
+	}
+}
+
 func convertToOrderItemResponse(items []models.OrderItem) []dto.OrderItemResponse {
 	response := make([]dto.OrderItemResponse, len(items))
 	for i, item := range items {
 		response[i] = dto.OrderItemResponse{
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			Price:       item.Price,
 		}
 	}
 
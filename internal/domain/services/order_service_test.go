@@ -1,8 +1,11 @@
 package services
 
 import (
+	"context"
 	"order-service/internal/application/dto"
+	"order-service/internal/domain/events"
 	"order-service/internal/domain/models"
+	"order-service/internal/domain/repositories"
 	"testing"
 	"time"
 
@@ -15,28 +18,38 @@ type MockOrderRepository struct {
 	mock.Mock
 }
 
-func (m *MockOrderRepository) Save(order models.Order) error {
-	args := m.Called(order)
+func (m *MockOrderRepository) Save(ctx context.Context, order *models.Order) error {
+	args := m.Called(ctx, order)
 	return args.Error(0)
 }
 
-func (m *MockOrderRepository) FindByID(id uint) (*models.Order, error) {
-	args := m.Called(id)
+func (m *MockOrderRepository) FindByID(ctx context.Context, id uint) (*models.Order, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(*models.Order), args.Error(1)
 }
 
-func (m *MockOrderRepository) FindAll() ([]models.Order, error) {
-	args := m.Called()
+func (m *MockOrderRepository) FindAll(ctx context.Context) ([]models.Order, error) {
+	args := m.Called(ctx)
 	return args.Get(0).([]models.Order), args.Error(1)
 }
 
+// MockOrderSearchRepository is a mock implementation of the OrderSearchRepository interface
+type MockOrderSearchRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderSearchRepository) Search(ctx context.Context, query repositories.OrderSearchQuery) (repositories.OrderSearchPage, error) {
+	args := m.Called(ctx, query)
+	return args.Get(0).(repositories.OrderSearchPage), args.Error(1)
+}
+
 // MockEventPublisher is a mock implementation of the EventPublisher interface
 type MockEventPublisher struct {
 	mock.Mock
 }
 
-func (m *MockEventPublisher) Publish(event interface{}) error {
-	args := m.Called(event)
+func (m *MockEventPublisher) Publish(ctx context.Context, event interface{}) error {
+	args := m.Called(ctx, event)
 	return args.Error(0)
 }
 
@@ -45,7 +58,7 @@ func TestCreateOrder(t *testing.T) {
 	mockRepo := new(MockOrderRepository)
 	mockPublisher := new(MockEventPublisher)
 
-	service := NewOrderService(mockRepo, mockPublisher)
+	service := NewOrderService(mockRepo, nil, mockPublisher)
 
 	mockTime := time.Date(2025, time.January, 8, 19, 38, 18, 365284100, time.Local)
 
@@ -57,26 +70,28 @@ func TestCreateOrder(t *testing.T) {
 		},
 	}
 
-	orderDto.NewOrderCreateDto()
+	orderDto.OrderDate = mockTime
 
 	expectedOrder := models.Order{
 		OrderID:    orderDto.OrderID,
 		CustomerID: orderDto.CustomerID,
+		Status:     models.OrderStatusPending,
 		OrderDate:  mockTime,
 		CreatedAt:  mockTime,
 		UpdatedAt:  mockTime,
 	}
 	expectedOrder.AddItem(models.OrderItem{
+		OrderID:   orderDto.OrderID,
 		ProductID: 1,
 		Quantity:  2,
 		Price:     9.99,
 	})
 
 	// Set up mock expectations
-	mockRepo.On("Save", expectedOrder).Return(0)
-	mockPublisher.On("Publish", mock.AnythingOfType("events.OrderCreatedEvent")).Return(nil)
+	mockRepo.On("Save", mock.Anything, &expectedOrder).Return(nil)
+	mockPublisher.On("Publish", mock.Anything, mock.AnythingOfType("events.OrderCreatedEvent")).Return(nil)
 
-	orderResponse, err := service.CreateOrder(orderDto)
+	orderResponse, err := service.CreateOrder(context.Background(), orderDto)
 	assert.NoError(t, err)
 	assert.Equal(t, "test-123", orderResponse.OrderID)
 	assert.Equal(t, uint(123), orderResponse.CustomerID)
@@ -92,23 +107,25 @@ func TestGetOrderByID(t *testing.T) {
 	mockRepo := new(MockOrderRepository)
 	mockPublisher := new(MockEventPublisher)
 
-	service := NewOrderService(mockRepo, mockPublisher)
+	service := NewOrderService(mockRepo, nil, mockPublisher)
 
 	sampleOrder := models.Order{
 		ID:         1,
+		OrderID:    "test-1",
 		CustomerID: 123,
 		OrderItems: []models.OrderItem{
 			{ProductID: 1, Quantity: 2, Price: 9.99},
 		},
 		TotalAmount: 19.98,
+		OrderDate:   time.Date(2025, time.January, 8, 19, 38, 18, 0, time.UTC),
 	}
 
 	// Set up mock expectations
-	mockRepo.On("FindByID", uint(1)).Return(&sampleOrder, nil)
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&sampleOrder, nil)
 
-	orderResponse, err := service.GetOrderByID(1)
+	orderResponse, err := service.GetOrderByID(context.Background(), 1)
 	assert.NoError(t, err)
-	assert.Equal(t, uint(1), orderResponse.OrderID)
+	assert.Equal(t, "test-1", orderResponse.OrderID)
 	assert.Equal(t, uint(123), orderResponse.CustomerID)
 	assert.Equal(t, 19.98, orderResponse.TotalAmount)
 	assert.Len(t, orderResponse.Items, 1)
@@ -121,11 +138,12 @@ func TestGetAllOrders(t *testing.T) {
 	mockRepo := new(MockOrderRepository)
 	mockPublisher := new(MockEventPublisher)
 
-	service := NewOrderService(mockRepo, mockPublisher)
+	service := NewOrderService(mockRepo, nil, mockPublisher)
 
 	sampleOrders := []models.Order{
 		{
 			ID:         1,
+			OrderID:    "test-1",
 			CustomerID: 123,
 			OrderItems: []models.OrderItem{
 				{ProductID: 1, Quantity: 2, Price: 9.99},
@@ -135,12 +153,12 @@ func TestGetAllOrders(t *testing.T) {
 	}
 
 	// Set up mock expectations
-	mockRepo.On("FindAll").Return(sampleOrders, nil)
+	mockRepo.On("FindAll", mock.Anything).Return(sampleOrders, nil)
 
-	ordersResponse, err := service.GetAllOrders()
+	ordersResponse, err := service.GetAllOrders(context.Background())
 	assert.NoError(t, err)
 	assert.Len(t, ordersResponse, 1)
-	assert.Equal(t, uint(1), ordersResponse[0].OrderID)
+	assert.Equal(t, "test-1", ordersResponse[0].OrderID)
 	assert.Equal(t, uint(123), ordersResponse[0].CustomerID)
 	assert.Equal(t, 19.98, ordersResponse[0].TotalAmount)
 	assert.Len(t, ordersResponse[0].Items, 1)
@@ -153,15 +171,17 @@ func TestAddItemToOrder(t *testing.T) {
 	mockRepo := new(MockOrderRepository)
 	mockPublisher := new(MockEventPublisher)
 
-	service := NewOrderService(mockRepo, mockPublisher)
+	service := NewOrderService(mockRepo, nil, mockPublisher)
 
 	sampleOrder := models.Order{
 		ID:         1,
+		OrderID:    "test-1",
 		CustomerID: 123,
 		OrderItems: []models.OrderItem{
 			{ProductID: 1, Quantity: 2, Price: 9.99},
 		},
 		TotalAmount: 19.98,
+		OrderDate:   time.Date(2025, time.January, 8, 19, 38, 18, 0, time.UTC),
 	}
 
 	newItem := dto.OrderItemDto{
@@ -178,15 +198,206 @@ func TestAddItemToOrder(t *testing.T) {
 	})
 
 	// Set up mock expectations
-	mockRepo.On("FindByID", uint(1)).Return(&sampleOrder, nil)
-	mockRepo.On("Save", updatedOrder).Return(nil)
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&sampleOrder, nil)
+	mockRepo.On("Save", mock.Anything, &updatedOrder).Return(nil)
+	mockPublisher.On("Publish", mock.Anything, mock.AnythingOfType("events.OrderItemAddedEvent")).Return(nil)
 
-	orderResponse, err := service.AddItemToOrder(1, newItem)
+	orderResponse, err := service.AddItemToOrder(context.Background(), 1, newItem)
 	assert.NoError(t, err)
-	assert.Equal(t, uint(1), orderResponse.OrderID)
+	assert.Equal(t, "test-1", orderResponse.OrderID)
 	assert.Equal(t, uint(123), orderResponse.CustomerID)
 	assert.Equal(t, 25.97, orderResponse.TotalAmount)
 	assert.Len(t, orderResponse.Items, 2)
 
 	mockRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+}
+
+// TestCancelOrder tests that a pending order is cancelled once and then rejects changes
+func TestCancelOrder(t *testing.T) {
+	mockRepo := new(MockOrderRepository)
+	mockPublisher := new(MockEventPublisher)
+
+	service := NewOrderService(mockRepo, nil, mockPublisher)
+
+	sampleOrder := models.Order{ID: 1, OrderID: "test-1", CustomerID: 123, Status: models.OrderStatusPending}
+
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&sampleOrder, nil)
+	mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*models.Order")).Return(nil).Once()
+	mockPublisher.On("Publish", mock.Anything, events.OrderCancelledEvent{OrderID: 1, Reference: "test-1"}).Return(nil).Once()
+
+	_, err := service.CancelOrder(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, models.OrderStatusCancelled, sampleOrder.Status)
+
+	_, err = service.CancelOrder(context.Background(), 1)
+	assert.ErrorIs(t, err, models.ErrOrderCancelled)
+
+	_, err = service.AddItemToOrder(context.Background(), 1, dto.OrderItemDto{ProductID: 2, Quantity: 1, Price: 5})
+	assert.ErrorIs(t, err, models.ErrOrderCancelled)
+
+	mockRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+}
+
+// TestCancelOrderExpectedVersion tests that a write expecting a stale version is refused before saving
+func TestCancelOrderExpectedVersion(t *testing.T) {
+	mockRepo := new(MockOrderRepository)
+	mockPublisher := new(MockEventPublisher)
+	service := NewOrderService(mockRepo, nil, mockPublisher)
+
+	sampleOrder := models.Order{ID: 1, OrderID: "test-1", Status: models.OrderStatusPending, Version: 3}
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&sampleOrder, nil)
+	mockRepo.On("Save", mock.Anything, &sampleOrder).Return(nil).Once()
+	mockPublisher.On("Publish", mock.Anything, events.OrderCancelledEvent{OrderID: 1, Reference: "test-1", Version: 3}).Return(nil).Once()
+
+	_, err := service.CancelOrder(repositories.WithExpectedVersions(context.Background(), []uint{2}), 1)
+	assert.ErrorIs(t, err, repositories.ErrOrderModified)
+	assert.Equal(t, models.OrderStatusPending, sampleOrder.Status)
+
+	_, err = service.CancelOrder(repositories.WithExpectedVersions(context.Background(), []uint{2, 3}), 1)
+	assert.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+}
+
+// TestUpdateOrder tests that updating replaces the items, recomputes the total and publishes the change
+func TestUpdateOrder(t *testing.T) {
+	mockRepo := new(MockOrderRepository)
+	mockPublisher := new(MockEventPublisher)
+	service := NewOrderService(mockRepo, nil, mockPublisher)
+
+	sampleOrder := models.Order{
+		ID: 1, OrderID: "test-1", CustomerID: 123, CustomerName: "Jane", Status: models.OrderStatusPending,
+		OrderDate:   time.Now(),
+		OrderItems:  []models.OrderItem{{ID: 5, OrderID: "test-1", ProductID: 1, Quantity: 2, Price: 9.99}},
+		TotalAmount: 19.98,
+	}
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&sampleOrder, nil)
+	mockRepo.On("Save", mock.Anything, &sampleOrder).Return(nil).Once()
+	mockPublisher.On("Publish", mock.Anything, mock.MatchedBy(func(e events.OrderUpdatedEvent) bool {
+		return e.OrderID == 1 && e.CustomerName == "Jane Doe" && e.TotalAmount == 15 && len(e.Items) == 1
+	})).Return(nil).Once()
+
+	response, err := service.UpdateOrder(context.Background(), 1, dto.OrderUpdateDto{
+		CustomerName: "Jane Doe",
+		OrderItems:   []dto.OrderItemDto{{ProductID: 2, Quantity: 3, Price: 5}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane Doe", response.CustomerName)
+	assert.Equal(t, 15.0, response.TotalAmount)
+	assert.Equal(t, []models.OrderItem{{OrderID: "test-1", ProductID: 2, Quantity: 3, Price: 5}}, sampleOrder.OrderItems)
+
+	_, err = service.UpdateOrder(context.Background(), 1, dto.OrderUpdateDto{
+		OrderItems: []dto.OrderItemDto{{ProductID: 2, Quantity: 0, Price: 5}},
+	})
+	assert.Error(t, err)
+
+	mockRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+}
+
+// TestPatchOrder tests that the patch sees the current fields and cancelled orders are not patched
+func TestPatchOrder(t *testing.T) {
+	mockRepo := new(MockOrderRepository)
+	mockPublisher := new(MockEventPublisher)
+	service := NewOrderService(mockRepo, nil, mockPublisher)
+
+	sampleOrder := models.Order{
+		ID: 1, OrderID: "test-1", CustomerID: 123, CustomerName: "Jane", Status: models.OrderStatusPending,
+		OrderDate:   time.Now(),
+		OrderItems:  []models.OrderItem{{ID: 5, ProductID: 1, Quantity: 2, Price: 9.99}},
+		TotalAmount: 19.98,
+	}
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&sampleOrder, nil)
+	mockRepo.On("Save", mock.Anything, &sampleOrder).Return(nil).Once()
+	mockPublisher.On("Publish", mock.Anything, mock.AnythingOfType("events.OrderUpdatedEvent")).Return(nil).Once()
+
+	var seen dto.OrderUpdateDto
+	_, err := service.PatchOrder(context.Background(), 1, func(current dto.OrderUpdateDto) (dto.OrderUpdateDto, error) {
+		seen = current
+		current.CustomerName = "Jane Doe"
+		return current, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, dto.OrderUpdateDto{CustomerName: "Jane", OrderItems: []dto.OrderItemDto{{ProductID: 1, Quantity: 2, Price: 9.99}}}, seen)
+	assert.Equal(t, "Jane Doe", sampleOrder.CustomerName)
+	assert.Equal(t, 19.98, sampleOrder.TotalAmount)
+
+	sampleOrder.Status = models.OrderStatusCancelled
+	_, err = service.PatchOrder(context.Background(), 1, func(current dto.OrderUpdateDto) (dto.OrderUpdateDto, error) {
+		t.Fatal("a cancelled order must not be patched")
+		return current, nil
+	})
+	assert.ErrorIs(t, err, models.ErrOrderCancelled)
+
+	mockRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+}
+
+// TestDeleteOrder tests that an order is soft-deleted once and then rejects changes
+func TestDeleteOrder(t *testing.T) {
+	mockRepo := new(MockOrderRepository)
+	mockPublisher := new(MockEventPublisher)
+	service := NewOrderService(mockRepo, nil, mockPublisher)
+
+	sampleOrder := models.Order{ID: 1, OrderID: "test-1", CustomerID: 123, Status: models.OrderStatusCancelled}
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(&sampleOrder, nil)
+	mockRepo.On("Save", mock.Anything, &sampleOrder).Return(nil).Once()
+	mockPublisher.On("Publish", mock.Anything, events.OrderDeletedEvent{OrderID: 1, Reference: "test-1"}).Return(nil).Once()
+
+	assert.NoError(t, service.DeleteOrder(context.Background(), 1))
+	assert.NotNil(t, sampleOrder.DeletedAt)
+
+	assert.ErrorIs(t, service.DeleteOrder(context.Background(), 1), models.ErrOrderDeleted)
+	_, err := service.CancelOrder(context.Background(), 1)
+	assert.ErrorIs(t, err, models.ErrOrderDeleted)
+
+	mockRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+}
+
+// TestSearchOrders tests that search results keep their rank and the page is clamped to its bounds
+func TestSearchOrders(t *testing.T) {
+	mockRepo := new(MockOrderRepository)
+	mockPublisher := new(MockEventPublisher)
+	searchRepo := new(MockOrderSearchRepository)
+
+	service := NewOrderService(mockRepo, searchRepo, mockPublisher)
+
+	searchRepo.On("Search", mock.Anything, repositories.OrderSearchQuery{Text: "widg", Page: 2, PageSize: 1}).Return(repositories.OrderSearchPage{
+		Hits:     []repositories.OrderSearchHit{{Order: models.Order{ID: 2, OrderID: "ORD-2", CustomerName: "John Smith"}, Rank: 0.5}},
+		Total:    2,
+		Page:     2,
+		PageSize: 1,
+	}, nil)
+	searchRepo.On("Search", mock.Anything, repositories.OrderSearchQuery{Text: "ord-3", Page: 1, PageSize: 20}).Return(repositories.OrderSearchPage{
+		Hits:     []repositories.OrderSearchHit{},
+		Page:     1,
+		PageSize: 20,
+	}, nil)
+	searchRepo.On("Search", mock.Anything, repositories.OrderSearchQuery{Text: "all", Page: 1, PageSize: maxSearchPageSize}).Return(repositories.OrderSearchPage{
+		Hits:     []repositories.OrderSearchHit{},
+		Page:     1,
+		PageSize: maxSearchPageSize,
+	}, nil)
+
+	results, err := service.SearchOrders(context.Background(), "widg", 2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), results.Total)
+	assert.Len(t, results.Results, 1)
+	assert.Equal(t, "ORD-2", results.Results[0].Order.OrderID)
+	assert.Equal(t, 0.5, results.Results[0].Rank)
+
+	results, err = service.SearchOrders(context.Background(), "ord-3", 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, results.Page)
+	assert.Equal(t, 20, results.PageSize)
+	assert.Empty(t, results.Results)
+
+	_, err = service.SearchOrders(context.Background(), "all", 1, maxSearchPageSize+1)
+	assert.NoError(t, err)
+
+	searchRepo.AssertExpectations(t)
 }
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"order-service/internal/application/dto"
+	"order-service/internal/domain/events"
+	"order-service/internal/domain/models"
+	"order-service/internal/domain/repositories"
+	"time"
+)
+
+// maxReportedOrders caps how many order references a retention report lists
+const maxReportedOrders = 100
+
+type RetentionService struct {
+	repo           repositories.OrderRetentionRepository
+	orders         repositories.OrderRepository
+	eventPublisher EventPublisher
+	policy         models.RetentionPolicy
+	now            func() time.Time
+}
+
+// NewRetentionService creates a service purging orders through repo. Legal holds are placed on
+// orders loaded from orders.
+func NewRetentionService(repo repositories.OrderRetentionRepository, orders repositories.OrderRepository, eventPublisher EventPublisher, policy models.RetentionPolicy) (*RetentionService, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &RetentionService{repo: repo, orders: orders, eventPublisher: eventPublisher, policy: policy, now: time.Now}, nil
+}
+
+// Run purges every order past the retention period in batches. In dry-run mode it only
+// reports what would be purged.
+func (s *RetentionService) Run(ctx context.Context, dryRun bool) (dto.RetentionReport, error) {
+	report := dto.RetentionReport{
+		DryRun:   dryRun,
+		Action:   string(s.policy.Action),
+		OrderIDs: []string{},
+		Cutoff:   s.policy.Cutoff(s.now()),
+	}
+
+	var afterID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		batch, err := s.repo.FindExpired(ctx, report.Cutoff, afterID, s.policy.BatchSize)
+		if err != nil {
+			return report, err
+		}
+		if len(batch) == 0 {
+			return report, nil
+		}
+		afterID = batch[len(batch)-1].ID
+		report.Matched += len(batch)
+
+		if dryRun {
+			for _, order := range batch {
+				addReportedOrderID(&report, order.OrderID)
+			}
+			continue
+		}
+
+		audits, err := s.purge(ctx, batch)
+		if err != nil {
+			return report, err
+		}
+		report.Purged += len(audits)
+
+		purged := make(map[string]bool, len(audits))
+		for _, audit := range audits {
+			purged[audit.OrderID] = true
+			addReportedOrderID(&report, audit.OrderID)
+		}
+		for _, order := range batch {
+			if !purged[order.OrderID] {
+				continue
+			}
+			err := s.eventPublisher.Publish(ctx, events.OrderPurgedEvent{OrderID: order.ID, Action: string(s.policy.Action)})
+			if err != nil {
+				return report, err
+			}
+		}
+	}
+}
+
+// SetLegalHold places an order on legal hold, or releases it, so retention skips it
+func (s *RetentionService) SetLegalHold(ctx context.Context, id uint, hold bool) error {
+	// Read from the primary so the hold is saved against the current version
+	order, err := s.orders.FindByID(repositories.WithReadYourWrites(ctx), id)
+	if err != nil {
+		return err
+	}
+
+	order.SetLegalHold(hold)
+	if err := s.repo.SaveLegalHold(ctx, order); err != nil {
+		return err
+	}
+
+	return s.eventPublisher.Publish(ctx, events.OrderLegalHoldChangedEvent{
+		OrderID:   order.ID,
+		Reference: order.OrderID,
+		LegalHold: order.LegalHold,
+		Version:   order.Version,
+		UpdatedAt: order.UpdatedAt,
+	})
+}
+
+func (s *RetentionService) purge(ctx context.Context, orders []models.Order) ([]models.OrderPurgeAudit, error) {
+	if s.policy.Action == models.RetentionAnonymize {
+		return s.repo.AnonymizeOrders(ctx, orders)
+	}
+	return s.repo.DeleteOrders(ctx, orders)
+}
+
+func addReportedOrderID(report *dto.RetentionReport, orderID string) {
+	if len(report.OrderIDs) < maxReportedOrders {
+		report.OrderIDs = append(report.OrderIDs, orderID)
+	}
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"order-service/internal/domain/events"
+	"order-service/internal/domain/models"
+	"order-service/internal/domain/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockOrderRetentionRepository is a mock implementation of the OrderRetentionRepository interface
+type MockOrderRetentionRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderRetentionRepository) FindExpired(ctx context.Context, cutoff time.Time, afterID uint, limit int) ([]models.Order, error) {
+	args := m.Called(ctx, cutoff, afterID, limit)
+	return args.Get(0).([]models.Order), args.Error(1)
+}
+
+func (m *MockOrderRetentionRepository) DeleteOrders(ctx context.Context, orders []models.Order) ([]models.OrderPurgeAudit, error) {
+	args := m.Called(ctx, orders)
+	return args.Get(0).([]models.OrderPurgeAudit), args.Error(1)
+}
+
+func (m *MockOrderRetentionRepository) AnonymizeOrders(ctx context.Context, orders []models.Order) ([]models.OrderPurgeAudit, error) {
+	args := m.Called(ctx, orders)
+	return args.Get(0).([]models.OrderPurgeAudit), args.Error(1)
+}
+
+func (m *MockOrderRetentionRepository) SaveLegalHold(ctx context.Context, order *models.Order) error {
+	args := m.Called(ctx, order)
+	if args.Error(0) == nil {
+		order.Version++
+	}
+	return args.Error(0)
+}
+
+func newTestRetentionService(t *testing.T, repo *MockOrderRetentionRepository, publisher *MockEventPublisher, action models.RetentionAction) *RetentionService {
+	service, err := NewRetentionService(repo, new(MockOrderRepository), publisher, models.RetentionPolicy{Period: 24 * time.Hour, Action: action, BatchSize: 2})
+	assert.NoError(t, err)
+	now := time.Date(2025, time.January, 8, 0, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	return service
+}
+
+// TestRetentionDryRun tests that a dry run reports matches without purging anything
+func TestRetentionDryRun(t *testing.T) {
+	mockRepo := new(MockOrderRetentionRepository)
+	mockPublisher := new(MockEventPublisher)
+	service := newTestRetentionService(t, mockRepo, mockPublisher, models.RetentionDelete)
+
+	cutoff := time.Date(2025, time.January, 7, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("FindExpired", mock.Anything, cutoff, uint(0), 2).Return([]models.Order{{ID: 1, OrderID: "a"}, {ID: 2, OrderID: "b"}}, nil)
+	mockRepo.On("FindExpired", mock.Anything, cutoff, uint(2), 2).Return([]models.Order{{ID: 5, OrderID: "c"}}, nil)
+	mockRepo.On("FindExpired", mock.Anything, cutoff, uint(5), 2).Return([]models.Order{}, nil)
+
+	report, err := service.Run(context.Background(), true)
+	assert.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 3, report.Matched)
+	assert.Equal(t, 0, report.Purged)
+	assert.Equal(t, []string{"a", "b", "c"}, report.OrderIDs)
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "DeleteOrders", mock.Anything, mock.Anything)
+	mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+}
+
+// TestRetentionAnonymizes tests that purged orders are reported and announced, skipping held ones
+func TestRetentionAnonymizes(t *testing.T) {
+	mockRepo := new(MockOrderRetentionRepository)
+	mockPublisher := new(MockEventPublisher)
+	service := newTestRetentionService(t, mockRepo, mockPublisher, models.RetentionAnonymize)
+
+	batch := []models.Order{{ID: 1, OrderID: "a"}, {ID: 2, OrderID: "b"}}
+	mockRepo.On("FindExpired", mock.Anything, mock.Anything, uint(0), 2).Return(batch, nil)
+	mockRepo.On("FindExpired", mock.Anything, mock.Anything, uint(2), 2).Return([]models.Order{}, nil)
+	// Order "b" was put on legal hold after it was selected
+	mockRepo.On("AnonymizeOrders", mock.Anything, batch).Return([]models.OrderPurgeAudit{{OrderID: "a", Action: models.RetentionAnonymize}}, nil)
+	mockPublisher.On("Publish", mock.Anything, events.OrderPurgedEvent{OrderID: 1, Action: "anonymize"}).Return(nil)
+
+	report, err := service.Run(context.Background(), false)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.Matched)
+	assert.Equal(t, 1, report.Purged)
+	assert.Equal(t, []string{"a"}, report.OrderIDs)
+
+	mockRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+}
+
+// TestRetentionPolicyValidate tests that incomplete policies are rejected
+func TestRetentionPolicyValidate(t *testing.T) {
+	_, err := NewRetentionService(nil, nil, nil, models.RetentionPolicy{Period: time.Hour, Action: "shred", BatchSize: 1})
+	assert.Error(t, err)
+	_, err = NewRetentionService(nil, nil, nil, models.RetentionPolicy{Action: models.RetentionDelete, BatchSize: 1})
+	assert.Error(t, err)
+}
+
+// TestSetLegalHold tests that a hold is saved against the loaded version and announced with the new one
+func TestSetLegalHold(t *testing.T) {
+	mockRepo := new(MockOrderRetentionRepository)
+	mockOrders := new(MockOrderRepository)
+	mockPublisher := new(MockEventPublisher)
+	service, err := NewRetentionService(mockRepo, mockOrders, mockPublisher, models.RetentionPolicy{Period: time.Hour, Action: models.RetentionDelete, BatchSize: 1})
+	assert.NoError(t, err)
+
+	mockOrders.On("FindByID", mock.MatchedBy(repositories.ReadYourWrites), uint(1)).Return(&models.Order{ID: 1, OrderID: "a", Version: 3}, nil)
+	mockRepo.On("SaveLegalHold", mock.Anything, mock.MatchedBy(func(order *models.Order) bool { return order.LegalHold })).Return(nil)
+	mockPublisher.On("Publish", mock.Anything, events.OrderLegalHoldChangedEvent{OrderID: 1, Reference: "a", LegalHold: true, Version: 4}).Return(nil)
+
+	assert.NoError(t, service.SetLegalHold(context.Background(), 1, true))
+	mockOrders.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+
+	// A hold on a stale version is refused and not announced
+	conflictRepo := new(MockOrderRetentionRepository)
+	conflictRepo.On("SaveLegalHold", mock.Anything, mock.Anything).Return(repositories.ErrVersionConflict)
+	service.repo = conflictRepo
+	assert.ErrorIs(t, service.SetLegalHold(context.Background(), 1, true), repositories.ErrVersionConflict)
+	mockPublisher.AssertNumberOfCalls(t, "Publish", 1)
+}
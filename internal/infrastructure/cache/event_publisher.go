@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"context"
+	"order-service/internal/domain/events"
+	"order-service/internal/domain/services"
+)
+
+// InvalidatingEventPublisher evicts cached orders named by the events it publishes
+type InvalidatingEventPublisher struct {
+	next  services.EventPublisher
+	cache *OrderRepository
+}
+
+// NewInvalidatingEventPublisher wraps next so order events invalidate cache entries
+func NewInvalidatingEventPublisher(next services.EventPublisher, cache *OrderRepository) *InvalidatingEventPublisher {
+	return &InvalidatingEventPublisher{next: next, cache: cache}
+}
+
+func (p *InvalidatingEventPublisher) Publish(ctx context.Context, event interface{}) error {
+	switch e := event.(type) {
+	case events.OrderCreatedEvent:
+		p.cache.Invalidate(ctx, e.OrderID)
+	case events.OrderItemAddedEvent:
+		p.cache.Invalidate(ctx, e.OrderID)
+	case events.OrderCancelledEvent:
+		p.cache.Invalidate(ctx, e.OrderID)
+	case events.OrderUpdatedEvent:
+		p.cache.Invalidate(ctx, e.OrderID)
+	case events.OrderDeletedEvent:
+		p.cache.Invalidate(ctx, e.OrderID)
+	case events.OrderLegalHoldChangedEvent:
+		p.cache.Invalidate(ctx, e.OrderID)
+	case events.OrderPurgedEvent:
+		p.cache.Invalidate(ctx, e.OrderID)
+	}
+	return p.next.Publish(ctx, event)
+}
@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLRUStoreEvictsLeastRecentlyUsed tests that the oldest untouched entry is evicted at capacity
+func TestLRUStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	store := NewLRUStore(2)
+
+	assert.NoError(t, store.Set(ctx, "a", []byte("1"), 0))
+	assert.NoError(t, store.Set(ctx, "b", []byte("2"), 0))
+	_, err := store.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.NoError(t, store.Set(ctx, "c", []byte("3"), 0))
+
+	_, err = store.Get(ctx, "b")
+	assert.ErrorIs(t, err, ErrMiss)
+	value, err := store.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("1"), value)
+	assert.Equal(t, 2, store.Len())
+}
+
+// TestLRUStoreExpiresEntries tests that entries are not returned after their TTL
+func TestLRUStoreExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewLRUStore(10)
+	store.now = func() time.Time { return now }
+
+	assert.NoError(t, store.Set(ctx, "a", []byte("1"), time.Minute))
+	_, err := store.Get(ctx, "a")
+	assert.NoError(t, err)
+
+	now = now.Add(time.Minute)
+	_, err = store.Get(ctx, "a")
+	assert.ErrorIs(t, err, ErrMiss)
+	assert.Equal(t, 0, store.Len())
+}
@@ -0,0 +1,19 @@
+package cache
+
+import "expvar"
+
+// Metrics are published through expvar under "order_cache" and served at /debug/vars
+var (
+	metrics       = expvar.NewMap("order_cache")
+	hits          = new(expvar.Int)
+	misses        = new(expvar.Int)
+	invalidations = new(expvar.Int)
+	failures      = new(expvar.Int)
+)
+
+func init() {
+	metrics.Set("hits", hits)
+	metrics.Set("misses", misses)
+	metrics.Set("invalidations", invalidations)
+	metrics.Set("errors", failures)
+}
@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"order-service/internal/domain/models"
+	"order-service/internal/domain/repositories"
+	"order-service/internal/infrastructure/logging"
+	"order-service/internal/infrastructure/persistence"
+	"strconv"
+	"time"
+)
+
+// OrderRepository caches FindByID results in front of another OrderRepository. Loads inside a
+// transaction bypass the cache, so commands always decide on the committed row and never cache
+// one their transaction has not committed yet.
+type OrderRepository struct {
+	next  repositories.OrderRepository
+	store Store
+	ttl   time.Duration
+}
+
+// NewOrderRepository wraps next with a cache backed by store
+func NewOrderRepository(next repositories.OrderRepository, store Store, ttl time.Duration) *OrderRepository {
+	return &OrderRepository{next: next, store: store, ttl: ttl}
+}
+
+func (r *OrderRepository) Save(ctx context.Context, order *models.Order) error {
+	if err := r.next.Save(ctx, order); err != nil {
+		// The order changed since it was loaded, so the cached copy it may have come from is stale
+		if errors.Is(err, repositories.ErrVersionConflict) && order.ID != 0 {
+			r.Invalidate(ctx, order.ID)
+		}
+		return err
+	}
+	if order.ID != 0 {
+		r.Invalidate(ctx, order.ID)
+	}
+	return nil
+}
+
+func (r *OrderRepository) FindByID(ctx context.Context, id uint) (*models.Order, error) {
+	if persistence.InTransaction(ctx) {
+		return r.next.FindByID(ctx, id)
+	}
+	key := orderKey(id)
+
+	cached, err := r.store.Get(ctx, key)
+	if err == nil {
+		var order models.Order
+		if err := json.Unmarshal(cached, &order); err == nil {
+			hits.Add(1)
+			return &order, nil
+		}
+	} else if !errors.Is(err, ErrMiss) {
+		failures.Add(1)
+		logging.Ctx(ctx).Warn().Msgf("order cache read failed for %s: %v", key, err)
+	}
+	misses.Add(1)
+
+	order, err := r.next.FindByID(ctx, id)
+	if err != nil {
+		return order, err
+	}
+
+	if encoded, err := json.Marshal(order); err == nil {
+		if err := r.store.Set(ctx, key, encoded, r.ttl); err != nil {
+			failures.Add(1)
+			logging.Ctx(ctx).Warn().Msgf("order cache write failed for %s: %v", key, err)
+		}
+	}
+	return order, nil
+}
+
+func (r *OrderRepository) FindAll(ctx context.Context) ([]models.Order, error) {
+	return r.next.FindAll(ctx)
+}
+
+// Invalidate drops the cached copy of an order once the transaction in ctx, if any, has ended
+func (r *OrderRepository) Invalidate(ctx context.Context, id uint) {
+	persistence.AfterTransaction(ctx, func() { r.evict(ctx, id) })
+}
+
+func (r *OrderRepository) evict(ctx context.Context, id uint) {
+	invalidations.Add(1)
+	if err := r.store.Delete(ctx, orderKey(id)); err != nil {
+		failures.Add(1)
+		logging.Ctx(ctx).Warn().Msgf("order cache invalidation failed for order %d: %v", id, err)
+	}
+}
+
+func orderKey(id uint) string {
+	return "order:" + strconv.FormatUint(uint64(id), 10)
+}
@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"context"
+	"order-service/internal/domain/events"
+	"order-service/internal/domain/models"
+	"order-service/internal/domain/repositories"
+	"order-service/internal/infrastructure/persistence"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/glebarez/sqlite"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// MockOrderRepository is a mock implementation of the OrderRepository interface
+type MockOrderRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrderRepository) Save(ctx context.Context, order *models.Order) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepository) FindByID(ctx context.Context, id uint) (*models.Order, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Order), args.Error(1)
+}
+
+func (m *MockOrderRepository) FindAll(ctx context.Context) ([]models.Order, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Order), args.Error(1)
+}
+
+// MockEventPublisher is a mock implementation of the EventPublisher interface
+type MockEventPublisher struct {
+	mock.Mock
+}
+
+func (m *MockEventPublisher) Publish(ctx context.Context, event interface{}) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func sampleOrder() *models.Order {
+	return &models.Order{
+		ID:          1,
+		OrderID:     "test-1",
+		CustomerID:  123,
+		OrderItems:  []models.OrderItem{{OrderID: "test-1", ProductID: 1, Quantity: 2, Price: 9.99}},
+		TotalAmount: 19.98,
+	}
+}
+
+// TestFindByIDIsCached tests that a second lookup is served from the cache
+func TestFindByIDIsCached(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockOrderRepository)
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(sampleOrder(), nil).Once()
+
+	repo := NewOrderRepository(mockRepo, NewLRUStore(10), time.Minute)
+	hitsBefore := hits.Value()
+
+	first, err := repo.FindByID(ctx, 1)
+	assert.NoError(t, err)
+	second, err := repo.FindByID(ctx, 1)
+	assert.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, hitsBefore+1, hits.Value())
+	mockRepo.AssertExpectations(t)
+}
+
+// TestSaveInvalidates tests that saving an order evicts its cached copy
+func TestSaveInvalidates(t *testing.T) {
+	ctx := context.Background()
+	order := sampleOrder()
+	mockRepo := new(MockOrderRepository)
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(order, nil).Twice()
+	mockRepo.On("Save", mock.Anything, order).Return(nil)
+
+	repo := NewOrderRepository(mockRepo, NewLRUStore(10), time.Minute)
+
+	_, err := repo.FindByID(ctx, 1)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Save(ctx, order))
+	_, err = repo.FindByID(ctx, 1)
+	assert.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestConflictingSaveInvalidates tests that a save rejected as stale evicts the cached copy it was based on
+func TestConflictingSaveInvalidates(t *testing.T) {
+	ctx := context.Background()
+	order := sampleOrder()
+	mockRepo := new(MockOrderRepository)
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(order, nil).Twice()
+	mockRepo.On("Save", mock.Anything, order).Return(repositories.ErrVersionConflict)
+
+	repo := NewOrderRepository(mockRepo, NewLRUStore(10), time.Minute)
+
+	_, err := repo.FindByID(ctx, 1)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, repo.Save(ctx, order), repositories.ErrVersionConflict)
+	_, err = repo.FindByID(ctx, 1)
+	assert.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestFindByIDInTransactionBypassesCache tests that loads inside a transaction neither read nor fill the cache
+func TestFindByIDInTransactionBypassesCache(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	require.NoError(t, err)
+	manager := persistence.NewGormTransactionManager(db)
+
+	mockRepo := new(MockOrderRepository)
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(sampleOrder(), nil).Times(3)
+	store := NewLRUStore(10)
+	repo := NewOrderRepository(mockRepo, store, time.Minute)
+
+	_, err = repo.FindByID(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.NoError(t, manager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		_, err := repo.FindByID(ctx, 1)
+		return err
+	}))
+	assert.NoError(t, store.Delete(context.Background(), orderKey(1)))
+	assert.NoError(t, manager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		_, err := repo.FindByID(ctx, 1)
+		return err
+	}))
+	_, err = store.Get(context.Background(), orderKey(1))
+	assert.ErrorIs(t, err, ErrMiss, "a load inside a transaction is not cached")
+
+	mockRepo.AssertExpectations(t)
+}
+
+// TestPublishedEventsInvalidate tests that order events evict the cached order
+func TestPublishedEventsInvalidate(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	store := NewRedisStore(redis.NewClient(&redis.Options{Addr: server.Addr()}), "orders:")
+
+	mockRepo := new(MockOrderRepository)
+	mockRepo.On("FindByID", mock.Anything, uint(1)).Return(sampleOrder(), nil)
+	mockPublisher := new(MockEventPublisher)
+	mockPublisher.On("Publish", mock.Anything, mock.Anything).Return(nil)
+
+	repo := NewOrderRepository(mockRepo, store, time.Minute)
+	publisher := NewInvalidatingEventPublisher(mockPublisher, repo)
+
+	_, err := repo.FindByID(ctx, 1)
+	assert.NoError(t, err)
+	assert.True(t, server.Exists("orders:order:1"))
+
+	assert.NoError(t, publisher.Publish(context.Background(), events.OrderCreatedEvent{OrderID: 1}))
+	assert.False(t, server.Exists("orders:order:1"))
+	mockPublisher.AssertExpectations(t)
+}
@@ -0,0 +1,388 @@
+// Package dynamo stores orders in a single DynamoDB table.
+package dynamo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"order-service/internal/domain/models"
+	"order-service/internal/domain/repositories"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// maxTransactItems is the DynamoDB limit on actions in one TransactWriteItems call
+const maxTransactItems = 100
+
+// API is the subset of the DynamoDB client the repository uses
+type API interface {
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+	Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)
+	DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+	CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error)
+}
+
+// NewClient creates a DynamoDB client for region. A non-empty endpoint overrides the AWS endpoint,
+// for example http://localhost:8000 for DynamoDB Local.
+func NewClient(ctx context.Context, region, endpoint string) (*dynamodb.Client, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("unable to load SDK config: %w", err)
+	}
+	return dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+	}), nil
+}
+
+// OrderRepository is a repositories.OrderRepository backed by a single DynamoDB table
+type OrderRepository struct {
+	client API
+	table  string
+}
+
+func NewOrderRepository(client API, table string) *OrderRepository {
+	return &OrderRepository{client: client, table: table}
+}
+
+// EnsureTable creates the table with on-demand capacity when it does not exist yet
+func (r *OrderRepository) EnsureTable(ctx context.Context) error {
+	_, err := r.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(r.table)})
+	var notFound *types.ResourceNotFoundException
+	if err == nil || !errors.As(err, &notFound) {
+		return err
+	}
+
+	_, err = r.client.CreateTable(ctx, &dynamodb.CreateTableInput{
+		TableName: aws.String(r.table),
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("PK"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("SK"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("PK"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("SK"), KeyType: types.KeyTypeRange},
+		},
+		BillingMode: types.BillingModePayPerRequest,
+	})
+	if err != nil {
+		return err
+	}
+	return dynamodb.NewTableExistsWaiter(r.client).Wait(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(r.table)}, time.Minute)
+}
+
+// Save writes the order and its items in one transaction. A new order claims its OrderID and
+// fails with ErrOrderIDTaken if another order holds it; an existing order is only overwritten
+// at the version that was loaded and fails with ErrVersionConflict otherwise.
+func (r *OrderRepository) Save(ctx context.Context, order *models.Order) error {
+	// An update also deletes the item records the order no longer has
+	var removed []string
+	if order.ID != 0 {
+		var err error
+		if removed, err = r.removedItemKeys(ctx, order); err != nil {
+			return err
+		}
+	}
+	if len(order.OrderItems)+len(removed)+2 > maxTransactItems {
+		return fmt.Errorf("order has %d items, at most %d fit in one transaction", len(order.OrderItems)+len(removed), maxTransactItems-2)
+	}
+
+	// Work on a copy so a failed save leaves the caller's order untouched
+	saved := *order
+	saved.OrderItems = append([]models.OrderItem(nil), order.OrderItems...)
+	creating := saved.ID == 0
+
+	now := time.Now()
+	if creating {
+		id, err := r.nextIDs(ctx, "order", 1)
+		if err != nil {
+			return err
+		}
+		saved.ID = id
+		saved.Version = 1
+		saved.CreatedAt = now
+		if saved.Status == "" {
+			saved.Status = models.OrderStatusPending
+		}
+	} else {
+		// Like the Postgres repository, UpdatedAt is left as the caller set it until the first update
+		saved.Version++
+		saved.UpdatedAt = now
+	}
+
+	if err := r.assignItemIDs(ctx, &saved); err != nil {
+		return err
+	}
+
+	put := &types.Put{TableName: aws.String(r.table), Item: orderRecord(&saved)}
+	writes := []types.TransactWriteItem{{Put: put}}
+	if creating {
+		put.ConditionExpression = aws.String("attribute_not_exists(PK)")
+		writes = append(writes, types.TransactWriteItem{Put: &types.Put{
+			TableName:           aws.String(r.table),
+			Item:                orderIDRecord(saved.OrderID, saved.ID),
+			ConditionExpression: aws.String("attribute_not_exists(PK)"),
+		}})
+	} else {
+		put.ConditionExpression = aws.String("Version = :expected")
+		put.ExpressionAttributeValues = item{":expected": num(uint64(order.Version))}
+	}
+	for _, orderItem := range saved.OrderItems {
+		writes = append(writes, types.TransactWriteItem{Put: &types.Put{
+			TableName: aws.String(r.table),
+			Item:      orderItemRecord(saved.ID, orderItem),
+		}})
+	}
+	for _, sk := range removed {
+		writes = append(writes, types.TransactWriteItem{Delete: &types.Delete{
+			TableName: aws.String(r.table),
+			Key:       key(orderPK(saved.ID), sk),
+		}})
+	}
+
+	_, err := r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: writes})
+	if err != nil {
+		return saveError(err, creating)
+	}
+	*order = saved
+	return nil
+}
+
+func (r *OrderRepository) FindByID(ctx context.Context, id uint) (*models.Order, error) {
+	var records []item
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.table),
+		KeyConditionExpression:    aws.String("PK = :pk"),
+		ExpressionAttributeValues: item{":pk": str(orderPK(id))},
+		ConsistentRead:            aws.Bool(repositories.ReadYourWrites(ctx)),
+	}
+	for {
+		output, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, output.Items...)
+		if len(output.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+
+	orders, err := decodeOrders(records)
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 || orders[0].DeletedAt != nil {
+		return nil, repositories.ErrOrderNotFound
+	}
+	return &orders[0], nil
+}
+
+// FindAll scans every order and item record, skipping deleted orders; items of one order may
+// span scan pages
+func (r *OrderRepository) FindAll(ctx context.Context) ([]models.Order, error) {
+	var records []item
+	input := &dynamodb.ScanInput{
+		TableName:                 aws.String(r.table),
+		FilterExpression:          aws.String("begins_with(PK, :prefix)"),
+		ExpressionAttributeValues: item{":prefix": str(orderPrefix)},
+		ConsistentRead:            aws.Bool(repositories.ReadYourWrites(ctx)),
+	}
+	for {
+		output, err := r.client.Scan(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, output.Items...)
+		if len(output.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+
+	orders, err := decodeOrders(records)
+	if err != nil {
+		return nil, err
+	}
+	live := []models.Order{}
+	for _, order := range orders {
+		if order.DeletedAt == nil {
+			live = append(live, order)
+		}
+	}
+	return live, nil
+}
+
+// removedItemKeys returns the sort keys of stored items that order no longer contains
+func (r *OrderRepository) removedItemKeys(ctx context.Context, order *models.Order) ([]string, error) {
+	kept := make(map[string]bool, len(order.OrderItems))
+	for _, orderItem := range order.OrderItems {
+		if orderItem.ID != 0 {
+			kept[itemSK(orderItem.ID)] = true
+		}
+	}
+
+	var removed []string
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(r.table),
+		KeyConditionExpression:    aws.String("PK = :pk AND begins_with(SK, :item)"),
+		ExpressionAttributeValues: item{":pk": str(orderPK(order.ID)), ":item": str(itemPrefix)},
+		ProjectionExpression:      aws.String("SK"),
+		ConsistentRead:            aws.Bool(true),
+	}
+	for {
+		output, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range output.Items {
+			if sk := getString(record, "SK"); !kept[sk] {
+				removed = append(removed, sk)
+			}
+		}
+		if len(output.LastEvaluatedKey) == 0 {
+			return removed, nil
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
+
+// assignItemIDs gives every new item an ID and copies the order's reference and date onto it
+func (r *OrderRepository) assignItemIDs(ctx context.Context, order *models.Order) error {
+	missing := 0
+	for _, orderItem := range order.OrderItems {
+		if orderItem.ID == 0 {
+			missing++
+		}
+	}
+
+	next := uint(0)
+	if missing > 0 {
+		last, err := r.nextIDs(ctx, "item", missing)
+		if err != nil {
+			return err
+		}
+		next = last - uint(missing) + 1
+	}
+
+	for i := range order.OrderItems {
+		if order.OrderItems[i].ID == 0 {
+			order.OrderItems[i].ID = next
+			next++
+		}
+		order.OrderItems[i].OrderID = order.OrderID
+		order.OrderItems[i].OrderDate = order.OrderDate
+	}
+	return nil
+}
+
+// nextIDs reserves count IDs from the named counter and returns the last one
+func (r *OrderRepository) nextIDs(ctx context.Context, counter string, count int) (uint, error) {
+	output, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.table),
+		Key:                       key(counterKey, counter),
+		UpdateExpression:          aws.String("ADD #value :count"),
+		ExpressionAttributeNames:  map[string]string{"#value": "Value"},
+		ExpressionAttributeValues: item{":count": num(uint64(count))},
+		ReturnValues:              types.ReturnValueUpdatedNew,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to allocate %s IDs: %w", counter, err)
+	}
+	value, ok := output.Attributes["Value"].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, fmt.Errorf("counter %s returned no value", counter)
+	}
+	last, err := strconv.ParseUint(value.Value, 10, 64)
+	return uint(last), err
+}
+
+// saveError translates failed conditions of a Save transaction into repository errors.
+// The order record is always the first write and the OrderID claim the second.
+func saveError(err error, creating bool) error {
+	var cancelled *types.TransactionCanceledException
+	if !errors.As(err, &cancelled) {
+		return err
+	}
+	for i, reason := range cancelled.CancellationReasons {
+		if aws.ToString(reason.Code) != "ConditionalCheckFailed" {
+			continue
+		}
+		switch {
+		case creating && i == 1:
+			return repositories.ErrOrderIDTaken
+		case !creating && i == 0:
+			return repositories.ErrVersionConflict
+		}
+	}
+	return err
+}
+
+// CreateBatch saves the orders one at a time; DynamoDB transactions are too small for a whole batch.
+// When a save fails, the orders before it stay saved and are reported by a PartialBatchError.
+func (r *OrderRepository) CreateBatch(ctx context.Context, orders []models.Order) error {
+	for i := range orders {
+		if err := r.Save(ctx, &orders[i]); err != nil {
+			return &repositories.PartialBatchError{Stored: i, Err: err}
+		}
+	}
+	return nil
+}
+
+// FindInBatches pages through the table with a scan of batchSize records per page and calls fn
+// once per page with its orders, in scan order. Items sort before their order record, so the
+// items after a page's last order record belong to an order that continues on the next page
+// and are held back until it arrives.
+func (r *OrderRepository) FindInBatches(ctx context.Context, batchSize int, fn func(orders []models.Order) error) error {
+	input := &dynamodb.ScanInput{
+		TableName:                 aws.String(r.table),
+		FilterExpression:          aws.String("begins_with(PK, :prefix)"),
+		ExpressionAttributeValues: item{":prefix": str(orderPrefix)},
+		ConsistentRead:            aws.Bool(repositories.ReadYourWrites(ctx)),
+		Limit:                     aws.Int32(int32(batchSize)),
+	}
+	var pending []item
+	for {
+		output, err := r.client.Scan(ctx, input)
+		if err != nil {
+			return err
+		}
+		records := append(pending, output.Items...)
+		complete := 0
+		for i, record := range records {
+			if getString(record, "SK") == orderSortKey {
+				complete = i + 1
+			}
+		}
+		pending = append([]item(nil), records[complete:]...)
+
+		orders, err := decodeOrders(records[:complete])
+		if err != nil {
+			return err
+		}
+		live := make([]models.Order, 0, len(orders))
+		for _, order := range orders {
+			if order.DeletedAt == nil {
+				live = append(live, order)
+			}
+		}
+		if len(live) > 0 {
+			if err := fn(live); err != nil {
+				return err
+			}
+		}
+
+		if len(output.LastEvaluatedKey) == 0 {
+			return nil
+		}
+		input.ExclusiveStartKey = output.LastEvaluatedKey
+	}
+}
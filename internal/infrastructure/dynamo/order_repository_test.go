@@ -0,0 +1,190 @@
+package dynamo
+
+import (
+	"context"
+	"fmt"
+	"order-service/internal/domain/models"
+	"order-service/internal/domain/repositories"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newLocalRepository connects to DynamoDB Local at DYNAMODB_ENDPOINT, skipping the test when it is unset,
+// e.g. DYNAMODB_ENDPOINT=http://localhost:8000 with amazon/dynamodb-local running
+func newLocalRepository(t *testing.T) *OrderRepository {
+	endpoint := os.Getenv("DYNAMODB_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("DYNAMODB_ENDPOINT is not set")
+	}
+	t.Setenv("AWS_ACCESS_KEY_ID", "local")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "local")
+
+	ctx := context.Background()
+	client, err := NewClient(ctx, "us-east-2", endpoint)
+	require.NoError(t, err)
+
+	repo := NewOrderRepository(client, fmt.Sprintf("orders-test-%d", time.Now().UnixNano()))
+	require.NoError(t, repo.EnsureTable(ctx))
+	return repo
+}
+
+// TestOrderRepository tests saving, loading and the conditional writes against DynamoDB Local
+func TestOrderRepository(t *testing.T) {
+	repo := newLocalRepository(t)
+	ctx := repositories.WithReadYourWrites(context.Background())
+
+	order := &models.Order{
+		OrderID:    "ORD-1",
+		CustomerID: 1,
+		OrderDate:  time.Date(2024, time.October, 27, 0, 0, 0, 0, time.UTC),
+	}
+	order.AddItem(models.OrderItem{ProductID: 1, Quantity: 2, Price: 5})
+	require.NoError(t, repo.Save(ctx, order))
+	assert.NotZero(t, order.ID)
+	assert.Equal(t, uint(1), order.Version)
+	assert.NotZero(t, order.OrderItems[0].ID)
+	assert.True(t, order.UpdatedAt.IsZero(), "a new order has not been updated yet")
+
+	found, err := repo.FindByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, order.OrderID, found.OrderID)
+	assert.Len(t, found.OrderItems, 1)
+
+	// A second order cannot claim the same OrderID
+	duplicate := &models.Order{OrderID: "ORD-1", CustomerID: 2, OrderDate: order.OrderDate}
+	assert.ErrorIs(t, repo.Save(ctx, duplicate), repositories.ErrOrderIDTaken)
+
+	// Saving the loaded copy bumps the version, after which the stale original is rejected
+	found.AddItem(models.OrderItem{ProductID: 2, Quantity: 1, Price: 3})
+	require.NoError(t, repo.Save(ctx, found))
+	assert.Equal(t, uint(2), found.Version)
+	assert.False(t, found.UpdatedAt.IsZero())
+	assert.ErrorIs(t, repo.Save(ctx, order), repositories.ErrVersionConflict)
+
+	all, err := repo.FindAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+	assert.Len(t, all[0].OrderItems, 2)
+
+	_, err = repo.FindByID(ctx, order.ID+1000)
+	assert.ErrorIs(t, err, repositories.ErrOrderNotFound)
+}
+
+// TestCreateBatchPartial tests that a batch failing partway reports how many of its orders were saved
+func TestCreateBatchPartial(t *testing.T) {
+	repo := newLocalRepository(t)
+	ctx := repositories.WithReadYourWrites(context.Background())
+
+	date := time.Date(2024, time.October, 27, 0, 0, 0, 0, time.UTC)
+	batch := []models.Order{
+		{OrderID: "ORD-1", CustomerID: 1, OrderDate: date},
+		{OrderID: "ORD-1", CustomerID: 2, OrderDate: date},
+		{OrderID: "ORD-2", CustomerID: 3, OrderDate: date},
+	}
+	err := repo.CreateBatch(ctx, batch)
+
+	var partial *repositories.PartialBatchError
+	require.ErrorAs(t, err, &partial)
+	assert.Equal(t, 1, partial.Stored)
+	assert.ErrorIs(t, err, repositories.ErrOrderIDTaken)
+	all, err := repo.FindAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+}
+
+// pagedScan is an API whose Scan serves fixed pages, linked by their index as the start key
+type pagedScan struct {
+	API
+	pages  [][]item
+	starts []string
+}
+
+func (p *pagedScan) Scan(_ context.Context, params *dynamodb.ScanInput, _ ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	page := 0
+	if params.ExclusiveStartKey != nil {
+		page, _ = strconv.Atoi(getString(params.ExclusiveStartKey, "PK"))
+	}
+	p.starts = append(p.starts, getString(params.ExclusiveStartKey, "PK"))
+	output := &dynamodb.ScanOutput{Items: p.pages[page]}
+	if page+1 < len(p.pages) {
+		output.LastEvaluatedKey = key(strconv.Itoa(page+1), "")
+	}
+	return output, nil
+}
+
+// TestFindInBatchesPages tests that every scan page is passed on as it arrives, holding back an
+// order whose records continue on the next page
+func TestFindInBatchesPages(t *testing.T) {
+	deletedAt := time.Now()
+	first := &models.Order{ID: 1, OrderID: "ORD-1"}
+	second := &models.Order{ID: 2, OrderID: "ORD-2"}
+	deleted := &models.Order{ID: 3, OrderID: "ORD-3", DeletedAt: &deletedAt}
+	api := &pagedScan{pages: [][]item{
+		{orderItemRecord(1, models.OrderItem{ID: 1}), orderRecord(first), orderItemRecord(2, models.OrderItem{ID: 2})},
+		{orderItemRecord(2, models.OrderItem{ID: 3}), orderRecord(second)},
+		{orderRecord(deleted)},
+	}}
+	repo := NewOrderRepository(api, "orders")
+
+	var batches [][]models.Order
+	err := repo.FindInBatches(context.Background(), 3, func(orders []models.Order) error {
+		batches = append(batches, orders)
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"", "1", "2"}, api.starts, "each page starts where the last one ended")
+	require.Len(t, batches, 2, "pages without live orders are skipped")
+	require.Len(t, batches[0], 1)
+	assert.Equal(t, "ORD-1", batches[0][0].OrderID)
+	assert.Len(t, batches[0][0].OrderItems, 1)
+	require.Len(t, batches[1], 1)
+	assert.Equal(t, "ORD-2", batches[1][0].OrderID)
+	assert.Len(t, batches[1][0].OrderItems, 2, "items from both pages are kept with their order")
+}
+
+// localWrites is an API that allocates IDs from a local counter and accepts every write
+type localWrites struct {
+	API
+	counter int
+}
+
+func (r *localWrites) UpdateItem(_ context.Context, params *dynamodb.UpdateItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	count, _ := strconv.Atoi(params.ExpressionAttributeValues[":count"].(*types.AttributeValueMemberN).Value)
+	r.counter += count
+	return &dynamodb.UpdateItemOutput{Attributes: item{"Value": num(uint64(r.counter))}}, nil
+}
+
+func (r *localWrites) TransactWriteItems(context.Context, *dynamodb.TransactWriteItemsInput, ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	return &dynamodb.TransactWriteItemsOutput{}, nil
+}
+
+func (r *localWrites) Query(context.Context, *dynamodb.QueryInput, ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	return &dynamodb.QueryOutput{}, nil
+}
+
+// TestSaveUpdatedAt tests that creating an order keeps its UpdatedAt and only updates stamp it
+func TestSaveUpdatedAt(t *testing.T) {
+	repo := NewOrderRepository(&localWrites{}, "orders")
+	ctx := context.Background()
+	orderDate := time.Date(2024, time.October, 27, 0, 0, 0, 0, time.UTC)
+
+	order := &models.Order{OrderID: "ORD-1", CustomerID: 1, OrderDate: orderDate, UpdatedAt: orderDate}
+	require.NoError(t, repo.Save(ctx, order))
+	assert.Equal(t, orderDate, order.UpdatedAt)
+
+	unset := &models.Order{OrderID: "ORD-2", CustomerID: 1, OrderDate: orderDate}
+	require.NoError(t, repo.Save(ctx, unset))
+	assert.True(t, unset.UpdatedAt.IsZero())
+
+	before := time.Now()
+	require.NoError(t, repo.Save(ctx, order))
+	assert.False(t, order.UpdatedAt.Before(before), "an update stamps UpdatedAt")
+}
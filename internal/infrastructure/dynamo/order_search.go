@@ -0,0 +1,22 @@
+package dynamo
+
+import (
+	"context"
+	"order-service/internal/domain/domainerr"
+	"order-service/internal/domain/repositories"
+)
+
+// ErrSearchNotSupported is returned for every search, the table has no full-text index
+var ErrSearchNotSupported = domainerr.NotSupported("search_not_supported", "order search is not available when orders are stored in DynamoDB")
+
+// OrderSearchRepository stands in for search when orders are stored in DynamoDB. The Postgres
+// search index is built from the orders table, which DynamoDB orders never reach.
+type OrderSearchRepository struct{}
+
+func NewOrderSearchRepository() repositories.OrderSearchRepository {
+	return OrderSearchRepository{}
+}
+
+func (OrderSearchRepository) Search(context.Context, repositories.OrderSearchQuery) (repositories.OrderSearchPage, error) {
+	return repositories.OrderSearchPage{}, ErrSearchNotSupported
+}
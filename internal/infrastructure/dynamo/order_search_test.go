@@ -0,0 +1,20 @@
+package dynamo
+
+import (
+	"context"
+	"order-service/internal/domain/domainerr"
+	"order-service/internal/domain/repositories"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOrderSearchRepository tests that search is refused with a domain error instead of an empty page
+func TestOrderSearchRepository(t *testing.T) {
+	_, err := NewOrderSearchRepository().Search(context.Background(), repositories.OrderSearchQuery{Text: "widget", Page: 1, PageSize: 20})
+	assert.ErrorIs(t, err, ErrSearchNotSupported)
+
+	domainErr, ok := domainerr.As(err)
+	assert.True(t, ok)
+	assert.Equal(t, domainerr.KindNotSupported, domainErr.Kind)
+}
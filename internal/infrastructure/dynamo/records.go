@@ -0,0 +1,244 @@
+package dynamo
+
+import (
+	"fmt"
+	"order-service/internal/domain/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Single-table layout. An order and its items share the partition key ORDER#<id>:
+//
+//	PK=ORDER#<id>      SK=ORDER              the order itself
+//	PK=ORDER#<id>      SK=ITEM#<item id>     one record per order item
+//	PK=ORDERID#<ref>   SK=ORDERID            claims an OrderID so it stays unique
+//	PK=COUNTER         SK=<name>             sequence used to assign numeric IDs
+const (
+	orderPrefix   = "ORDER#"
+	orderSortKey  = "ORDER"
+	itemPrefix    = "ITEM#"
+	orderIDPrefix = "ORDERID#"
+	orderIDSort   = "ORDERID"
+	counterKey    = "COUNTER"
+)
+
+type item = map[string]types.AttributeValue
+
+func orderPK(id uint) string {
+	return orderPrefix + strconv.FormatUint(uint64(id), 10)
+}
+
+// itemSK zero-pads the item ID so items sort in insertion order
+func itemSK(id uint) string {
+	return fmt.Sprintf("%s%020d", itemPrefix, id)
+}
+
+func key(pk, sk string) item {
+	return item{"PK": str(pk), "SK": str(sk)}
+}
+
+// orderRecord stores every Order field except its items
+func orderRecord(order *models.Order) item {
+	record := key(orderPK(order.ID), orderSortKey)
+	record["ID"] = num(uint64(order.ID))
+	record["OrderID"] = str(order.OrderID)
+	record["CustomerID"] = num(uint64(order.CustomerID))
+	record["CustomerName"] = str(order.CustomerName)
+	record["TotalAmount"] = float(order.TotalAmount)
+	record["Status"] = str(string(order.Status))
+	record["OrderDate"] = timestamp(order.OrderDate)
+	record["LegalHold"] = &types.AttributeValueMemberBOOL{Value: order.LegalHold}
+	record["Version"] = num(uint64(order.Version))
+	record["CreatedAt"] = timestamp(order.CreatedAt)
+	record["UpdatedAt"] = timestamp(order.UpdatedAt)
+	if order.AnonymizedAt != nil {
+		record["AnonymizedAt"] = timestamp(*order.AnonymizedAt)
+	}
+	if order.DeletedAt != nil {
+		record["DeletedAt"] = timestamp(*order.DeletedAt)
+	}
+	return record
+}
+
+// orderItemRecord stores an item as a child record of orderID
+func orderItemRecord(orderID uint, orderItem models.OrderItem) item {
+	record := key(orderPK(orderID), itemSK(orderItem.ID))
+	record["ID"] = num(uint64(orderItem.ID))
+	record["OrderID"] = str(orderItem.OrderID)
+	record["OrderDate"] = timestamp(orderItem.OrderDate)
+	record["ProductID"] = num(uint64(orderItem.ProductID))
+	record["ProductName"] = str(orderItem.ProductName)
+	record["Quantity"] = &types.AttributeValueMemberN{Value: strconv.Itoa(orderItem.Quantity)}
+	record["Price"] = float(orderItem.Price)
+	return record
+}
+
+// orderIDRecord claims an OrderID for the order with the given ID
+func orderIDRecord(orderID string, id uint) item {
+	record := key(orderIDPrefix+orderID, orderIDSort)
+	record["ID"] = num(uint64(id))
+	return record
+}
+
+// decodeOrders assembles orders from their order and item records, in the order the records arrive
+func decodeOrders(records []item) ([]models.Order, error) {
+	var orders []models.Order
+	index := map[string]int{}
+	items := map[string][]models.OrderItem{}
+
+	for _, record := range records {
+		pk, sk := getString(record, "PK"), getString(record, "SK")
+		switch {
+		case sk == orderSortKey:
+			order, err := decodeOrder(record)
+			if err != nil {
+				return nil, err
+			}
+			index[pk] = len(orders)
+			orders = append(orders, order)
+		case strings.HasPrefix(sk, itemPrefix):
+			orderItem, err := decodeOrderItem(record)
+			if err != nil {
+				return nil, err
+			}
+			items[pk] = append(items[pk], orderItem)
+		}
+	}
+
+	for pk, i := range index {
+		orders[i].OrderItems = items[pk]
+	}
+	return orders, nil
+}
+
+func decodeOrder(record item) (models.Order, error) {
+	var order models.Order
+	var err error
+	var id, customerID, version uint64
+
+	if id, err = getNumber(record, "ID"); err != nil {
+		return order, err
+	}
+	if customerID, err = getNumber(record, "CustomerID"); err != nil {
+		return order, err
+	}
+	if version, err = getNumber(record, "Version"); err != nil {
+		return order, err
+	}
+	if order.TotalAmount, err = getFloat(record, "TotalAmount"); err != nil {
+		return order, err
+	}
+	if order.OrderDate, err = getTime(record, "OrderDate"); err != nil {
+		return order, err
+	}
+	if order.CreatedAt, err = getTime(record, "CreatedAt"); err != nil {
+		return order, err
+	}
+	if order.UpdatedAt, err = getTime(record, "UpdatedAt"); err != nil {
+		return order, err
+	}
+	if _, ok := record["AnonymizedAt"]; ok {
+		anonymizedAt, err := getTime(record, "AnonymizedAt")
+		if err != nil {
+			return order, err
+		}
+		order.AnonymizedAt = &anonymizedAt
+	}
+	if _, ok := record["DeletedAt"]; ok {
+		deletedAt, err := getTime(record, "DeletedAt")
+		if err != nil {
+			return order, err
+		}
+		order.DeletedAt = &deletedAt
+	}
+	if hold, ok := record["LegalHold"].(*types.AttributeValueMemberBOOL); ok {
+		order.LegalHold = hold.Value
+	}
+
+	order.ID = uint(id)
+	order.CustomerID = uint(customerID)
+	order.Version = uint(version)
+	order.OrderID = getString(record, "OrderID")
+	order.CustomerName = getString(record, "CustomerName")
+	order.Status = models.OrderStatus(getString(record, "Status"))
+	return order, nil
+}
+
+func decodeOrderItem(record item) (models.OrderItem, error) {
+	var orderItem models.OrderItem
+	var err error
+	var id, productID, quantity uint64
+
+	if id, err = getNumber(record, "ID"); err != nil {
+		return orderItem, err
+	}
+	if productID, err = getNumber(record, "ProductID"); err != nil {
+		return orderItem, err
+	}
+	if quantity, err = getNumber(record, "Quantity"); err != nil {
+		return orderItem, err
+	}
+	if orderItem.Price, err = getFloat(record, "Price"); err != nil {
+		return orderItem, err
+	}
+	if orderItem.OrderDate, err = getTime(record, "OrderDate"); err != nil {
+		return orderItem, err
+	}
+
+	orderItem.ID = uint(id)
+	orderItem.ProductID = uint(productID)
+	orderItem.Quantity = int(quantity)
+	orderItem.OrderID = getString(record, "OrderID")
+	orderItem.ProductName = getString(record, "ProductName")
+	return orderItem, nil
+}
+
+func str(value string) types.AttributeValue {
+	return &types.AttributeValueMemberS{Value: value}
+}
+
+func num(value uint64) types.AttributeValue {
+	return &types.AttributeValueMemberN{Value: strconv.FormatUint(value, 10)}
+}
+
+func float(value float64) types.AttributeValue {
+	return &types.AttributeValueMemberN{Value: strconv.FormatFloat(value, 'f', -1, 64)}
+}
+
+func timestamp(value time.Time) types.AttributeValue {
+	return &types.AttributeValueMemberS{Value: value.UTC().Format(time.RFC3339Nano)}
+}
+
+func getString(record item, name string) string {
+	if value, ok := record[name].(*types.AttributeValueMemberS); ok {
+		return value.Value
+	}
+	return ""
+}
+
+func getNumber(record item, name string) (uint64, error) {
+	value, ok := record[name].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, fmt.Errorf("attribute %s is not a number", name)
+	}
+	return strconv.ParseUint(value.Value, 10, 64)
+}
+
+func getFloat(record item, name string) (float64, error) {
+	value, ok := record[name].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, fmt.Errorf("attribute %s is not a number", name)
+	}
+	return strconv.ParseFloat(value.Value, 64)
+}
+
+func getTime(record item, name string) (time.Time, error) {
+	value, ok := record[name].(*types.AttributeValueMemberS)
+	if !ok {
+		return time.Time{}, fmt.Errorf("attribute %s is not a string", name)
+	}
+	return time.Parse(time.RFC3339Nano, value.Value)
+}
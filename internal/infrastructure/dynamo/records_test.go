@@ -0,0 +1,72 @@
+package dynamo
+
+import (
+	"order-service/internal/domain/models"
+	"order-service/internal/domain/repositories"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDecodeOrders tests that order and item records round-trip into orders
+func TestDecodeOrders(t *testing.T) {
+	orderDate := time.Date(2024, time.October, 27, 9, 30, 0, 0, time.UTC)
+	anonymizedAt := orderDate.Add(time.Hour)
+	deletedAt := orderDate.Add(2 * time.Hour)
+	order := models.Order{
+		ID:           7,
+		OrderID:      "ORD-7",
+		CustomerID:   42,
+		CustomerName: "Ada Lovelace",
+		TotalAmount:  30.5,
+		Status:       models.OrderStatusPending,
+		OrderDate:    orderDate,
+		LegalHold:    true,
+		AnonymizedAt: &anonymizedAt,
+		DeletedAt:    &deletedAt,
+		Version:      3,
+		CreatedAt:    orderDate,
+		UpdatedAt:    orderDate,
+	}
+	items := []models.OrderItem{
+		{ID: 2, OrderID: "ORD-7", OrderDate: orderDate, ProductID: 11, ProductName: "Widget", Quantity: 2, Price: 10},
+		{ID: 10, OrderID: "ORD-7", OrderDate: orderDate, ProductID: 12, ProductName: "Gadget", Quantity: 1, Price: 10.5},
+	}
+
+	records := []item{
+		orderItemRecord(order.ID, items[0]),
+		orderRecord(&order),
+		orderIDRecord(order.OrderID, order.ID),
+		orderItemRecord(order.ID, items[1]),
+	}
+
+	orders, err := decodeOrders(records)
+	assert.NoError(t, err)
+	assert.Len(t, orders, 1)
+
+	order.OrderItems = items
+	assert.Equal(t, order, orders[0])
+}
+
+// TestItemSKSortsNumerically tests that item sort keys order by ID
+func TestItemSKSortsNumerically(t *testing.T) {
+	assert.Less(t, itemSK(9), itemSK(10))
+}
+
+// TestSaveError tests that failed transaction conditions map to repository errors
+func TestSaveError(t *testing.T) {
+	cancelled := func(codes ...string) error {
+		reasons := make([]types.CancellationReason, len(codes))
+		for i, code := range codes {
+			reasons[i] = types.CancellationReason{Code: aws.String(code)}
+		}
+		return &types.TransactionCanceledException{CancellationReasons: reasons}
+	}
+
+	assert.ErrorIs(t, saveError(cancelled("None", "ConditionalCheckFailed"), true), repositories.ErrOrderIDTaken)
+	assert.ErrorIs(t, saveError(cancelled("ConditionalCheckFailed", "None"), false), repositories.ErrVersionConflict)
+	assert.NotErrorIs(t, saveError(cancelled("TransactionConflict"), false), repositories.ErrVersionConflict)
+}
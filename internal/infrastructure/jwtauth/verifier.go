@@ -0,0 +1,146 @@
+package jwtauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"order-service/internal/application/auth"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultRolesClaim is the claim that lists the caller's roles unless Config names another
+const DefaultRolesClaim = "roles"
+
+// Config describes which tokens are accepted. An empty Issuer or Audience is not checked.
+type Config struct {
+	Issuer     string
+	Audience   string
+	RolesClaim string
+	// Leeway tolerates clock skew when checking exp, nbf and iat
+	Leeway time.Duration
+}
+
+// Verifier checks bearer tokens and maps their claims to a principal
+type Verifier struct {
+	keys   KeySet
+	config Config
+	parser *jwt.Parser
+}
+
+func NewVerifier(keys KeySet, config Config) *Verifier {
+	if config.RolesClaim == "" {
+		config.RolesClaim = DefaultRolesClaim
+	}
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(validMethods(keys)),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(config.Leeway),
+	}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+	return &Verifier{keys: keys, config: config, parser: jwt.NewParser(options...)}
+}
+
+// Verify checks the token's signature and registered claims. The subject becomes the principal's
+// subject and the roles claim, a list or a space-separated string, its roles.
+func (v *Verifier) Verify(token string) (auth.Principal, error) {
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, v.key); err != nil {
+		return auth.Principal{}, err
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil {
+		return auth.Principal{}, err
+	}
+	if subject == "" {
+		return auth.Principal{}, errors.New("token has no subject")
+	}
+	roles, err := parseRoles(claims[v.config.RolesClaim])
+	if err != nil {
+		return auth.Principal{}, err
+	}
+	return auth.Principal{Subject: subject, Roles: roles}, nil
+}
+
+// key finds the key named by the token's kid; a single configured key also verifies tokens without one
+func (v *Verifier) key(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if key, ok := v.keys[kid]; ok {
+		return checkKeyType(token.Method, key)
+	}
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return checkKeyType(token.Method, key)
+		}
+	}
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+// checkKeyType stops a token from choosing an algorithm that does not belong to the key,
+// such as HS256 signed with a public RSA key
+func checkKeyType(method jwt.SigningMethod, key interface{}) (interface{}, error) {
+	for _, alg := range algorithms(key) {
+		if method.Alg() == alg {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("algorithm %s does not match the key", method.Alg())
+}
+
+func validMethods(keys KeySet) []string {
+	var methods []string
+	seen := map[string]bool{}
+	for _, key := range keys {
+		for _, alg := range algorithms(key) {
+			if !seen[alg] {
+				seen[alg] = true
+				methods = append(methods, alg)
+			}
+		}
+	}
+	return methods
+}
+
+func algorithms(key interface{}) []string {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		return []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}
+	case *ecdsa.PublicKey:
+		return []string{"ES256", "ES384", "ES512"}
+	case ed25519.PublicKey:
+		return []string{"EdDSA"}
+	case []byte:
+		return []string{"HS256", "HS384", "HS512"}
+	}
+	return nil
+}
+
+func parseRoles(claim interface{}) ([]string, error) {
+	switch value := claim.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return strings.Fields(value), nil
+	case []interface{}:
+		roles := make([]string, 0, len(value))
+		for _, role := range value {
+			name, ok := role.(string)
+			if !ok {
+				return nil, errors.New("roles claim must list strings")
+			}
+			roles = append(roles, name)
+		}
+		return roles, nil
+	}
+	return nil, errors.New("roles claim must be a list or a string")
+}
@@ -0,0 +1,110 @@
+package jwtauth
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"order-service/internal/application/auth"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// TestVerifyJWKS tests that tokens are verified with the JWKS key named by their kid
+func TestVerifyJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	jwks := fmt.Sprintf(`{"keys":[
+		{"kty":"RSA","kid":"rsa-1","use":"sig","n":%q,"e":%q},
+		{"kty":"EC","kid":"ec-1","crv":"P-256","x":%q,"y":%q},
+		{"kty":"RSA","kid":"enc-1","use":"enc","n":"AQAB","e":"AQAB"}
+	]}`, encode(rsaKey.N.Bytes()), encode([]byte{1, 0, 1}), encode(ecKey.X.Bytes()), encode(ecKey.Y.Bytes()))
+	keys, err := ParseJWKS([]byte(jwks))
+	require.NoError(t, err)
+	assert.Len(t, keys, 2)
+
+	verifier := NewVerifier(keys, Config{Issuer: "https://issuer.example", Audience: "orders"})
+	valid := jwt.MapClaims{
+		"sub":   "42",
+		"iss":   "https://issuer.example",
+		"aud":   "orders",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []string{"customer"},
+	}
+
+	principal, err := verifier.Verify(sign(t, jwt.SigningMethodRS256, rsaKey, "rsa-1", valid))
+	assert.NoError(t, err)
+	assert.Equal(t, auth.Principal{Subject: "42", Roles: []string{"customer"}}, principal)
+
+	principal, err = verifier.Verify(sign(t, jwt.SigningMethodES256, ecKey, "ec-1", valid))
+	assert.NoError(t, err)
+	assert.Equal(t, "42", principal.Subject)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"unknown kid", sign(t, jwt.SigningMethodRS256, rsaKey, "rsa-2", valid)},
+		{"wrong key for kid", sign(t, jwt.SigningMethodES256, ecKey, "rsa-1", valid)},
+		{"expired", sign(t, jwt.SigningMethodRS256, rsaKey, "rsa-1", jwt.MapClaims{"sub": "42", "iss": "https://issuer.example", "aud": "orders", "exp": time.Now().Add(-time.Hour).Unix()})},
+		{"no expiry", sign(t, jwt.SigningMethodRS256, rsaKey, "rsa-1", jwt.MapClaims{"sub": "42", "iss": "https://issuer.example", "aud": "orders"})},
+		{"wrong issuer", sign(t, jwt.SigningMethodRS256, rsaKey, "rsa-1", jwt.MapClaims{"sub": "42", "iss": "https://other.example", "aud": "orders", "exp": time.Now().Add(time.Hour).Unix()})},
+		{"wrong audience", sign(t, jwt.SigningMethodRS256, rsaKey, "rsa-1", jwt.MapClaims{"sub": "42", "iss": "https://issuer.example", "aud": "billing", "exp": time.Now().Add(time.Hour).Unix()})},
+		{"no subject", sign(t, jwt.SigningMethodRS256, rsaKey, "rsa-1", jwt.MapClaims{"iss": "https://issuer.example", "aud": "orders", "exp": time.Now().Add(time.Hour).Unix()})},
+		{"HMAC signed with the public key", sign(t, jwt.SigningMethodHS256, x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey), "rsa-1", valid)},
+		{"garbage", "not.a.token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier.Verify(tt.token)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestVerifyStaticKey tests that a single PEM key or secret verifies tokens without a kid
+func TestVerifyStaticKey(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+
+	key, err := ParsePublicKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(t, err)
+
+	claims := jwt.MapClaims{"sub": "alice", "exp": time.Now().Add(time.Hour).Unix(), "groups": "staff admin"}
+	principal, err := NewVerifier(KeySet{"": key}, Config{RolesClaim: "groups"}).Verify(sign(t, jwt.SigningMethodEdDSA, private, "", claims))
+	assert.NoError(t, err)
+	assert.Equal(t, auth.Principal{Subject: "alice", Roles: []string{"staff", "admin"}}, principal)
+
+	secret := []byte("a-shared-secret-of-enough-length")
+	principal, err = NewVerifier(KeySet{"": secret}, Config{}).Verify(sign(t, jwt.SigningMethodHS256, secret, "", jwt.MapClaims{"sub": "7", "exp": time.Now().Add(time.Hour).Unix()}))
+	assert.NoError(t, err)
+	assert.Equal(t, auth.Principal{Subject: "7"}, principal)
+}
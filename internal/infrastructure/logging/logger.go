@@ -1,6 +1,9 @@
 package logging
 
 import (
+	"context"
+	"order-service/internal/domain/requestid"
+
 	"github.com/phuslu/log"
 )
 
@@ -9,3 +12,41 @@ var Logger log.Logger
 func InitLogger() {
 	Logger = log.DefaultLogger
 }
+
+type callerKey struct{}
+
+// caller is who made the request being served, as attached to its log lines
+type caller struct {
+	principal string
+	apiKeyID  uint
+}
+
+// WithPrincipal returns a copy of ctx whose Ctx logger names the authenticated caller: its subject
+// and, for callers authenticated by an API key, the key's ID
+func WithPrincipal(ctx context.Context, subject string, apiKeyID uint) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller{principal: subject, apiKeyID: apiKeyID})
+}
+
+// Ctx returns Logger with the request ID and authenticated caller of ctx attached to every line,
+// or Logger itself outside of a request
+func Ctx(ctx context.Context) *log.Logger {
+	id := requestid.From(ctx)
+	who, authenticated := ctx.Value(callerKey{}).(caller)
+	if id == "" && !authenticated {
+		return &Logger
+	}
+
+	fields := log.NewContext(append(log.Context(nil), Logger.Context...))
+	if id != "" {
+		fields = fields.Str("request_id", id)
+	}
+	if authenticated {
+		fields = fields.Str("principal", who.principal)
+		if who.apiKeyID != 0 {
+			fields = fields.Uint64("api_key_id", uint64(who.apiKeyID))
+		}
+	}
+	logger := Logger
+	logger.Context = fields.Value()
+	return &logger
+}
@@ -0,0 +1,65 @@
+package persistence
+
+import (
+	"context"
+	"order-service/internal/application/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type GormAPIKeyStore struct {
+	db *gorm.DB
+}
+
+func NewGormAPIKeyStore(db *gorm.DB) repository.APIKeyStore {
+	return &GormAPIKeyStore{db: db}
+}
+
+func (s *GormAPIKeyStore) Create(ctx context.Context, key *repository.APIKey) error {
+	return s.db.WithContext(ctx).Create(key).Error
+}
+
+func (s *GormAPIKeyStore) FindByID(ctx context.Context, id uint) (*repository.APIKey, error) {
+	var key repository.APIKey
+	err := s.db.WithContext(ctx).First(&key, id).Error
+	return &key, apiKeyError(err)
+}
+
+func (s *GormAPIKeyStore) FindByPrefix(ctx context.Context, prefix string) (*repository.APIKey, error) {
+	var key repository.APIKey
+	err := s.db.WithContext(ctx).Where("prefix = ?", prefix).First(&key).Error
+	return &key, apiKeyError(err)
+}
+
+func (s *GormAPIKeyStore) List(ctx context.Context) ([]repository.APIKey, error) {
+	var keys []repository.APIKey
+	err := s.db.WithContext(ctx).Order("id").Find(&keys).Error
+	return keys, err
+}
+
+func (s *GormAPIKeyStore) Rotate(ctx context.Context, replacement *repository.APIKey, expiresAt time.Time) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Only shorten the old key's life, a rotation never extends it
+		result := tx.Model(&repository.APIKey{}).
+			Where("id = ? AND revoked_at IS NULL", *replacement.RotatedFromID).
+			Where("expires_at IS NULL OR expires_at > ?", expiresAt).
+			Update("expires_at", expiresAt)
+		if result.Error != nil {
+			return result.Error
+		}
+		return tx.Create(replacement).Error
+	})
+}
+
+func (s *GormAPIKeyStore) Revoke(ctx context.Context, id uint, at time.Time) error {
+	result := s.db.WithContext(ctx).Model(&repository.APIKey{}).Where("id = ?", id).
+		Update("revoked_at", gorm.Expr("COALESCE(revoked_at, ?)", at))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrAPIKeyNotFound
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package persistence
+
+import (
+	"context"
+	"order-service/internal/application/repository"
+	"sort"
+	"sync"
+	"time"
+)
+
+// InMemoryAPIKeyStore keeps API keys in process, for tests and local development
+type InMemoryAPIKeyStore struct {
+	mu     sync.Mutex
+	keys   map[uint]repository.APIKey
+	nextID uint
+}
+
+func NewInMemoryAPIKeyStore() *InMemoryAPIKeyStore {
+	return &InMemoryAPIKeyStore{keys: map[uint]repository.APIKey{}}
+}
+
+func (s *InMemoryAPIKeyStore) Create(_ context.Context, key *repository.APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.create(key)
+	return nil
+}
+
+func (s *InMemoryAPIKeyStore) create(key *repository.APIKey) {
+	s.nextID++
+	key.ID = s.nextID
+	if key.CreatedAt.IsZero() {
+		key.CreatedAt = time.Now()
+	}
+	s.keys[key.ID] = *key
+}
+
+func (s *InMemoryAPIKeyStore) FindByID(_ context.Context, id uint) (*repository.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, repository.ErrAPIKeyNotFound
+	}
+	return &key, nil
+}
+
+func (s *InMemoryAPIKeyStore) FindByPrefix(_ context.Context, prefix string) (*repository.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range s.keys {
+		if key.Prefix == prefix {
+			return &key, nil
+		}
+	}
+	return nil, repository.ErrAPIKeyNotFound
+}
+
+func (s *InMemoryAPIKeyStore) List(_ context.Context) ([]repository.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]repository.APIKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys, nil
+}
+
+func (s *InMemoryAPIKeyStore) Rotate(_ context.Context, replacement *repository.APIKey, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if old, ok := s.keys[*replacement.RotatedFromID]; ok && old.RevokedAt == nil && (old.ExpiresAt == nil || old.ExpiresAt.After(expiresAt)) {
+		old.ExpiresAt = &expiresAt
+		s.keys[old.ID] = old
+	}
+	s.create(replacement)
+	return nil
+}
+
+func (s *InMemoryAPIKeyStore) Revoke(_ context.Context, id uint, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[id]
+	if !ok {
+		return repository.ErrAPIKeyNotFound
+	}
+	if key.RevokedAt == nil {
+		key.RevokedAt = &at
+		s.keys[id] = key
+	}
+	return nil
+}
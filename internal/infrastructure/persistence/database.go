@@ -0,0 +1,152 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"order-service/internal/infrastructure/logging"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
+)
+
+// DatabaseConfig describes the primary connection and any read replicas
+type DatabaseConfig struct {
+	PrimaryDSN  string
+	ReplicaDSNs []string
+	Logger      logger.Interface
+	Pool        PoolConfig
+}
+
+// PoolConfig sizes the connection pool of the primary and of each replica.
+// Bounded lifetimes make the pool replace connections broken by a Postgres restart.
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// RetryConfig bounds how long ConnectWithRetry keeps trying and how it backs off
+type RetryConfig struct {
+	Timeout        time.Duration
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultPoolConfig is used for any PoolConfig field left at zero
+var DefaultPoolConfig = PoolConfig{
+	MaxOpenConns:    25,
+	MaxIdleConns:    10,
+	ConnMaxLifetime: 30 * time.Minute,
+	ConnMaxIdleTime: 5 * time.Minute,
+}
+
+// OpenDatabase connects to the primary and routes queries to the replicas when any are configured.
+// Writes and raw statements other than SELECT always go to the primary. The primary must answer
+// a ping before ctx is done.
+func OpenDatabase(ctx context.Context, config DatabaseConfig) (*gorm.DB, error) {
+	pool := config.Pool.withDefaults()
+
+	// TranslateError turns driver errors such as unique violations into gorm.ErrDuplicatedKey.
+	// The ping gorm makes on open has no deadline, so ping with ctx below instead.
+	db, err := gorm.Open(postgres.Open(config.PrimaryDSN), &gorm.Config{
+		Logger:               config.Logger,
+		TranslateError:       true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		_ = sqlDB.Close()
+		return nil, err
+	}
+	if err := db.Use(NewTracingPlugin()); err != nil {
+		return nil, fmt.Errorf("failed to register query tracing: %w", err)
+	}
+
+	sqlDB.SetMaxOpenConns(pool.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(pool.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+
+	if len(config.ReplicaDSNs) == 0 {
+		return db, nil
+	}
+
+	replicas := make([]gorm.Dialector, len(config.ReplicaDSNs))
+	for i, dsn := range config.ReplicaDSNs {
+		replicas[i] = postgres.Open(dsn)
+	}
+
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	}).
+		SetMaxOpenConns(pool.MaxOpenConns).
+		SetMaxIdleConns(pool.MaxIdleConns).
+		SetConnMaxLifetime(pool.ConnMaxLifetime).
+		SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+	if err := db.Use(resolver); err != nil {
+		return nil, fmt.Errorf("failed to register read replicas: %w", err)
+	}
+	return db, nil
+}
+
+// ConnectWithRetry opens the database, retrying with exponential backoff and jitter
+// until it succeeds or retry.Timeout has passed. An attempt still running at the timeout is abandoned.
+func ConnectWithRetry(ctx context.Context, config DatabaseConfig, retry RetryConfig) (*gorm.DB, error) {
+	return connectWithRetry(ctx, func(ctx context.Context) (*gorm.DB, error) { return OpenDatabase(ctx, config) }, retry, time.After)
+}
+
+func connectWithRetry(ctx context.Context, open func(ctx context.Context) (*gorm.DB, error), retry RetryConfig, after func(time.Duration) <-chan time.Time) (*gorm.DB, error) {
+	ctx, cancel := context.WithTimeout(ctx, retry.Timeout)
+	defer cancel()
+
+	backoff := retry.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		db, err := open(ctx)
+		if err == nil {
+			return db, nil
+		}
+
+		// Sleep between half and all of the backoff so restarting instances spread out
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		logging.Logger.Warn().Msgf("database connection attempt %d failed, retrying in %s: %v", attempt, wait, err)
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("could not connect to the database after %d attempts: %w", attempt, err)
+		case <-after(wait):
+		}
+
+		backoff *= 2
+		if backoff > retry.MaxBackoff {
+			backoff = retry.MaxBackoff
+		}
+	}
+}
+
+func (p PoolConfig) withDefaults() PoolConfig {
+	if p.MaxOpenConns <= 0 {
+		p.MaxOpenConns = DefaultPoolConfig.MaxOpenConns
+	}
+	if p.MaxIdleConns <= 0 {
+		p.MaxIdleConns = DefaultPoolConfig.MaxIdleConns
+	}
+	if p.ConnMaxLifetime <= 0 {
+		p.ConnMaxLifetime = DefaultPoolConfig.ConnMaxLifetime
+	}
+	if p.ConnMaxIdleTime <= 0 {
+		p.ConnMaxIdleTime = DefaultPoolConfig.ConnMaxIdleTime
+	}
+	return p
+}
@@ -0,0 +1,85 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// TestConnectWithRetrySucceeds tests that failed attempts are retried with growing backoff
+func TestConnectWithRetrySucceeds(t *testing.T) {
+	attempts := 0
+	open := func(context.Context) (*gorm.DB, error) {
+		attempts++
+		if attempts < 4 {
+			return nil, errors.New("connection refused")
+		}
+		return &gorm.DB{}, nil
+	}
+
+	var waits []time.Duration
+	after := func(d time.Duration) <-chan time.Time {
+		waits = append(waits, d)
+		ch := make(chan time.Time, 1)
+		ch <- time.Now()
+		return ch
+	}
+
+	db, err := connectWithRetry(context.Background(), open, RetryConfig{Timeout: time.Minute, InitialBackoff: 100 * time.Millisecond, MaxBackoff: 250 * time.Millisecond}, after)
+	assert.NoError(t, err)
+	assert.NotNil(t, db)
+	assert.Equal(t, 4, attempts)
+	assert.Len(t, waits, 3)
+	assert.LessOrEqual(t, waits[0], 100*time.Millisecond)
+	assert.GreaterOrEqual(t, waits[1], 100*time.Millisecond)
+	assert.LessOrEqual(t, waits[2], 250*time.Millisecond)
+}
+
+// TestConnectWithRetryGivesUp tests that connecting fails once the timeout has passed
+func TestConnectWithRetryGivesUp(t *testing.T) {
+	open := func(context.Context) (*gorm.DB, error) {
+		return nil, errors.New("connection refused")
+	}
+
+	_, err := connectWithRetry(context.Background(), open, RetryConfig{Timeout: 20 * time.Millisecond, InitialBackoff: 5 * time.Millisecond, MaxBackoff: 10 * time.Millisecond}, time.After)
+	assert.ErrorContains(t, err, "connection refused")
+}
+
+// TestConnectWithRetryBoundsAttempts tests that an attempt stuck on an unresponsive server is
+// abandoned at the timeout
+func TestConnectWithRetryBoundsAttempts(t *testing.T) {
+	// Accept connections but never answer, like a server that is starting or a dropped route
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	config := DatabaseConfig{PrimaryDSN: fmt.Sprintf("postgres://orders@%s/orders?sslmode=disable", listener.Addr())}
+	start := time.Now()
+	_, err = ConnectWithRetry(context.Background(), config, RetryConfig{Timeout: 200 * time.Millisecond, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 10 * time.Millisecond})
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+// TestPoolConfigDefaults tests that unset pool settings fall back to the defaults
+func TestPoolConfigDefaults(t *testing.T) {
+	pool := PoolConfig{MaxOpenConns: 50}.withDefaults()
+	assert.Equal(t, 50, pool.MaxOpenConns)
+	assert.Equal(t, DefaultPoolConfig.MaxIdleConns, pool.MaxIdleConns)
+	assert.Equal(t, DefaultPoolConfig.ConnMaxLifetime, pool.ConnMaxLifetime)
+}
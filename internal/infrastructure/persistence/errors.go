@@ -0,0 +1,29 @@
+package persistence
+
+import (
+	"errors"
+	"order-service/internal/application/repository"
+	"order-service/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+// OrderError translates GORM errors from order queries into repository errors so callers
+// never see driver details. The original error stays available through errors.Unwrap.
+func OrderError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return repositories.ErrOrderNotFound.Wrap(err)
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return repositories.ErrOrderIDTaken.Wrap(err)
+	}
+	return err
+}
+
+// apiKeyError translates a missing API key into the store's not found error
+func apiKeyError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return repository.ErrAPIKeyNotFound.Wrap(err)
+	}
+	return err
+}
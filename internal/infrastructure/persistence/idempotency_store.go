@@ -0,0 +1,59 @@
+package persistence
+
+import (
+	"context"
+	"order-service/internal/application/repository"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GormIdempotencyStore struct {
+	db *gorm.DB
+}
+
+func NewGormIdempotencyStore(db *gorm.DB) repository.IdempotencyStore {
+	return &GormIdempotencyStore{db: db}
+}
+
+func (s *GormIdempotencyStore) Reserve(ctx context.Context, record repository.IdempotencyRecord) (*repository.IdempotencyRecord, error) {
+	var existing *repository.IdempotencyRecord
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("key = ? AND expires_at <= ?", record.Key, time.Now()).Delete(&repository.IdempotencyRecord{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+		if result.Error != nil || result.RowsAffected == 1 {
+			return result.Error
+		}
+
+		existing = &repository.IdempotencyRecord{}
+		return tx.Where("key = ?", record.Key).First(existing).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+func (s *GormIdempotencyStore) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	return s.db.WithContext(ctx).Model(&repository.IdempotencyRecord{}).
+		Where("key = ?", key).
+		Updates(map[string]interface{}{
+			"completed":    true,
+			"status_code":  statusCode,
+			"content_type": contentType,
+			"body":         body,
+		}).Error
+}
+
+func (s *GormIdempotencyStore) Release(ctx context.Context, key string) error {
+	return s.db.WithContext(ctx).Where("key = ? AND completed = ?", key, false).Delete(&repository.IdempotencyRecord{}).Error
+}
+
+func (s *GormIdempotencyStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&repository.IdempotencyRecord{})
+	return result.RowsAffected, result.Error
+}
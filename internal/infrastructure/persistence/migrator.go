@@ -0,0 +1,260 @@
+package persistence
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/plugin/dbresolver"
+)
+
+// ErrSchemaBehind is returned by EnsureCurrent when migrations are pending
+var ErrSchemaBehind = errors.New("database schema is behind the embedded migrations")
+
+// ErrSchemaDirty is returned when a previous migration failed part way through
+var ErrSchemaDirty = errors.New("database schema is dirty, fix it manually and force a version")
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration is a single versioned schema change
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus describes where the database stands relative to the embedded migrations
+type MigrationStatus struct {
+	Version uint64
+	Dirty   bool
+	Latest  uint64
+	Pending []Migration
+}
+
+// schemaMigration is the single-row version table, compatible with golang-migrate
+type schemaMigration struct {
+	Version int64 `gorm:"primaryKey;autoIncrement:false"`
+	Dirty   bool  `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator applies the versioned migrations found in a filesystem
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator loads and validates the migrations in source
+func NewMigrator(db *gorm.DB, source fs.FS) (*Migrator, error) {
+	migrations, err := LoadMigrations(source)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// LoadMigrations reads <version>_<name>.up.sql / .down.sql pairs ordered by version
+func LoadMigrations(source fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := map[uint64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %q: %w", entry.Name(), err)
+		}
+		body, err := fs.ReadFile(source, path.Join(".", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %q: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(body)
+		} else {
+			migration.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Status reports the current version, dirty flag and pending migrations
+func (m *Migrator) Status() (MigrationStatus, error) {
+	current, err := m.current()
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+
+	status := MigrationStatus{Version: uint64(current.Version), Dirty: current.Dirty}
+	for _, migration := range m.migrations {
+		status.Latest = migration.Version
+		if migration.Version > status.Version {
+			status.Pending = append(status.Pending, migration)
+		}
+	}
+	return status, nil
+}
+
+// EnsureCurrent returns an error unless every embedded migration has been applied
+func (m *Migrator) EnsureCurrent() error {
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+	if status.Dirty {
+		return fmt.Errorf("%w (version %d)", ErrSchemaDirty, status.Version)
+	}
+	if len(status.Pending) > 0 {
+		return fmt.Errorf("%w: at version %d, latest is %d", ErrSchemaBehind, status.Version, status.Latest)
+	}
+	return nil
+}
+
+// Up applies all pending migrations and returns how many were applied
+func (m *Migrator) Up() (int, error) {
+	status, err := m.Status()
+	if err != nil {
+		return 0, err
+	}
+	if status.Dirty {
+		return 0, fmt.Errorf("%w (version %d)", ErrSchemaDirty, status.Version)
+	}
+
+	for i, migration := range status.Pending {
+		if err := m.apply(migration.Version, migration.Up, migration.Version); err != nil {
+			return i, fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+	}
+	return len(status.Pending), nil
+}
+
+// Down rolls back up to steps applied migrations and returns how many were rolled back
+func (m *Migrator) Down(steps int) (int, error) {
+	status, err := m.Status()
+	if err != nil {
+		return 0, err
+	}
+	if status.Dirty {
+		return 0, fmt.Errorf("%w (version %d)", ErrSchemaDirty, status.Version)
+	}
+
+	applied := []Migration{}
+	for _, migration := range m.migrations {
+		if migration.Version <= status.Version {
+			applied = append(applied, migration)
+		}
+	}
+
+	rolledBack := 0
+	for i := len(applied) - 1; i >= 0 && rolledBack < steps; i-- {
+		migration := applied[i]
+		if migration.Down == "" {
+			return rolledBack, fmt.Errorf("migration %d_%s has no down script", migration.Version, migration.Name)
+		}
+		var previous uint64
+		if i > 0 {
+			previous = applied[i-1].Version
+		}
+		if err := m.apply(migration.Version, migration.Down, previous); err != nil {
+			return rolledBack, fmt.Errorf("rollback of %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		rolledBack++
+	}
+	return rolledBack, nil
+}
+
+// Force sets the recorded version and clears the dirty flag without running any SQL
+func (m *Migrator) Force(version uint64) error {
+	if version != 0 && !m.known(version) {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+	return m.setVersion(m.db, version, false)
+}
+
+// apply marks the schema dirty at version, runs script in a transaction and records target
+func (m *Migrator) apply(version uint64, script string, target uint64) error {
+	if err := m.setVersion(m.db, version, true); err != nil {
+		return err
+	}
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(script).Error; err != nil {
+			return err
+		}
+		return m.setVersion(tx, target, false)
+	})
+}
+
+func (m *Migrator) current() (schemaMigration, error) {
+	if err := m.db.AutoMigrate(&schemaMigration{}); err != nil {
+		return schemaMigration{}, fmt.Errorf("failed to prepare schema_migrations: %w", err)
+	}
+
+	// Always read the version from the primary, replicas may lag behind
+	var rows []schemaMigration
+	if err := m.db.Clauses(dbresolver.Write).Limit(1).Find(&rows).Error; err != nil {
+		return schemaMigration{}, err
+	}
+	if len(rows) == 0 {
+		return schemaMigration{}, nil
+	}
+	return rows[0], nil
+}
+
+func (m *Migrator) setVersion(db *gorm.DB, version uint64, dirty bool) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&schemaMigration{}).Error; err != nil {
+			return err
+		}
+		if version == 0 && !dirty {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&schemaMigration{Version: int64(version), Dirty: dirty}).Error
+	})
+}
+
+func (m *Migrator) known(version uint64) bool {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package persistence
+
+import (
+	"order-service/sql/migrations"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadMigrations tests that migrations are paired and ordered by version
+func TestLoadMigrations(t *testing.T) {
+	source := fstest.MapFS{
+		"2_add_items.up.sql":       {Data: []byte("CREATE TABLE items ();")},
+		"2_add_items.down.sql":     {Data: []byte("DROP TABLE items;")},
+		"1_create_orders.up.sql":   {Data: []byte("CREATE TABLE orders ();")},
+		"1_create_orders.down.sql": {Data: []byte("DROP TABLE orders;")},
+		"README.md":                {Data: []byte("ignored")},
+	}
+
+	loaded, err := LoadMigrations(source)
+	assert.NoError(t, err)
+	assert.Len(t, loaded, 2)
+	assert.Equal(t, uint64(1), loaded[0].Version)
+	assert.Equal(t, "create_orders", loaded[0].Name)
+	assert.Equal(t, "DROP TABLE orders;", loaded[0].Down)
+	assert.Equal(t, uint64(2), loaded[1].Version)
+}
+
+// TestLoadMigrationsRequiresUp tests that a down script without an up script is rejected
+func TestLoadMigrationsRequiresUp(t *testing.T) {
+	source := fstest.MapFS{
+		"1_create_orders.down.sql": {Data: []byte("DROP TABLE orders;")},
+	}
+
+	_, err := LoadMigrations(source)
+	assert.Error(t, err)
+}
+
+// TestEmbeddedMigrations tests that every embedded migration can be rolled back
+func TestEmbeddedMigrations(t *testing.T) {
+	loaded, err := LoadMigrations(migrations.FS)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, loaded)
+	for _, migration := range loaded {
+		assert.NotEmpty(t, migration.Down, "migration %d has no down script", migration.Version)
+	}
+}
+
+// testMigrations creates two tables, one per migration
+var testMigrations = fstest.MapFS{
+	"1_create_orders.up.sql":   {Data: []byte("CREATE TABLE orders (id INTEGER PRIMARY KEY);")},
+	"1_create_orders.down.sql": {Data: []byte("DROP TABLE orders;")},
+	"2_create_items.up.sql":    {Data: []byte("CREATE TABLE items (id INTEGER PRIMARY KEY);")},
+	"2_create_items.down.sql":  {Data: []byte("DROP TABLE items;")},
+}
+
+// TestMigratorUpDown tests that Up applies pending migrations in order and Down rolls them back
+func TestMigratorUpDown(t *testing.T) {
+	db := openTestDB(t)
+	migrator, err := NewMigrator(db, testMigrations)
+	require.NoError(t, err)
+
+	status, err := migrator.Status()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), status.Version)
+	assert.Equal(t, uint64(2), status.Latest)
+	assert.Len(t, status.Pending, 2)
+	assert.ErrorIs(t, migrator.EnsureCurrent(), ErrSchemaBehind)
+
+	applied, err := migrator.Up()
+	require.NoError(t, err)
+	assert.Equal(t, 2, applied)
+	assert.True(t, db.Migrator().HasTable("orders"))
+	assert.True(t, db.Migrator().HasTable("items"))
+	status, err = migrator.Status()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), status.Version)
+	assert.Empty(t, status.Pending)
+	assert.NoError(t, migrator.EnsureCurrent())
+
+	applied, err = migrator.Up()
+	require.NoError(t, err)
+	assert.Equal(t, 0, applied, "nothing is pending")
+
+	rolledBack, err := migrator.Down(1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rolledBack)
+	assert.False(t, db.Migrator().HasTable("items"))
+	assert.True(t, db.Migrator().HasTable("orders"))
+	status, err = migrator.Status()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), status.Version)
+
+	rolledBack, err = migrator.Down(5)
+	require.NoError(t, err)
+	assert.Equal(t, 1, rolledBack, "only applied migrations are rolled back")
+	assert.False(t, db.Migrator().HasTable("orders"))
+	status, err = migrator.Status()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), status.Version)
+}
+
+// TestMigratorForce tests that a failed migration leaves the schema dirty until a version is forced
+func TestMigratorForce(t *testing.T) {
+	db := openTestDB(t)
+	source := fstest.MapFS{
+		"1_create_orders.up.sql":   testMigrations["1_create_orders.up.sql"],
+		"1_create_orders.down.sql": testMigrations["1_create_orders.down.sql"],
+		"2_broken.up.sql":          {Data: []byte("CREATE TABLE broken (")},
+		"2_broken.down.sql":        {Data: []byte("DROP TABLE broken;")},
+	}
+	migrator, err := NewMigrator(db, source)
+	require.NoError(t, err)
+
+	applied, err := migrator.Up()
+	assert.Error(t, err)
+	assert.Equal(t, 1, applied)
+	status, err := migrator.Status()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), status.Version)
+	assert.True(t, status.Dirty)
+
+	_, err = migrator.Up()
+	assert.ErrorIs(t, err, ErrSchemaDirty)
+	_, err = migrator.Down(1)
+	assert.ErrorIs(t, err, ErrSchemaDirty)
+	assert.ErrorIs(t, migrator.EnsureCurrent(), ErrSchemaDirty)
+
+	assert.Error(t, migrator.Force(99), "unknown versions cannot be forced")
+	require.NoError(t, migrator.Force(1))
+	status, err = migrator.Status()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), status.Version)
+	assert.False(t, status.Dirty)
+	require.Len(t, status.Pending, 1)
+	assert.Equal(t, uint64(2), status.Pending[0].Version)
+
+	require.NoError(t, migrator.Force(0))
+	status, err = migrator.Status()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), status.Version)
+	assert.Len(t, status.Pending, 2)
+}
@@ -0,0 +1,41 @@
+package persistence
+
+import (
+	"context"
+	"order-service/internal/domain/models"
+	"order-service/internal/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+type GormOrderBulkRepository struct {
+	db *gorm.DB
+}
+
+func NewGormOrderBulkRepository(db *gorm.DB) repositories.OrderBulkRepository {
+	return &GormOrderBulkRepository{db: db}
+}
+
+// CreateBatch inserts the orders with one statement and their items with another
+func (r *GormOrderBulkRepository) CreateBatch(ctx context.Context, orders []models.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	for i := range orders {
+		orders[i].Version = 1
+		for j := range orders[i].OrderItems {
+			orders[i].OrderItems[j].OrderDate = orders[i].OrderDate
+		}
+	}
+	err := Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		return tx.Create(&orders).Error
+	})
+	return OrderError(err)
+}
+
+func (r *GormOrderBulkRepository) FindInBatches(ctx context.Context, batchSize int, fn func(orders []models.Order) error) error {
+	var orders []models.Order
+	return ReadSession(ctx, r.db).Preload("OrderItems").Where("deleted_at IS NULL").Order("id").FindInBatches(&orders, batchSize, func(_ *gorm.DB, _ int) error {
+		return fn(orders)
+	}).Error
+}
@@ -0,0 +1,121 @@
+package persistence
+
+import (
+	"context"
+	"order-service/internal/application/repository"
+	"regexp"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// orderPartitionPattern matches the monthly orders partitions created by create_order_partitions
+var orderPartitionPattern = regexp.MustCompile(`^orders_p(\d{6})$`)
+
+// GormOrderPartitionStore creates and archives monthly partitions through the SQL functions
+// installed by the partitioning migration
+type GormOrderPartitionStore struct {
+	db                *gorm.DB
+	archiveSchema     string
+	archiveTablespace string
+}
+
+// NewGormOrderPartitionStore archives partitions into archiveSchema and, when it is not empty,
+// moves them to archiveTablespace
+func NewGormOrderPartitionStore(db *gorm.DB, archiveSchema, archiveTablespace string) repository.OrderPartitionStore {
+	return &GormOrderPartitionStore{db: db, archiveSchema: archiveSchema, archiveTablespace: archiveTablespace}
+}
+
+func (s *GormOrderPartitionStore) EnsurePartitions(ctx context.Context, from time.Time, months int) ([]time.Time, error) {
+	existing, err := s.partitionMonths(ctx)
+	if err != nil {
+		return nil, err
+	}
+	exists := make(map[time.Time]bool, len(existing))
+	for _, month := range existing {
+		exists[month] = true
+	}
+
+	var created []time.Time
+	for i := 0; i < months; i++ {
+		month := monthStart(from).AddDate(0, i, 0)
+		if exists[month] {
+			continue
+		}
+		if err := s.db.WithContext(ctx).Exec("SELECT create_order_partitions(?::DATE)", month.Format(time.DateOnly)).Error; err != nil {
+			return created, err
+		}
+		created = append(created, month)
+	}
+	return created, nil
+}
+
+func (s *GormOrderPartitionStore) ArchivePartitions(ctx context.Context, before time.Time) ([]time.Time, error) {
+	existing, err := s.partitionMonths(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var archived []time.Time
+	for _, month := range monthsBefore(existing, before) {
+		err := s.db.WithContext(ctx).Exec("SELECT archive_order_partitions(?::DATE, ?, ?)",
+			month.Format(time.DateOnly), s.archiveSchema, s.archiveTablespace).Error
+		if err != nil {
+			return archived, err
+		}
+		archived = append(archived, month)
+	}
+	return archived, nil
+}
+
+// partitionMonths lists the months that currently have an attached orders partition
+func (s *GormOrderPartitionStore) partitionMonths(ctx context.Context) ([]time.Time, error) {
+	var names []string
+	err := s.db.WithContext(ctx).Raw(`SELECT c.relname FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'orders'::regclass`).Scan(&names).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var months []time.Time
+	for _, name := range names {
+		if month, ok := partitionMonth(name); ok {
+			months = append(months, month)
+		}
+	}
+	sort.Slice(months, func(i, j int) bool { return months[i].Before(months[j]) })
+	return months, nil
+}
+
+// partitionMonth parses the month out of a partition name such as orders_p202410
+func partitionMonth(name string) (time.Time, bool) {
+	match := orderPartitionPattern.FindStringSubmatch(name)
+	if match == nil {
+		return time.Time{}, false
+	}
+	month, err := time.Parse("200601", match[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return month, true
+}
+
+// monthsBefore returns the months that end on or before the start of the month of before
+func monthsBefore(months []time.Time, before time.Time) []time.Time {
+	cutoff := monthStart(before)
+	var old []time.Time
+	for _, month := range months {
+		if month.Before(cutoff) {
+			old = append(old, month)
+		}
+	}
+	return old
+}
+
+// monthStart truncates t to the first instant of its UTC month, matching the partition bounds
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
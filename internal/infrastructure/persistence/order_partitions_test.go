@@ -0,0 +1,38 @@
+package persistence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPartitionMonth tests parsing months out of partition names
+func TestPartitionMonth(t *testing.T) {
+	month, ok := partitionMonth("orders_p202410")
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, time.October, 1, 0, 0, 0, 0, time.UTC), month)
+
+	_, ok = partitionMonth("orders_default")
+	assert.False(t, ok)
+	_, ok = partitionMonth("order_items_p202410")
+	assert.False(t, ok)
+}
+
+// TestMonthsBefore tests that only months ending before the cutoff month are archived
+func TestMonthsBefore(t *testing.T) {
+	months := []time.Time{
+		time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, time.September, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, time.October, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	old := monthsBefore(months, time.Date(2024, time.October, 15, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, months[:2], old)
+}
+
+// TestMonthStart tests truncation to the UTC month used by the partition bounds
+func TestMonthStart(t *testing.T) {
+	local := time.Date(2024, time.November, 1, 1, 0, 0, 0, time.FixedZone("CET", 2*60*60))
+	assert.Equal(t, time.Date(2024, time.October, 1, 0, 0, 0, 0, time.UTC), monthStart(local))
+}
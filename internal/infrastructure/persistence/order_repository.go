@@ -1,10 +1,13 @@
 package persistence
 
 import (
+	"context"
 	"order-service/internal/domain/models"
 	"order-service/internal/domain/repositories"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
 )
 
 type GormOrderRepository struct {
@@ -15,19 +18,96 @@ func NewGormOrderRepository(db *gorm.DB) repositories.OrderRepository {
 	return &GormOrderRepository{db: db}
 }
 
-func (r *GormOrderRepository) Save(order models.Order) error {
-	return r.db.Create(&order).Error
+// orderKey maps an order's id and order_id to the month partition holding it
+type orderKey struct {
+	OrderID   string `gorm:"primaryKey"`
+	ID        uint
+	OrderDate time.Time
 }
 
-func (r *GormOrderRepository) FindByID(id uint) (*models.Order, error) {
+func (orderKey) TableName() string {
+	return "order_keys"
+}
+
+// Save inserts a new order or updates an existing one, assigning IDs to new orders and items.
+// Items are stamped with the order's date so they are stored in the same month partition.
+// Updates only apply to the version that was loaded and fail with ErrVersionConflict otherwise.
+// The legal hold and anonymization are left alone, they are only written by the retention repository.
+func (r *GormOrderRepository) Save(ctx context.Context, order *models.Order) error {
+	for i := range order.OrderItems {
+		order.OrderItems[i].OrderDate = order.OrderDate
+	}
+	if order.ID == 0 {
+		order.Version = 1
+		return OrderError(Conn(ctx, r.db).Save(order).Error)
+	}
+
+	expected := order.Version
+	order.Version++
+	err := Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Selecting every column stops Save from falling back to an upsert when no row matches
+		result := tx.Select("*").Omit("legal_hold", "anonymized_at").Where("version = ?", expected).Save(order)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrVersionConflict
+		}
+		return deleteRemovedItems(tx, order)
+	})
+	if err != nil {
+		order.Version = expected
+	}
+	return OrderError(err)
+}
+
+// deleteRemovedItems deletes the stored items an update dropped from the order
+func deleteRemovedItems(tx *gorm.DB, order *models.Order) error {
+	query := tx.Where("order_id = ? AND order_date = ?", order.OrderID, order.OrderDate)
+	if len(order.OrderItems) > 0 {
+		kept := make([]uint, len(order.OrderItems))
+		for i, item := range order.OrderItems {
+			kept[i] = item.ID
+		}
+		query = query.Where("id NOT IN ?", kept)
+	}
+	return query.Delete(&models.OrderItem{}).Error
+}
+
+// FindByID resolves the order's partition through order_keys so only that month is scanned.
+// Deleted orders are not found.
+func (r *GormOrderRepository) FindByID(ctx context.Context, id uint) (*models.Order, error) {
 	var order models.Order
+	db := ReadSession(ctx, r.db)
+
+	var key orderKey
+	if err := db.Where("id = ?", id).Take(&key).Error; err != nil {
+		return &order, OrderError(err)
+	}
+
 	// Use Preload to fetch OrderItems along with the Order
-	err := r.db.Preload("OrderItems").First(&order, id).Error
-	return &order, err
+	err := db.Preload("OrderItems", "order_date = ?", key.OrderDate).
+		Where("order_date = ? AND deleted_at IS NULL", key.OrderDate).
+		First(&order, id).Error
+	return &order, OrderError(err)
 }
 
-func (r *GormOrderRepository) FindAll() ([]models.Order, error) {
+func (r *GormOrderRepository) FindAll(ctx context.Context) ([]models.Order, error) {
 	var orders []models.Order
-	err := r.db.Preload("OrderItems").Find(&orders).Error
+	err := ReadSession(ctx, r.db).Preload("OrderItems").Where("deleted_at IS NULL").Find(&orders).Error
 	return orders, err
 }
+
+// ReadSession returns a session for queries, pinned to the primary when the caller needs its own writes.
+// Inside a transaction it reads through the transaction.
+func ReadSession(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(transactionKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	db = db.WithContext(ctx)
+	if repositories.ReadYourWrites(ctx) {
+		// Start a new session so the pinned db can be reused for several queries
+		return db.Clauses(dbresolver.Write).Session(&gorm.Session{})
+	}
+	return db
+}
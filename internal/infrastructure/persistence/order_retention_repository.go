@@ -0,0 +1,126 @@
+package persistence
+
+import (
+	"context"
+	"order-service/internal/domain/models"
+	"order-service/internal/domain/repositories"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/plugin/dbresolver"
+)
+
+type GormOrderRetentionRepository struct {
+	db *gorm.DB
+}
+
+func NewGormOrderRetentionRepository(db *gorm.DB) repositories.OrderRetentionRepository {
+	return &GormOrderRetentionRepository{db: db}
+}
+
+func (r *GormOrderRetentionRepository) FindExpired(ctx context.Context, cutoff time.Time, afterID uint, limit int) ([]models.Order, error) {
+	var orders []models.Order
+	// Read from the primary so a batch never misses rows a replica has not caught up on
+	err := r.db.WithContext(ctx).Clauses(dbresolver.Write).
+		Preload("OrderItems").
+		Where("order_date < ? AND id > ? AND legal_hold = ? AND anonymized_at IS NULL", cutoff, afterID, false).
+		Order("id").
+		Limit(limit).
+		Find(&orders).Error
+	return orders, err
+}
+
+func (r *GormOrderRetentionRepository) DeleteOrders(ctx context.Context, orders []models.Order) ([]models.OrderPurgeAudit, error) {
+	return r.purge(ctx, orders, models.RetentionDelete, func(tx *gorm.DB, ids []uint, orderIDs []string) error {
+		if err := tx.Where("order_id IN ?", orderIDs).Delete(&models.OrderItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", ids).Delete(&models.Order{}).Error
+	})
+}
+
+func (r *GormOrderRetentionRepository) AnonymizeOrders(ctx context.Context, orders []models.Order) ([]models.OrderPurgeAudit, error) {
+	now := time.Now()
+	return r.purge(ctx, orders, models.RetentionAnonymize, func(tx *gorm.DB, ids []uint, _ []string) error {
+		return tx.Model(&models.Order{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"customer_id":   0,
+				"customer_name": "",
+				"anonymized_at": now,
+				"version":       gorm.Expr("version + 1"),
+				"updated_at":    now,
+			}).Error
+	})
+}
+
+// purge locks the orders, drops any put on legal hold since they were selected,
+// applies action to the rest and records an audit entry for each in one transaction
+func (r *GormOrderRetentionRepository) purge(ctx context.Context, orders []models.Order, action models.RetentionAction, apply func(tx *gorm.DB, ids []uint, orderIDs []string) error) ([]models.OrderPurgeAudit, error) {
+	if len(orders) == 0 {
+		return nil, nil
+	}
+
+	candidates := make([]uint, len(orders))
+	for i, order := range orders {
+		candidates[i] = order.ID
+	}
+
+	var audits []models.OrderPurgeAudit
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var purgeable []uint
+		err := tx.Model(&models.Order{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND legal_hold = ?", candidates, false).
+			Pluck("id", &purgeable).Error
+		if err != nil || len(purgeable) == 0 {
+			return err
+		}
+
+		allowed := make(map[uint]bool, len(purgeable))
+		for _, id := range purgeable {
+			allowed[id] = true
+		}
+		var ids []uint
+		var orderIDs []string
+		now := time.Now()
+		for _, order := range orders {
+			if allowed[order.ID] {
+				ids = append(ids, order.ID)
+				orderIDs = append(orderIDs, order.OrderID)
+				audits = append(audits, models.NewOrderPurgeAudit(order, action, now))
+			}
+		}
+
+		if err := apply(tx, ids, orderIDs); err != nil {
+			return err
+		}
+		return tx.Create(&audits).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return audits, nil
+}
+
+// SaveLegalHold writes only the hold, as GormOrderRepository.Save leaves it alone
+func (r *GormOrderRetentionRepository) SaveLegalHold(ctx context.Context, order *models.Order) error {
+	now := time.Now()
+	result := Conn(ctx, r.db).Model(&models.Order{}).
+		Where("id = ? AND order_date = ? AND version = ?", order.ID, order.OrderDate, order.Version).
+		Updates(map[string]interface{}{
+			"legal_hold": order.LegalHold,
+			"version":    order.Version + 1,
+			"updated_at": now,
+		})
+	if result.Error != nil {
+		return OrderError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrVersionConflict
+	}
+	order.Version++
+	order.UpdatedAt = now
+	return nil
+}
@@ -0,0 +1,54 @@
+package persistence
+
+import (
+	"context"
+	"order-service/internal/domain/models"
+	"order-service/internal/domain/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLegalHoldSurvivesOrderSave tests that saving an order loaded before it was put on legal hold
+// neither clears the hold nor goes through
+func TestLegalHoldSurvivesOrderSave(t *testing.T) {
+	db := openTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE orders (
+		id INTEGER PRIMARY KEY AUTOINCREMENT, order_id TEXT, customer_id INTEGER, customer_name TEXT,
+		total_amount REAL, status TEXT DEFAULT 'pending', order_date DATETIME, legal_hold NUMERIC DEFAULT false,
+		anonymized_at DATETIME, deleted_at DATETIME, version INTEGER, created_at DATETIME, updated_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE order_items (id INTEGER PRIMARY KEY AUTOINCREMENT, order_id TEXT, order_date DATETIME)`).Error)
+	ctx := context.Background()
+	orders := NewGormOrderRepository(db)
+	retention := NewGormOrderRetentionRepository(db)
+
+	order := models.Order{OrderID: "held", CustomerID: 1, TotalAmount: 5, OrderDate: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)}
+	require.NoError(t, orders.Save(ctx, &order))
+	earlier := order
+
+	held := order
+	held.SetLegalHold(true)
+	require.NoError(t, retention.SaveLegalHold(ctx, &held))
+	assert.Equal(t, order.Version+1, held.Version)
+
+	// The copy loaded before the hold is stale
+	require.NoError(t, earlier.Cancel())
+	assert.ErrorIs(t, orders.Save(ctx, &earlier), repositories.ErrVersionConflict)
+
+	// A current copy that never saw the hold does not clear it either
+	current := held
+	current.LegalHold = false
+	current.CustomerName = "renamed"
+	require.NoError(t, orders.Save(ctx, &current))
+
+	var stored models.Order
+	require.NoError(t, db.First(&stored, order.ID).Error)
+	assert.True(t, stored.LegalHold)
+	assert.Equal(t, "renamed", stored.CustomerName)
+	assert.Equal(t, models.OrderStatusPending, stored.Status)
+
+	// Holding a stale version is refused too
+	assert.ErrorIs(t, retention.SaveLegalHold(ctx, &earlier), repositories.ErrVersionConflict)
+}
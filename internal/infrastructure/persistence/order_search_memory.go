@@ -0,0 +1,115 @@
+package persistence
+
+import (
+	"context"
+	"order-service/internal/domain/models"
+	"order-service/internal/domain/repositories"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// InMemoryOrderSearchRepository is a simple OrderSearchRepository for tests and local runs.
+// Every search term must prefix a word in the order; matches on the reference rank above
+// the customer name, which rank above items.
+type InMemoryOrderSearchRepository struct {
+	mu     sync.RWMutex
+	orders []models.Order
+}
+
+func NewInMemoryOrderSearchRepository(orders ...models.Order) *InMemoryOrderSearchRepository {
+	return &InMemoryOrderSearchRepository{orders: orders}
+}
+
+// Index adds or replaces an order in the search index
+func (r *InMemoryOrderSearchRepository) Index(order models.Order) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.orders {
+		if r.orders[i].OrderID == order.OrderID {
+			r.orders[i] = order
+			return
+		}
+	}
+	r.orders = append(r.orders, order)
+}
+
+func (r *InMemoryOrderSearchRepository) Search(_ context.Context, query repositories.OrderSearchQuery) (repositories.OrderSearchPage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	page := repositories.OrderSearchPage{Page: query.Page, PageSize: query.PageSize, Hits: []repositories.OrderSearchHit{}}
+	terms := searchWords(query.Text)
+	if len(terms) == 0 {
+		return page, nil
+	}
+
+	var hits []repositories.OrderSearchHit
+	for _, order := range r.orders {
+		if rank, ok := rankOrder(order, terms); ok {
+			hits = append(hits, repositories.OrderSearchHit{Order: order, Rank: rank})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Rank != hits[j].Rank {
+			return hits[i].Rank > hits[j].Rank
+		}
+		return hits[i].Order.ID > hits[j].Order.ID
+	})
+
+	page.Total = int64(len(hits))
+	start := (query.Page - 1) * query.PageSize
+	if start < 0 || start >= len(hits) {
+		return page, nil
+	}
+	end := start + query.PageSize
+	if end > len(hits) {
+		end = len(hits)
+	}
+	page.Hits = hits[start:end]
+	return page, nil
+}
+
+func rankOrder(order models.Order, terms []string) (float64, bool) {
+	fields := []struct {
+		words  []string
+		weight float64
+	}{
+		{searchWords(order.OrderID), 1.0},
+		{searchWords(order.CustomerName), 0.4},
+	}
+	var items []string
+	for _, item := range order.OrderItems {
+		items = append(items, searchWords(item.ProductName+" "+strconv.FormatUint(uint64(item.ProductID), 10))...)
+	}
+	fields = append(fields, struct {
+		words  []string
+		weight float64
+	}{items, 0.2})
+
+	var rank float64
+	for _, term := range terms {
+		best := 0.0
+		for _, field := range fields {
+			for _, word := range field.words {
+				if strings.HasPrefix(word, term) && field.weight > best {
+					best = field.weight
+				}
+			}
+		}
+		if best == 0 {
+			return 0, false
+		}
+		rank += best
+	}
+	return rank, true
+}
+
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,81 @@
+package persistence
+
+import (
+	"context"
+	"order-service/internal/domain/models"
+	"order-service/internal/domain/repositories"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// GormOrderSearchRepository searches the tsvector maintained on the orders table
+type GormOrderSearchRepository struct {
+	db *gorm.DB
+}
+
+func NewGormOrderSearchRepository(db *gorm.DB) repositories.OrderSearchRepository {
+	return &GormOrderSearchRepository{db: db}
+}
+
+func (r *GormOrderSearchRepository) Search(ctx context.Context, query repositories.OrderSearchQuery) (repositories.OrderSearchPage, error) {
+	page := repositories.OrderSearchPage{Page: query.Page, PageSize: query.PageSize, Hits: []repositories.OrderSearchHit{}}
+
+	tsquery := prefixTSQuery(query.Text)
+	if tsquery == "" {
+		return page, nil
+	}
+
+	db := ReadSession(ctx, r.db)
+	matches := db.Table("orders").Where("search_vector @@ to_tsquery('simple', ?) AND deleted_at IS NULL", tsquery)
+	if err := matches.Count(&page.Total).Error; err != nil {
+		return page, err
+	}
+	if page.Total == 0 {
+		return page, nil
+	}
+
+	var ranked []struct {
+		ID   uint
+		Rank float64
+	}
+	err := db.Table("orders").
+		Select("id, ts_rank(search_vector, to_tsquery('simple', ?)) AS rank", tsquery).
+		Where("search_vector @@ to_tsquery('simple', ?) AND deleted_at IS NULL", tsquery).
+		Order("rank DESC, id DESC").
+		Limit(query.PageSize).
+		Offset((query.Page - 1) * query.PageSize).
+		Scan(&ranked).Error
+	if err != nil || len(ranked) == 0 {
+		return page, err
+	}
+
+	ids := make([]uint, len(ranked))
+	for i, row := range ranked {
+		ids[i] = row.ID
+	}
+	var orders []models.Order
+	if err := db.Preload("OrderItems").Find(&orders, ids).Error; err != nil {
+		return page, err
+	}
+
+	byID := make(map[uint]models.Order, len(orders))
+	for _, order := range orders {
+		byID[order.ID] = order
+	}
+	for _, row := range ranked {
+		if order, ok := byID[row.ID]; ok {
+			page.Hits = append(page.Hits, repositories.OrderSearchHit{Order: order, Rank: row.Rank})
+		}
+	}
+	return page, nil
+}
+
+// prefixTSQuery turns free text into an AND of prefix terms, e.g. "Jo Smi" -> "jo:* & smi:*"
+func prefixTSQuery(text string) string {
+	terms := searchWords(text)
+	for i, term := range terms {
+		terms[i] = term + ":*"
+	}
+	return strings.Join(terms, " & ")
+}
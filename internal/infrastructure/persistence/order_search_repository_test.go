@@ -0,0 +1,164 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"order-service/internal/domain/repositories"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestPrefixTSQuery tests that free text becomes a safe prefix tsquery
+func TestPrefixTSQuery(t *testing.T) {
+	assert.Equal(t, "jo:* & smi:*", prefixTSQuery("Jo  Smi"))
+	assert.Equal(t, "ord:* & 2024:* & 001:*", prefixTSQuery("ORD-2024-001"))
+	assert.Equal(t, "drop:* & table:*", prefixTSQuery("'); DROP TABLE & | !"))
+	assert.Equal(t, "", prefixTSQuery("  -- "))
+}
+
+// scriptedQuery answers every statement that contains Match with Columns and Rows
+type scriptedQuery struct {
+	Match   string
+	Columns []string
+	Rows    [][]driver.Value
+}
+
+// scriptedDB is a database/sql driver that answers queries from a script and records what it was asked.
+// It stands in for Postgres where SQLite cannot run the query, such as full text search.
+type scriptedDB struct {
+	script   []scriptedQuery
+	mu       sync.Mutex
+	executed []string
+	args     [][]driver.Value
+}
+
+func (d *scriptedDB) Connect(context.Context) (driver.Conn, error) { return scriptedConn{d}, nil }
+func (d *scriptedDB) Driver() driver.Driver                        { return nil }
+
+// queries returns the statements run so far and their arguments
+func (d *scriptedDB) queries() ([]string, [][]driver.Value) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.executed, d.args
+}
+
+type scriptedConn struct{ db *scriptedDB }
+
+func (c scriptedConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c scriptedConn) Close() error                        { return nil }
+func (c scriptedConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c scriptedConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	c.db.executed = append(c.db.executed, query)
+	c.db.args = append(c.db.args, values)
+	for _, scripted := range c.db.script {
+		if strings.Contains(query, scripted.Match) {
+			return &scriptedRows{columns: scripted.Columns, rows: scripted.Rows}, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected query %q", query)
+}
+
+type scriptedRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *scriptedRows) Columns() []string { return r.columns }
+func (r *scriptedRows) Close() error      { return nil }
+
+func (r *scriptedRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// openScriptedDB opens a Postgres session whose queries are answered by script
+func openScriptedDB(t *testing.T, script ...scriptedQuery) (*gorm.DB, *scriptedDB) {
+	t.Helper()
+	scripted := &scriptedDB{script: script}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sql.OpenDB(scripted)}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	return db, scripted
+}
+
+// TestGormOrderSearchRepository tests that matches are counted, ranked, paged and loaded with their items
+func TestGormOrderSearchRepository(t *testing.T) {
+	db, scripted := openScriptedDB(t,
+		scriptedQuery{Match: "count(*)", Columns: []string{"count"}, Rows: [][]driver.Value{{int64(5)}}},
+		scriptedQuery{Match: "ts_rank", Columns: []string{"id", "rank"}, Rows: [][]driver.Value{{int64(7), 0.9}, {int64(3), 0.4}}},
+		scriptedQuery{Match: `FROM "orders"`, Columns: []string{"id", "order_id", "customer_name"}, Rows: [][]driver.Value{
+			{int64(3), "ORD-3", "Jane Widgetson"},
+			{int64(7), "ORD-7", "John Smith"},
+		}},
+		scriptedQuery{Match: `FROM "order_items"`, Columns: []string{"id", "order_id", "product_name"}, Rows: [][]driver.Value{
+			{int64(1), "ORD-7", "Blue Widget"},
+		}},
+	)
+
+	page, err := NewGormOrderSearchRepository(db).Search(context.Background(), repositories.OrderSearchQuery{Text: "Wid", Page: 2, PageSize: 2})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(5), page.Total)
+	assert.Equal(t, 2, page.Page)
+	assert.Equal(t, 2, page.PageSize)
+	require.Len(t, page.Hits, 2)
+	assert.Equal(t, "ORD-7", page.Hits[0].Order.OrderID, "hits keep the rank order, not the load order")
+	assert.Equal(t, 0.9, page.Hits[0].Rank)
+	require.Len(t, page.Hits[0].Order.OrderItems, 1)
+	assert.Equal(t, "Blue Widget", page.Hits[0].Order.OrderItems[0].ProductName)
+	assert.Equal(t, "ORD-3", page.Hits[1].Order.OrderID)
+	assert.Equal(t, 0.4, page.Hits[1].Rank)
+	assert.Empty(t, page.Hits[1].Order.OrderItems)
+
+	queries, args := scripted.queries()
+	require.Len(t, queries, 4)
+	assert.Contains(t, queries[0], "search_vector @@ to_tsquery('simple', $1) AND deleted_at IS NULL")
+	assert.Equal(t, []driver.Value{"wid:*"}, args[0])
+	assert.Contains(t, queries[1], "ORDER BY rank DESC, id DESC")
+	assert.Equal(t, []driver.Value{"wid:*", "wid:*", int64(2), int64(2)}, args[1], "the second page of two skips two matches")
+}
+
+// TestGormOrderSearchRepositoryNoMatches tests that the database is not asked for text without terms
+// and that no page is loaded when nothing matches
+func TestGormOrderSearchRepositoryNoMatches(t *testing.T) {
+	db, scripted := openScriptedDB(t,
+		scriptedQuery{Match: "count(*)", Columns: []string{"count"}, Rows: [][]driver.Value{{int64(0)}}},
+	)
+	repo := NewGormOrderSearchRepository(db)
+
+	page, err := repo.Search(context.Background(), repositories.OrderSearchQuery{Text: " -- ", Page: 1, PageSize: 20})
+	require.NoError(t, err)
+	assert.Empty(t, page.Hits)
+	queries, _ := scripted.queries()
+	assert.Empty(t, queries)
+
+	page, err = repo.Search(context.Background(), repositories.OrderSearchQuery{Text: "nobody", Page: 1, PageSize: 20})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), page.Total)
+	assert.NotNil(t, page.Hits)
+	queries, _ = scripted.queries()
+	assert.Len(t, queries, 1)
+}
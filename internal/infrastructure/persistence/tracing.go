@@ -0,0 +1,79 @@
+package persistence
+
+import (
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// spanKey is the statement setting holding the span of a running statement
+const spanKey = "otel:span"
+
+// TracingPlugin starts a client span for every statement GORM runs, as a child of the span in the
+// statement's context. Spans carry the SQL with its placeholders, never the bound values.
+type TracingPlugin struct {
+	tracer trace.Tracer
+}
+
+func NewTracingPlugin() *TracingPlugin {
+	return &TracingPlugin{tracer: otel.Tracer("order-service/gorm")}
+}
+
+func (p *TracingPlugin) Name() string {
+	return "otel"
+}
+
+func (p *TracingPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register("otel:before_create", p.before("create")),
+		callbacks.Create().After("gorm:create").Register("otel:after_create", p.after),
+		callbacks.Query().Before("gorm:query").Register("otel:before_query", p.before("query")),
+		callbacks.Query().After("gorm:query").Register("otel:after_query", p.after),
+		callbacks.Update().Before("gorm:update").Register("otel:before_update", p.before("update")),
+		callbacks.Update().After("gorm:update").Register("otel:after_update", p.after),
+		callbacks.Delete().Before("gorm:delete").Register("otel:before_delete", p.before("delete")),
+		callbacks.Delete().After("gorm:delete").Register("otel:after_delete", p.after),
+		callbacks.Row().Before("gorm:row").Register("otel:before_row", p.before("row")),
+		callbacks.Row().After("gorm:row").Register("otel:after_row", p.after),
+		callbacks.Raw().Before("gorm:raw").Register("otel:before_raw", p.before("raw")),
+		callbacks.Raw().After("gorm:raw").Register("otel:after_raw", p.after),
+	)
+}
+
+func (p *TracingPlugin) before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx, span := p.tracer.Start(db.Statement.Context, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperationNameKey.String(operation)))
+		db.Statement.Context = ctx
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+func (p *TracingPlugin) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	defer span.End()
+
+	if db.Statement.Table != "" {
+		span.SetAttributes(semconv.DBCollectionNameKey.String(db.Statement.Table))
+	}
+	if query := strings.TrimSpace(db.Statement.SQL.String()); query != "" {
+		span.SetAttributes(semconv.DBQueryTextKey.String(query))
+	}
+	span.SetAttributes(attribute.Int64("db.rows_affected", db.RowsAffected))
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
@@ -0,0 +1,53 @@
+package persistence
+
+import (
+	"context"
+	"order-service/internal/domain/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// TestTracingPlugin tests that statements run as child spans carrying their SQL but not its values
+func TestTracingPlugin(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	// A dry run builds every statement without a database to run it on
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(NewTracingPlugin()))
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "command")
+	var order models.Order
+	db.WithContext(ctx).Where("order_id = ?", "ORD-secret").First(&order)
+	db.WithContext(ctx).Model(&models.Order{ID: 1}).Update("status", "cancelled")
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	query := spans[0]
+	assert.Equal(t, "gorm.query", query.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), query.Parent().SpanID())
+	assert.Contains(t, query.Attributes(), attribute.String("db.system", "postgresql"))
+	assert.Contains(t, query.Attributes(), attribute.String("db.collection.name", "orders"))
+	var statement string
+	for _, attr := range query.Attributes() {
+		if attr.Key == "db.query.text" {
+			statement = attr.Value.AsString()
+		}
+	}
+	assert.Contains(t, statement, "order_id = $1")
+	assert.NotContains(t, statement, "ORD-secret")
+
+	assert.Equal(t, "gorm.update", spans[1].Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[1].Parent().SpanID())
+}
@@ -0,0 +1,62 @@
+package persistence
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type transactionKey struct{}
+
+type afterTransactionKey struct{}
+
+// GormTransactionManager runs work in a database transaction carried in the context
+type GormTransactionManager struct {
+	db *gorm.DB
+}
+
+func NewGormTransactionManager(db *gorm.DB) *GormTransactionManager {
+	return &GormTransactionManager{db: db}
+}
+
+// WithinTransaction runs fn in a transaction, joining the one already in ctx if there is one.
+// Repositories pick the transaction up from the context passed to fn.
+func (m *GormTransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if InTransaction(ctx) {
+		return fn(ctx)
+	}
+	var after []func()
+	ctx = context.WithValue(ctx, afterTransactionKey{}, &after)
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, transactionKey{}, tx))
+	})
+	for _, f := range after {
+		f()
+	}
+	return err
+}
+
+// AfterTransaction runs fn once the transaction in ctx has committed or rolled back, or right away
+// when there is none. Caches evict through it, so no reader can cache a row again before the
+// change is committed.
+func AfterTransaction(ctx context.Context, fn func()) {
+	if after, ok := ctx.Value(afterTransactionKey{}).(*[]func()); ok {
+		*after = append(*after, fn)
+		return
+	}
+	fn()
+}
+
+// InTransaction reports whether ctx carries a transaction
+func InTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(transactionKey{}).(*gorm.DB)
+	return ok
+}
+
+// Conn returns the transaction in ctx, or db when there is none
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(transactionKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
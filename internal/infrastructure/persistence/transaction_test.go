@@ -0,0 +1,31 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAfterTransaction tests that deferred work runs when the outermost transaction ends, whatever its outcome
+func TestAfterTransaction(t *testing.T) {
+	manager := NewGormTransactionManager(openTestDB(t))
+
+	ran := false
+	AfterTransaction(context.Background(), func() { ran = true })
+	assert.True(t, ran, "without a transaction the work runs right away")
+
+	for _, failure := range []error{nil, errors.New("command failed")} {
+		ran = false
+		err := manager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+			return manager.WithinTransaction(ctx, func(ctx context.Context) error {
+				AfterTransaction(ctx, func() { ran = true })
+				assert.False(t, ran, "the work waits for the transaction")
+				return failure
+			})
+		})
+		assert.Equal(t, failure, err)
+		assert.True(t, ran)
+	}
+}
@@ -0,0 +1,24 @@
+package projections
+
+import (
+	"context"
+	"order-service/internal/domain/services"
+)
+
+// ProjectingEventPublisher updates the read model before passing events on
+type ProjectingEventPublisher struct {
+	next      services.EventPublisher
+	projector *OrderProjector
+}
+
+// NewProjectingEventPublisher wraps next so published events keep the read model current
+func NewProjectingEventPublisher(next services.EventPublisher, projector *OrderProjector) *ProjectingEventPublisher {
+	return &ProjectingEventPublisher{next: next, projector: projector}
+}
+
+func (p *ProjectingEventPublisher) Publish(ctx context.Context, event interface{}) error {
+	if err := p.projector.Apply(ctx, event); err != nil {
+		return err
+	}
+	return p.next.Publish(ctx, event)
+}
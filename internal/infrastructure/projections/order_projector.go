@@ -0,0 +1,211 @@
+package projections
+
+import (
+	"context"
+	"fmt"
+	"order-service/internal/application/query"
+	"order-service/internal/domain/events"
+	"order-service/internal/domain/models"
+	"order-service/internal/infrastructure/persistence"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// rebuildBatchSize is how many orders Rebuild replays per batch
+const rebuildBatchSize = 500
+
+// OrderProjector applies order domain events to the order summary and customer history tables
+type OrderProjector struct {
+	db *gorm.DB
+}
+
+func NewOrderProjector(db *gorm.DB) *OrderProjector {
+	return &OrderProjector{db: db}
+}
+
+// Apply projects a single event, events it does not know are ignored. It joins the transaction in
+// ctx, so the read model only changes when the command that published the event commits.
+func (p *OrderProjector) Apply(ctx context.Context, event interface{}) error {
+	return persistence.Conn(ctx, p.db).Transaction(func(tx *gorm.DB) error {
+		return p.apply(tx, event)
+	})
+}
+
+// Rebuild clears the read model and replays the order history from the write model
+func (p *OrderProjector) Rebuild(ctx context.Context) (int, error) {
+	replayed := 0
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("TRUNCATE order_summaries, customer_order_histories").Error; err != nil {
+			return err
+		}
+
+		var orders []models.Order
+		return tx.Preload("OrderItems").Where("deleted_at IS NULL").Order("id").FindInBatches(&orders, rebuildBatchSize, func(batch *gorm.DB, _ int) error {
+			for _, order := range orders {
+				if err := p.apply(tx, events.NewOrderCreatedEvent(order)); err != nil {
+					return fmt.Errorf("failed to replay order %s: %w", order.OrderID, err)
+				}
+			}
+			replayed += len(orders)
+			return nil
+		}).Error
+	})
+	return replayed, err
+}
+
+func (p *OrderProjector) apply(tx *gorm.DB, event interface{}) error {
+	switch e := event.(type) {
+	case events.OrderCreatedEvent:
+		return p.orderCreated(tx, e)
+	case events.OrderItemAddedEvent:
+		return p.orderItemAdded(tx, e)
+	case events.OrderCancelledEvent:
+		return p.orderCancelled(tx, e)
+	case events.OrderUpdatedEvent:
+		return p.orderUpdated(tx, e)
+	case events.OrderDeletedEvent:
+		return p.orderDeleted(tx, e)
+	case events.OrderLegalHoldChangedEvent:
+		return p.orderLegalHoldChanged(tx, e)
+	case events.OrderPurgedEvent:
+		return p.orderPurged(tx, e)
+	}
+	return nil
+}
+
+func (p *OrderProjector) orderCreated(tx *gorm.DB, e events.OrderCreatedEvent) error {
+	summary := query.OrderSummary{
+		ID:           e.OrderID,
+		OrderID:      e.Reference,
+		CustomerID:   e.CustomerID,
+		CustomerName: e.CustomerName,
+		ItemCount:    len(e.Items),
+		TotalAmount:  e.TotalAmount,
+		Items:        make([]query.OrderSummaryItem, len(e.Items)),
+		Status:       string(e.Status),
+		OrderDate:    e.OrderDate,
+		Version:      e.Version,
+		UpdatedAt:    e.UpdatedAt,
+	}
+	for i, item := range e.Items {
+		summary.Items[i] = summaryItem(item)
+	}
+	if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&summary).Error; err != nil {
+		return err
+	}
+
+	// Anonymized orders no longer belong to a customer
+	if e.CustomerID == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&query.CustomerOrderHistory{
+		CustomerID:  e.CustomerID,
+		OrderID:     e.Reference,
+		ItemCount:   summary.ItemCount,
+		TotalAmount: summary.TotalAmount,
+		OrderDate:   summary.OrderDate,
+	}).Error
+}
+
+func (p *OrderProjector) orderItemAdded(tx *gorm.DB, e events.OrderItemAddedEvent) error {
+	var summary query.OrderSummary
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&summary, e.OrderID).Error
+	if err != nil {
+		return err
+	}
+
+	summary.Items = append(summary.Items, summaryItem(e.Item))
+	summary.ItemCount = len(summary.Items)
+	summary.TotalAmount = e.TotalAmount
+	summary.Version = e.Version
+	summary.UpdatedAt = e.UpdatedAt
+	if err := tx.Save(&summary).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&query.CustomerOrderHistory{}).
+		Where("customer_id = ? AND order_id = ?", summary.CustomerID, summary.OrderID).
+		Updates(map[string]interface{}{"item_count": summary.ItemCount, "total_amount": summary.TotalAmount}).Error
+}
+
+func (p *OrderProjector) orderCancelled(tx *gorm.DB, e events.OrderCancelledEvent) error {
+	return tx.Model(&query.OrderSummary{}).Where("id = ?", e.OrderID).Updates(map[string]interface{}{
+		"status":     string(models.OrderStatusCancelled),
+		"version":    e.Version,
+		"updated_at": e.UpdatedAt,
+	}).Error
+}
+
+func (p *OrderProjector) orderUpdated(tx *gorm.DB, e events.OrderUpdatedEvent) error {
+	items := make([]query.OrderSummaryItem, len(e.Items))
+	for i, item := range e.Items {
+		items[i] = summaryItem(item)
+	}
+
+	var summary query.OrderSummary
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&summary, e.OrderID).Error
+	if err != nil {
+		return err
+	}
+	summary.CustomerName = e.CustomerName
+	summary.Items = items
+	summary.ItemCount = len(items)
+	summary.TotalAmount = e.TotalAmount
+	summary.Version = e.Version
+	summary.UpdatedAt = e.UpdatedAt
+	if err := tx.Save(&summary).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&query.CustomerOrderHistory{}).
+		Where("customer_id = ? AND order_id = ?", summary.CustomerID, summary.OrderID).
+		Updates(map[string]interface{}{"item_count": summary.ItemCount, "total_amount": summary.TotalAmount}).Error
+}
+
+// orderDeleted drops a deleted order from the read model, like a purge
+func (p *OrderProjector) orderDeleted(tx *gorm.DB, e events.OrderDeletedEvent) error {
+	if err := tx.Where("order_id = ?", e.Reference).Delete(&query.CustomerOrderHistory{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("id = ?", e.OrderID).Delete(&query.OrderSummary{}).Error
+}
+
+// orderLegalHoldChanged keeps the summary's version current; the hold itself is not part of the read model
+func (p *OrderProjector) orderLegalHoldChanged(tx *gorm.DB, e events.OrderLegalHoldChangedEvent) error {
+	return tx.Model(&query.OrderSummary{}).Where("id = ?", e.OrderID).Updates(map[string]interface{}{
+		"version":    e.Version,
+		"updated_at": e.UpdatedAt,
+	}).Error
+}
+
+func (p *OrderProjector) orderPurged(tx *gorm.DB, e events.OrderPurgedEvent) error {
+	var summary query.OrderSummary
+	err := tx.Where("id = ?", e.OrderID).Limit(1).Find(&summary).Error
+	if err != nil || summary.ID == 0 {
+		return err
+	}
+
+	if err := tx.Where("order_id = ?", summary.OrderID).Delete(&query.CustomerOrderHistory{}).Error; err != nil {
+		return err
+	}
+	if e.Action == string(models.RetentionDelete) {
+		return tx.Delete(&summary).Error
+	}
+	return tx.Model(&summary).Updates(map[string]interface{}{
+		"customer_id":   0,
+		"customer_name": "",
+		"version":       gorm.Expr("version + 1"),
+		"updated_at":    time.Now(),
+	}).Error
+}
+
+func summaryItem(item models.OrderItem) query.OrderSummaryItem {
+	return query.OrderSummaryItem{
+		ProductID:   item.ProductID,
+		ProductName: item.ProductName,
+		Quantity:    item.Quantity,
+		Price:       item.Price,
+	}
+}
@@ -0,0 +1,81 @@
+package projections
+
+import (
+	"context"
+	"errors"
+	"order-service/internal/application/command"
+	"order-service/internal/application/query"
+	"order-service/internal/domain/events"
+	"order-service/internal/domain/models"
+	"order-service/internal/infrastructure/persistence"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// publishOrder is a command that publishes the creation of Order and then fails with Err
+type publishOrder struct {
+	Order models.Order
+	Err   error
+}
+
+func (publishOrder) CommandName() string { return "publish_order" }
+
+// nopPublisher stands in for the publishers after the projection
+type nopPublisher struct{}
+
+func (nopPublisher) Publish(context.Context, interface{}) error { return nil }
+
+// openReadModel opens an SQLite database with the read model tables
+func openReadModel(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&query.OrderSummary{}, &query.CustomerOrderHistory{}))
+	return db
+}
+
+// TestApplyJoinsCommandTransaction tests that the read model only changes when the publishing command commits
+func TestApplyJoinsCommandTransaction(t *testing.T) {
+	db := openReadModel(t)
+	publisher := NewProjectingEventPublisher(nopPublisher{}, NewOrderProjector(db))
+	bus := command.NewBus(command.TransactionMiddleware(persistence.NewGormTransactionManager(db)))
+	command.Register(bus, func(ctx context.Context, cmd publishOrder) (struct{}, error) {
+		if err := publisher.Publish(ctx, events.NewOrderCreatedEvent(cmd.Order)); err != nil {
+			return struct{}{}, err
+		}
+		return struct{}{}, cmd.Err
+	})
+	readModel := NewGormReadModel(db)
+
+	rolledBack := models.Order{ID: 1, OrderID: "ORD-1", CustomerID: 9, OrderDate: time.Now()}
+	_, err := bus.Dispatch(context.Background(), publishOrder{Order: rolledBack, Err: errors.New("command failed")})
+	require.Error(t, err)
+
+	summaries, err := readModel.FindOrderSummaries(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, summaries, "the summary is rolled back with the command")
+	history, err := readModel.FindCustomerOrderHistory(context.Background(), 9)
+	require.NoError(t, err)
+	assert.Empty(t, history, "the customer history is rolled back with the command")
+
+	committed := models.Order{ID: 2, OrderID: "ORD-2", CustomerID: 9, OrderDate: time.Now()}
+	_, err = bus.Dispatch(context.Background(), publishOrder{Order: committed})
+	require.NoError(t, err)
+
+	summaries, err = readModel.FindOrderSummaries(context.Background())
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, "ORD-2", summaries[0].OrderID)
+	history, err = readModel.FindCustomerOrderHistory(context.Background(), 9)
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+}
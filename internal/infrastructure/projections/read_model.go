@@ -0,0 +1,42 @@
+package projections
+
+import (
+	"context"
+	"order-service/internal/application/query"
+	"order-service/internal/infrastructure/persistence"
+
+	"gorm.io/gorm"
+)
+
+// GormReadModel reads the projected order tables
+type GormReadModel struct {
+	db *gorm.DB
+}
+
+func NewGormReadModel(db *gorm.DB) query.ReadModel {
+	return &GormReadModel{db: db}
+}
+
+func (r *GormReadModel) FindOrderSummary(ctx context.Context, id uint) (*query.OrderSummary, error) {
+	var summary query.OrderSummary
+	err := persistence.ReadSession(ctx, r.db).First(&summary, id).Error
+	return &summary, persistence.OrderError(err)
+}
+
+func (r *GormReadModel) FindOrderSummaries(ctx context.Context) ([]query.OrderSummary, error) {
+	var summaries []query.OrderSummary
+	err := persistence.ReadSession(ctx, r.db).Order("id").Find(&summaries).Error
+	return summaries, err
+}
+
+func (r *GormReadModel) FindCustomerOrderSummaries(ctx context.Context, customerID uint) ([]query.OrderSummary, error) {
+	var summaries []query.OrderSummary
+	err := persistence.ReadSession(ctx, r.db).Where("customer_id = ?", customerID).Order("id").Find(&summaries).Error
+	return summaries, err
+}
+
+func (r *GormReadModel) FindCustomerOrderHistory(ctx context.Context, customerID uint) ([]query.CustomerOrderHistory, error) {
+	var history []query.CustomerOrderHistory
+	err := persistence.ReadSession(ctx, r.db).Where("customer_id = ?", customerID).Order("order_date DESC").Find(&history).Error
+	return history, err
+}
@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the memory store drops counters of past windows
+const sweepInterval = time.Minute
+
+// MemoryStore is a Store for a single instance that keeps its counters in process
+type MemoryStore struct {
+	mu        sync.Mutex
+	counters  map[string]*counter
+	lastSweep time.Time
+}
+
+type counter struct {
+	index    int64
+	current  int64
+	previous int64
+	// expiresAt is when the counter no longer affects any decision
+	expiresAt time.Time
+}
+
+// NewMemoryStore creates an empty in-process store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{counters: map[string]*counter{}}
+}
+
+func (s *MemoryStore) Allow(_ context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	index, previousWeight, reset := window(limit, now)
+	c, ok := s.counters[key]
+	switch {
+	case !ok:
+		c = &counter{index: index}
+		s.counters[key] = c
+	case c.index == index-1:
+		c.index, c.previous, c.current = index, c.current, 0
+	case c.index != index:
+		c.index, c.previous, c.current = index, 0, 0
+	}
+
+	res := result(limit, c.current, c.previous, previousWeight, reset)
+	if res.Allowed {
+		c.current++
+	}
+	c.expiresAt = now.Add(reset + limit.Window)
+	return res, nil
+}
+
+func (s *MemoryStore) Refund(_ context.Context, key string, limit Limit, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index, _, _ := window(limit, now)
+	if c, ok := s.counters[key]; ok && c.index == index && c.current > 0 {
+		c.current--
+	}
+	return nil
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, c := range s.counters {
+		if now.After(c.expiresAt) {
+			delete(s.counters, key)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// allowScript counts a request in KEYS[1] unless the weighted total with the previous window in
+// KEYS[2] has reached the limit. ARGV holds the limit, the previous window's weight and the
+// counter expiry in milliseconds. It returns whether the request was counted and both counts
+// before it.
+var allowScript = redis.NewScript(`
+local current = tonumber(redis.call('GET', KEYS[1]) or '0')
+local previous = tonumber(redis.call('GET', KEYS[2]) or '0')
+if math.floor(previous * tonumber(ARGV[2])) + current >= tonumber(ARGV[1]) then
+	return {0, current, previous}
+end
+if redis.call('INCR', KEYS[1]) == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[3])
+end
+return {1, current, previous}
+`)
+
+// refundScript takes back a request counted in KEYS[1], unless its window already expired
+var refundScript = redis.NewScript(`
+if tonumber(redis.call('GET', KEYS[1]) or '0') > 0 then
+	redis.call('DECR', KEYS[1])
+end
+return 0
+`)
+
+// RedisStore is a Store shared by every instance through any Redis-compatible server
+type RedisStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisStore creates a store that namespaces its keys with prefix
+func NewRedisStore(client redis.UniversalClient, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+func (s *RedisStore) Allow(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	index, previousWeight, reset := window(limit, now)
+	// The hash tag keeps both windows of a key in one cluster slot, as the script needs
+	base := s.prefix + "{" + key + "}:"
+	keys := []string{base + strconv.FormatInt(index, 10), base + strconv.FormatInt(index-1, 10)}
+	expiry := (reset + limit.Window).Milliseconds()
+
+	counts, err := allowScript.Run(ctx, s.client, keys, limit.Requests, previousWeight, expiry).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	return result(limit, counts[1], counts[2], previousWeight, reset), nil
+}
+
+func (s *RedisStore) Refund(ctx context.Context, key string, limit Limit, now time.Time) error {
+	index, _, _ := window(limit, now)
+	return refundScript.Run(ctx, s.client, []string{s.prefix + "{" + key + "}:" + strconv.FormatInt(index, 10)}).Err()
+}
@@ -0,0 +1,111 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Parts a rule can key its counters by
+const (
+	ByClient = "client"
+	ByIP     = "ip"
+	ByRoute  = "route"
+)
+
+// Rule limits the requests matching Method and Path, counted separately for every combination
+// of the parts in By. Clients listed in Clients get their own number of requests per window.
+type Rule struct {
+	Name string `json:"name"`
+	// Method is an HTTP method, empty for every method
+	Method string `json:"method,omitempty"`
+	// Path is a route such as /orders/:id without its version prefix, empty for every path
+	Path     string         `json:"path,omitempty"`
+	By       []string       `json:"by"`
+	Requests int            `json:"requests"`
+	Window   time.Duration  `json:"-"`
+	Fixed    bool           `json:"fixed,omitempty"`
+	Clients  map[string]int `json:"clients,omitempty"`
+}
+
+// Limit returns the limit that applies to client
+func (r Rule) Limit(client string) Limit {
+	requests, ok := r.Clients[client]
+	if !ok {
+		requests = r.Requests
+	}
+	return Limit{Requests: requests, Window: r.Window, Fixed: r.Fixed}
+}
+
+// PerClient reports whether a rule needs the authenticated client, to key its counters or to
+// pick its limit
+func (r Rule) PerClient() bool {
+	for _, part := range r.By {
+		if part == ByClient {
+			return true
+		}
+	}
+	return len(r.Clients) > 0
+}
+
+// SplitRules separates the rules that only need the request from those that need its client, in
+// their original order
+func SplitRules(rules []Rule) (anonymous, perClient []Rule) {
+	for _, rule := range rules {
+		if rule.PerClient() {
+			perClient = append(perClient, rule)
+		} else {
+			anonymous = append(anonymous, rule)
+		}
+	}
+	return anonymous, perClient
+}
+
+// ParseRules reads rules from a JSON array, where windows are durations such as "1m" or "24h":
+//
+//	[
+//	  {"name": "create-orders", "method": "POST", "path": "/orders", "by": ["client"], "requests": 10, "window": "1m"},
+//	  {"name": "per-ip", "by": ["ip", "route"], "requests": 300, "window": "1m"},
+//	  {"name": "daily-quota", "by": ["client"], "requests": 10000, "window": "24h", "fixed": true, "clients": {"acme": 50000}}
+//	]
+func ParseRules(data []byte) ([]Rule, error) {
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+	for i, rule := range rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rule %d has no name", i)
+		}
+		if rule.Requests < 1 || rule.Window <= 0 {
+			return nil, fmt.Errorf("rule %s needs a positive number of requests and window", rule.Name)
+		}
+		for _, part := range rule.By {
+			if part != ByClient && part != ByIP && part != ByRoute {
+				return nil, fmt.Errorf("rule %s cannot be keyed by %q", rule.Name, part)
+			}
+		}
+	}
+	return rules, nil
+}
+
+// UnmarshalJSON reads the window of a rule as a duration string
+func (r *Rule) UnmarshalJSON(data []byte) error {
+	type plainRule Rule
+	var raw struct {
+		plainRule
+		Window string `json:"window"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*r = Rule(raw.plainRule)
+	if raw.Window != "" {
+		window, err := time.ParseDuration(raw.Window)
+		if err != nil {
+			return fmt.Errorf("rule %s: %w", r.Name, err)
+		}
+		r.Window = window
+	}
+	return nil
+}
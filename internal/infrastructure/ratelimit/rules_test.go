@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseRules tests that rules are read with their windows and per-client overrides
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules([]byte(`[
+		{"name": "create-orders", "method": "POST", "path": "/orders", "by": ["client"], "requests": 10, "window": "1m"},
+		{"name": "daily-quota", "by": ["client"], "requests": 1000, "window": "24h", "fixed": true, "clients": {"acme": 5000}}
+	]`))
+	assert.NoError(t, err)
+	assert.Equal(t, []Rule{
+		{Name: "create-orders", Method: "POST", Path: "/orders", By: []string{ByClient}, Requests: 10, Window: time.Minute},
+		{Name: "daily-quota", By: []string{ByClient}, Requests: 1000, Window: 24 * time.Hour, Fixed: true, Clients: map[string]int{"acme": 5000}},
+	}, rules)
+
+	assert.Equal(t, Limit{Requests: 5000, Window: 24 * time.Hour, Fixed: true}, rules[1].Limit("acme"))
+	assert.Equal(t, Limit{Requests: 1000, Window: 24 * time.Hour, Fixed: true}, rules[1].Limit("globex"))
+
+	for _, invalid := range []string{
+		`[{"by": ["ip"], "requests": 1, "window": "1m"}]`,
+		`[{"name": "a", "by": ["ip"], "requests": 0, "window": "1m"}]`,
+		`[{"name": "a", "by": ["ip"], "requests": 1, "window": "a minute"}]`,
+		`[{"name": "a", "by": ["user"], "requests": 1, "window": "1m"}]`,
+	} {
+		_, err := ParseRules([]byte(invalid))
+		assert.Error(t, err, invalid)
+	}
+}
+
+// TestSplitRules tests that rules needing the authenticated client are told apart from the rest
+func TestSplitRules(t *testing.T) {
+	perIP := Rule{Name: "per-ip", By: []string{ByIP, ByRoute}}
+	global := Rule{Name: "global"}
+	perClient := Rule{Name: "per-client", By: []string{ByClient}}
+	overrides := Rule{Name: "overrides", By: []string{ByIP}, Clients: map[string]int{"acme": 5}}
+
+	anonymous, clients := SplitRules([]Rule{perIP, perClient, global, overrides})
+	assert.Equal(t, []Rule{perIP, global}, anonymous)
+	assert.Equal(t, []Rule{perClient, overrides}, clients)
+}
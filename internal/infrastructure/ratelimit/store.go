@@ -0,0 +1,57 @@
+// Package ratelimit counts requests against limits in pluggable stores.
+//
+// Requests are counted in windows aligned to multiples of the limit's window since the Unix
+// epoch, so a daily window starts at midnight UTC. A sliding limit also counts the previous
+// window, weighted by how much of it still falls within the last window length, which stops
+// clients from sending twice the limit around a window boundary. A fixed limit resets at the
+// end of each window, which suits quotas.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit allows Requests per Window
+type Limit struct {
+	Requests int
+	Window   time.Duration
+	Fixed    bool
+}
+
+// Result is the state of a limit after a request was counted, or refused
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the current window ends
+	Reset time.Duration
+}
+
+// Store counts requests per key
+type Store interface {
+	// Allow counts a request for key if limit still allows it at now
+	Allow(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+	// Refund takes back a request Allow counted for key at now
+	Refund(ctx context.Context, key string, limit Limit, now time.Time) error
+}
+
+// window returns the index of the window now falls in, the weight of the previous window and
+// how long the current window lasts
+func window(limit Limit, now time.Time) (index int64, previousWeight float64, reset time.Duration) {
+	index = now.UnixNano() / int64(limit.Window)
+	elapsed := time.Duration(now.UnixNano() - index*int64(limit.Window))
+	if !limit.Fixed {
+		previousWeight = 1 - float64(elapsed)/float64(limit.Window)
+	}
+	return index, previousWeight, limit.Window - elapsed
+}
+
+// result decides a request given the counts of the current and previous windows before it
+func result(limit Limit, current, previous int64, previousWeight float64, reset time.Duration) Result {
+	count := int(float64(previous)*previousWeight) + int(current)
+	if count >= limit.Requests {
+		return Result{Limit: limit.Requests, Reset: reset}
+	}
+	return Result{Allowed: true, Limit: limit.Requests, Remaining: limit.Requests - count - 1, Reset: reset}
+}
@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// stores returns every Store implementation, the Redis one backed by miniredis
+func stores(t *testing.T) map[string]Store {
+	server := miniredis.RunT(t)
+	return map[string]Store{
+		"memory": NewMemoryStore(),
+		"redis":  NewRedisStore(redis.NewClient(&redis.Options{Addr: server.Addr()}), "test:"),
+	}
+}
+
+// TestFixedLimit tests that a fixed limit refuses requests once exhausted and resets with the next window
+func TestFixedLimit(t *testing.T) {
+	limit := Limit{Requests: 2, Window: time.Minute, Fixed: true}
+	start := time.Date(2024, 10, 27, 14, 0, 10, 0, time.UTC)
+
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			res, err := store.Allow(ctx, "client", limit, start)
+			assert.NoError(t, err)
+			assert.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 1, Reset: 50 * time.Second}, res)
+
+			res, _ = store.Allow(ctx, "client", limit, start.Add(time.Second))
+			assert.True(t, res.Allowed)
+			assert.Equal(t, 0, res.Remaining)
+
+			res, _ = store.Allow(ctx, "client", limit, start.Add(2*time.Second))
+			assert.False(t, res.Allowed)
+			assert.Equal(t, 48*time.Second, res.Reset)
+
+			res, _ = store.Allow(ctx, "other", limit, start.Add(2*time.Second))
+			assert.True(t, res.Allowed, "keys are counted separately")
+
+			res, _ = store.Allow(ctx, "client", limit, start.Add(50*time.Second))
+			assert.True(t, res.Allowed, "the next window starts over")
+		})
+	}
+}
+
+// TestSlidingLimit tests that a sliding limit still counts the previous window while it overlaps
+func TestSlidingLimit(t *testing.T) {
+	limit := Limit{Requests: 4, Window: time.Minute}
+	start := time.Date(2024, 10, 27, 14, 0, 0, 0, time.UTC)
+
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			for i := 0; i < 4; i++ {
+				res, err := store.Allow(ctx, "client", limit, start.Add(50*time.Second))
+				assert.NoError(t, err)
+				assert.True(t, res.Allowed)
+			}
+
+			// 15s into the next window three of the previous four requests still count
+			res, _ := store.Allow(ctx, "client", limit, start.Add(75*time.Second))
+			assert.Equal(t, Result{Allowed: true, Limit: 4, Remaining: 0, Reset: 45 * time.Second}, res)
+			res, _ = store.Allow(ctx, "client", limit, start.Add(75*time.Second))
+			assert.Equal(t, Result{Allowed: false, Limit: 4, Reset: 45 * time.Second}, res)
+
+			// 45s into the window only one of them counts
+			res, _ = store.Allow(ctx, "client", limit, start.Add(105*time.Second))
+			assert.Equal(t, Result{Allowed: true, Limit: 4, Remaining: 1, Reset: 15 * time.Second}, res)
+		})
+	}
+}
+
+// TestRefund tests that a refunded request no longer counts against its limit
+func TestRefund(t *testing.T) {
+	limit := Limit{Requests: 1, Window: time.Minute, Fixed: true}
+	start := time.Date(2024, 10, 27, 14, 0, 10, 0, time.UTC)
+
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			res, _ := store.Allow(ctx, "client", limit, start)
+			assert.True(t, res.Allowed)
+			assert.NoError(t, store.Refund(ctx, "client", limit, start))
+
+			res, _ = store.Allow(ctx, "client", limit, start.Add(time.Second))
+			assert.True(t, res.Allowed, "the refunded request is not counted")
+			res, _ = store.Allow(ctx, "client", limit, start.Add(2*time.Second))
+			assert.False(t, res.Allowed)
+
+			assert.NoError(t, store.Refund(ctx, "unknown", limit, start), "refunding an unknown key is a no-op")
+		})
+	}
+}
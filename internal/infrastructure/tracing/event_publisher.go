@@ -0,0 +1,39 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"order-service/internal/domain/services"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingEventPublisher wraps every Publish in a producer span named after the event type
+type TracingEventPublisher struct {
+	next   services.EventPublisher
+	tracer trace.Tracer
+}
+
+// NewTracingEventPublisher wraps next so each published event shows up in the trace of its request
+func NewTracingEventPublisher(next services.EventPublisher) *TracingEventPublisher {
+	return &TracingEventPublisher{next: next, tracer: otel.Tracer("order-service/events")}
+}
+
+func (p *TracingEventPublisher) Publish(ctx context.Context, event interface{}) error {
+	eventType := fmt.Sprintf("%T", event)
+	ctx, span := p.tracer.Start(ctx, "publish "+eventType,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(semconv.MessagingOperationTypePublish, attribute.String("event.type", eventType)))
+	defer span.End()
+
+	err := p.next.Publish(ctx, event)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"order-service/internal/domain/events"
+	"order-service/internal/domain/requestid"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// publisherFunc adapts a function to an EventPublisher
+type publisherFunc func(ctx context.Context, event interface{}) error
+
+func (f publisherFunc) Publish(ctx context.Context, event interface{}) error {
+	return f(ctx, event)
+}
+
+// TestTracingEventPublisher tests that every publish runs in a producer span tagged with the request ID
+func TestTracingEventPublisher(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(RequestIDProcessor{}), sdktrace.WithSpanProcessor(recorder)))
+
+	var inner trace.SpanContext
+	publisher := NewTracingEventPublisher(publisherFunc(func(ctx context.Context, event interface{}) error {
+		inner = trace.SpanContextFromContext(ctx)
+		if _, ok := event.(events.OrderDeletedEvent); ok {
+			return errors.New("broker unavailable")
+		}
+		return nil
+	}))
+
+	ctx := requestid.With(context.Background(), "req-1")
+	assert.NoError(t, publisher.Publish(ctx, events.OrderCancelledEvent{OrderID: 1}))
+	assert.Error(t, publisher.Publish(ctx, events.OrderDeletedEvent{OrderID: 1}))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "publish events.OrderCancelledEvent", spans[0].Name())
+	assert.Equal(t, trace.SpanKindProducer, spans[0].SpanKind())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("request.id", "req-1"))
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, spans[1].SpanContext(), inner, "the wrapped publisher runs inside the span")
+}
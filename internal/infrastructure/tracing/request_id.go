@@ -0,0 +1,22 @@
+package tracing
+
+import (
+	"context"
+	"order-service/internal/domain/requestid"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// RequestIDProcessor tags every span started while serving a request with the request's ID
+type RequestIDProcessor struct{}
+
+func (RequestIDProcessor) OnStart(ctx context.Context, span trace.ReadWriteSpan) {
+	if id := requestid.From(ctx); id != "" {
+		span.SetAttributes(attribute.String("request.id", id))
+	}
+}
+
+func (RequestIDProcessor) OnEnd(trace.ReadOnlySpan)         {}
+func (RequestIDProcessor) Shutdown(context.Context) error   { return nil }
+func (RequestIDProcessor) ForceFlush(context.Context) error { return nil }
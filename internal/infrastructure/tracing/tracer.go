@@ -2,33 +2,145 @@ package tracing
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
+// Exporters spans can be sent to
+const (
+	// ExporterNone records spans for propagation and log correlation but sends them nowhere
+	ExporterNone = "none"
+	// ExporterStdout writes spans to standard output, one JSON object per line
+	ExporterStdout = "stdout"
+	// ExporterOTLPGRPC sends spans to an OpenTelemetry collector over gRPC, by default on localhost:4317
+	ExporterOTLPGRPC = "otlp-grpc"
+	// ExporterOTLPHTTP sends spans to an OpenTelemetry collector over HTTP, by default on localhost:4318
+	ExporterOTLPHTTP = "otlp-http"
+)
+
+// DefaultServiceName names the service in every span when Config.ServiceName is empty
+const DefaultServiceName = "OrderService"
+
+// Config selects where spans are exported, how many of them are kept and which service they describe
+type Config struct {
+	// Exporter is one of the Exporter constants, ExporterNone when empty
+	Exporter string
+	// Endpoint is the collector's host:port, or a URL such as http://collector:4318. When empty the
+	// OTLP exporters read OTEL_EXPORTER_OTLP_ENDPOINT, then fall back to their default port on localhost.
+	Endpoint string
+	// Insecure sends spans to a host:port endpoint without TLS
+	Insecure bool
+	// SampleRatio is the share of new traces that are recorded, from 0 to 1. Traces continued from a
+	// caller follow the caller's decision.
+	SampleRatio float64
+
+	ServiceName string
+	Version     string
+	Environment string
+	InstanceID  string
+}
+
 var TracerProvider *trace.TracerProvider
 
-func InitTracer() error {
-	exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+// InitTracer installs the global tracer provider and the W3C trace context propagator
+func InitTracer(ctx context.Context, config Config) error {
+	exporter, err := newExporter(ctx, config)
+	if err != nil {
+		return err
+	}
+	res, err := newResource(ctx, config)
 	if err != nil {
 		return err
 	}
 
-	TracerProvider = trace.NewTracerProvider(
-		trace.WithBatcher(exporter),
-		trace.WithResource(resource.NewWithAttributes(
-			semconv.SchemaURL,
-			semconv.ServiceNameKey.String("OrderService"),
-		)),
-	)
+	options := []trace.TracerProviderOption{
+		trace.WithSampler(trace.ParentBased(trace.TraceIDRatioBased(config.SampleRatio))),
+		trace.WithResource(res),
+		trace.WithSpanProcessor(RequestIDProcessor{}),
+	}
+	if exporter != nil {
+		options = append(options, trace.WithBatcher(exporter))
+	}
+	TracerProvider = trace.NewTracerProvider(options...)
 	otel.SetTracerProvider(TracerProvider)
+	// Continue the W3C trace context and baggage of incoming requests
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
 	return nil
 }
 
+// newExporter returns nil for ExporterNone
+func newExporter(ctx context.Context, config Config) (trace.SpanExporter, error) {
+	url := strings.Contains(config.Endpoint, "://")
+	switch config.Exporter {
+	case "", ExporterNone:
+		return nil, nil
+	case ExporterStdout:
+		return stdouttrace.New()
+	case ExporterOTLPGRPC:
+		var options []otlptracegrpc.Option
+		switch {
+		case url:
+			options = append(options, otlptracegrpc.WithEndpointURL(config.Endpoint))
+		case config.Endpoint != "":
+			options = append(options, otlptracegrpc.WithEndpoint(config.Endpoint))
+		}
+		if config.Insecure && !url {
+			options = append(options, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(ctx, options...)
+	case ExporterOTLPHTTP:
+		var options []otlptracehttp.Option
+		switch {
+		case url:
+			options = append(options, otlptracehttp.WithEndpointURL(config.Endpoint))
+		case config.Endpoint != "":
+			options = append(options, otlptracehttp.WithEndpoint(config.Endpoint))
+		}
+		if config.Insecure && !url {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, options...)
+	}
+	return nil, fmt.Errorf("unknown trace exporter %q, use %s, %s, %s or %s",
+		config.Exporter, ExporterNone, ExporterStdout, ExporterOTLPGRPC, ExporterOTLPHTTP)
+}
+
+// newResource describes the service instance. OTEL_RESOURCE_ATTRIBUTES may add attributes, but
+// those set in config take precedence.
+func newResource(ctx context.Context, config Config) (*resource.Resource, error) {
+	serviceName := config.ServiceName
+	if serviceName == "" {
+		serviceName = DefaultServiceName
+	}
+	attributes := []attribute.KeyValue{semconv.ServiceNameKey.String(serviceName)}
+	if config.Version != "" {
+		attributes = append(attributes, semconv.ServiceVersionKey.String(config.Version))
+	}
+	if config.Environment != "" {
+		attributes = append(attributes, semconv.DeploymentEnvironmentKey.String(config.Environment))
+	}
+	if config.InstanceID != "" {
+		attributes = append(attributes, semconv.ServiceInstanceIDKey.String(config.InstanceID))
+	}
+
+	return resource.New(ctx,
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+		resource.WithAttributes(attributes...),
+	)
+}
+
 func ShutdownTracer(ctx context.Context) error {
 	if TracerProvider != nil {
 		return TracerProvider.Shutdown(ctx)
@@ -0,0 +1,136 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// collector is a stand-in for an OpenTelemetry collector that keeps every export request
+type collector struct {
+	coltracepb.UnimplementedTraceServiceServer
+	mu       sync.Mutex
+	requests []*coltracepb.ExportTraceServiceRequest
+}
+
+func (c *collector) Export(_ context.Context, request *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, request)
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+// ServeHTTP accepts OTLP over HTTP with protobuf bodies
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	request := &coltracepb.ExportTraceServiceRequest{}
+	if err != nil || r.URL.Path != "/v1/traces" || proto.Unmarshal(body, request) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	response, _ := c.Export(r.Context(), request)
+	encoded, _ := proto.Marshal(response)
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	_, _ = w.Write(encoded)
+}
+
+// exported returns the names of the exported spans and the attributes of their resource
+func (c *collector) exported() (spans []string, resource map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resource = map[string]string{}
+	for _, request := range c.requests {
+		for _, resourceSpans := range request.ResourceSpans {
+			for _, attr := range resourceSpans.Resource.Attributes {
+				resource[attr.Key] = attr.Value.GetStringValue()
+			}
+			for _, scopeSpans := range resourceSpans.ScopeSpans {
+				for _, span := range scopeSpans.Spans {
+					spans = append(spans, span.Name)
+				}
+			}
+		}
+	}
+	return spans, resource
+}
+
+// TestInitTracerOTLP tests that spans and the service's resource reach a collector over gRPC and HTTP
+func TestInitTracerOTLP(t *testing.T) {
+	grpcCollector := &collector{}
+	server := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(server, grpcCollector)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	httpCollector := &collector{}
+	httpServer := httptest.NewServer(httpCollector)
+	defer httpServer.Close()
+
+	tests := []struct {
+		name      string
+		config    Config
+		collector *collector
+	}{
+		{"grpc", Config{Exporter: ExporterOTLPGRPC, Endpoint: listener.Addr().String(), Insecure: true}, grpcCollector},
+		{"http url", Config{Exporter: ExporterOTLPHTTP, Endpoint: httpServer.URL}, httpCollector},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.SampleRatio = 1
+			tt.config.Version = "1.4.2"
+			tt.config.Environment = "staging"
+			tt.config.InstanceID = "orders-7f9c"
+			require.NoError(t, InitTracer(context.Background(), tt.config))
+
+			_, span := otel.Tracer("test").Start(context.Background(), "GET /v1/orders/:id")
+			span.End()
+			require.NoError(t, ShutdownTracer(context.Background()), "shutting down flushes the batch")
+
+			spans, resource := tt.collector.exported()
+			assert.Equal(t, []string{"GET /v1/orders/:id"}, spans)
+			assert.Equal(t, DefaultServiceName, resource["service.name"])
+			assert.Equal(t, "1.4.2", resource["service.version"])
+			assert.Equal(t, "staging", resource["deployment.environment"])
+			assert.Equal(t, "orders-7f9c", resource["service.instance.id"])
+		})
+	}
+}
+
+// TestInitTracerSampler tests that new traces are sampled by ratio and continued traces follow their caller
+func TestInitTracerSampler(t *testing.T) {
+	require.NoError(t, InitTracer(context.Background(), Config{Exporter: ExporterNone, SampleRatio: 0}))
+	defer ShutdownTracer(context.Background())
+	tracer := otel.Tracer("test")
+
+	_, root := tracer.Start(context.Background(), "root")
+	assert.False(t, root.SpanContext().IsSampled(), "no new trace is kept at ratio 0")
+	assert.True(t, root.SpanContext().IsValid(), "unsampled spans still carry IDs to propagate")
+
+	remote := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9},
+		SpanID:     trace.SpanID{0x00, 0xf0},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	_, child := tracer.Start(trace.ContextWithRemoteSpanContext(context.Background(), remote), "child")
+	assert.True(t, child.SpanContext().IsSampled(), "a caller's sampled trace is kept")
+}
+
+// TestInitTracerUnknownExporter tests that a misspelled exporter is reported instead of ignored
+func TestInitTracerUnknownExporter(t *testing.T) {
+	assert.ErrorContains(t, InitTracer(context.Background(), Config{Exporter: "jaeger"}), `unknown trace exporter "jaeger"`)
+}
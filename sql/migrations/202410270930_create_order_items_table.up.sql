@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS order_items (
+    id BIGSERIAL PRIMARY KEY,
+    order_id TEXT NOT NULL,
+    product_id BIGINT NOT NULL,
+    quantity BIGINT NOT NULL,
+    price NUMERIC NOT NULL,
+    CONSTRAINT fk_orders_order_items FOREIGN KEY (order_id) REFERENCES orders (order_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_items_order_id ON order_items (order_id);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_orders_search_vector;
+DROP TRIGGER IF EXISTS order_items_search_vector ON order_items;
+DROP TRIGGER IF EXISTS orders_search_vector ON orders;
+DROP FUNCTION IF EXISTS order_items_search_vector_trigger();
+DROP FUNCTION IF EXISTS orders_search_vector_trigger();
+DROP FUNCTION IF EXISTS order_search_document(TEXT, TEXT);
+ALTER TABLE orders DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE order_items DROP COLUMN IF EXISTS product_name;
+ALTER TABLE orders DROP COLUMN IF EXISTS customer_name;
@@ -0,0 +1,50 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS customer_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS product_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+
+-- The search document weights the order reference above the customer name above the items
+CREATE OR REPLACE FUNCTION order_search_document(p_order_id TEXT, p_customer_name TEXT)
+RETURNS TSVECTOR AS $$
+    SELECT setweight(to_tsvector('simple', coalesce(p_order_id, '')), 'A')
+        || setweight(to_tsvector('simple', coalesce(p_customer_name, '')), 'B')
+        || setweight(to_tsvector('simple', coalesce((
+            SELECT string_agg(i.product_name || ' ' || i.product_id::TEXT, ' ')
+            FROM order_items i
+            WHERE i.order_id = p_order_id
+        ), '')), 'C');
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION orders_search_vector_trigger() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector := order_search_document(NEW.order_id, NEW.customer_name);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION order_items_search_vector_trigger() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE orders SET search_vector = order_search_document(order_id, customer_name)
+        WHERE order_id = OLD.order_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE orders SET search_vector = order_search_document(order_id, customer_name)
+        WHERE order_id = NEW.order_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS orders_search_vector ON orders;
+CREATE TRIGGER orders_search_vector
+    BEFORE INSERT OR UPDATE OF order_id, customer_name ON orders
+    FOR EACH ROW EXECUTE FUNCTION orders_search_vector_trigger();
+
+DROP TRIGGER IF EXISTS order_items_search_vector ON order_items;
+CREATE TRIGGER order_items_search_vector
+    AFTER INSERT OR UPDATE OR DELETE ON order_items
+    FOR EACH ROW EXECUTE FUNCTION order_items_search_vector_trigger();
+
+UPDATE orders SET search_vector = order_search_document(order_id, customer_name);
+
+CREATE INDEX IF NOT EXISTS idx_orders_search_vector ON orders USING GIN (search_vector);
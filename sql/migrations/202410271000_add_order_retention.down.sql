@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS order_purge_audits;
+DROP INDEX IF EXISTS idx_orders_retention;
+ALTER TABLE orders DROP COLUMN IF EXISTS anonymized_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS legal_hold;
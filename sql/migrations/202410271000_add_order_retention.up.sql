@@ -0,0 +1,18 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS legal_hold BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMPTZ;
+
+-- Partial index over the rows the retention job scans
+CREATE INDEX IF NOT EXISTS idx_orders_retention ON orders (order_date, id)
+    WHERE legal_hold = FALSE AND anonymized_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS order_purge_audits (
+    id BIGSERIAL PRIMARY KEY,
+    order_id TEXT NOT NULL,
+    action TEXT NOT NULL,
+    order_date TIMESTAMPTZ NOT NULL,
+    item_count BIGINT NOT NULL,
+    total_amount NUMERIC NOT NULL,
+    purged_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_purge_audits_order_id ON order_purge_audits (order_id);
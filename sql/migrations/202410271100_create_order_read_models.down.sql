@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS customer_order_histories;
+DROP TABLE IF EXISTS order_summaries;
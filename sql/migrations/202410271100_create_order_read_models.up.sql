@@ -0,0 +1,46 @@
+-- Query-side tables maintained by the order projections, never written by commands
+CREATE TABLE IF NOT EXISTS order_summaries (
+    id BIGINT PRIMARY KEY,
+    order_id TEXT NOT NULL,
+    customer_id BIGINT NOT NULL,
+    customer_name TEXT NOT NULL DEFAULT '',
+    item_count BIGINT NOT NULL,
+    total_amount NUMERIC NOT NULL,
+    items JSONB NOT NULL DEFAULT '[]',
+    order_date TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_summaries_order_id ON order_summaries (order_id);
+
+CREATE TABLE IF NOT EXISTS customer_order_histories (
+    customer_id BIGINT NOT NULL,
+    order_id TEXT NOT NULL,
+    item_count BIGINT NOT NULL,
+    total_amount NUMERIC NOT NULL,
+    order_date TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (customer_id, order_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_order_histories_order_date ON customer_order_histories (customer_id, order_date DESC);
+
+-- Backfill from the write model, equivalent to running `projections rebuild`
+INSERT INTO order_summaries (id, order_id, customer_id, customer_name, item_count, total_amount, items, order_date)
+SELECT o.id, o.order_id, o.customer_id, o.customer_name, count(i.id), o.total_amount,
+       coalesce(jsonb_agg(jsonb_build_object(
+           'product_id', i.product_id,
+           'product_name', i.product_name,
+           'quantity', i.quantity,
+           'price', i.price
+       ) ORDER BY i.id) FILTER (WHERE i.id IS NOT NULL), '[]'),
+       o.order_date
+FROM orders o
+LEFT JOIN order_items i ON i.order_id = o.order_id
+GROUP BY o.id
+ON CONFLICT DO NOTHING;
+
+INSERT INTO customer_order_histories (customer_id, order_id, item_count, total_amount, order_date)
+SELECT customer_id, order_id, item_count, total_amount, order_date
+FROM order_summaries
+WHERE customer_id <> 0
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,2 @@
+ALTER TABLE order_summaries DROP COLUMN IF EXISTS status;
+ALTER TABLE orders DROP COLUMN IF EXISTS status;
@@ -0,0 +1,2 @@
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'pending';
+ALTER TABLE order_summaries ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'pending';
@@ -0,0 +1,86 @@
+-- Copy the live partitions back into plain tables; archived partitions are left where they are
+DROP TRIGGER IF EXISTS order_items_search_vector ON order_items;
+DROP TRIGGER IF EXISTS orders_search_vector ON orders;
+DROP TRIGGER IF EXISTS order_keys ON orders;
+DROP TRIGGER IF EXISTS order_keys_update ON orders;
+DROP FUNCTION IF EXISTS order_keys_trigger();
+DROP FUNCTION IF EXISTS archive_order_partitions(DATE, TEXT, TEXT);
+DROP FUNCTION IF EXISTS create_order_partitions(DATE);
+
+ALTER TABLE orders RENAME TO orders_partitioned;
+ALTER TABLE order_items RENAME TO order_items_partitioned;
+ALTER SEQUENCE orders_id_seq OWNED BY NONE;
+ALTER SEQUENCE order_items_id_seq OWNED BY NONE;
+DROP INDEX IF EXISTS idx_order_items_order_id;
+DROP INDEX IF EXISTS idx_orders_search_vector;
+DROP INDEX IF EXISTS idx_orders_retention;
+
+CREATE TABLE orders (
+    id BIGINT PRIMARY KEY DEFAULT nextval('orders_id_seq'),
+    order_id TEXT NOT NULL,
+    customer_id BIGINT NOT NULL,
+    total_amount NUMERIC NOT NULL DEFAULT 0,
+    order_date TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    customer_name TEXT NOT NULL DEFAULT '',
+    search_vector TSVECTOR,
+    legal_hold BOOLEAN NOT NULL DEFAULT FALSE,
+    anonymized_at TIMESTAMPTZ,
+    status TEXT NOT NULL DEFAULT 'pending'
+);
+
+CREATE TABLE order_items (
+    id BIGINT PRIMARY KEY DEFAULT nextval('order_items_id_seq'),
+    order_id TEXT NOT NULL,
+    product_id BIGINT NOT NULL,
+    quantity BIGINT NOT NULL,
+    price NUMERIC NOT NULL,
+    product_name TEXT NOT NULL DEFAULT ''
+);
+
+ALTER SEQUENCE orders_id_seq OWNED BY orders.id;
+ALTER SEQUENCE order_items_id_seq OWNED BY order_items.id;
+
+INSERT INTO orders (id, order_id, customer_id, total_amount, order_date, created_at, updated_at,
+                    customer_name, search_vector, legal_hold, anonymized_at, status)
+SELECT id, order_id, customer_id, total_amount, order_date, created_at, updated_at,
+       customer_name, search_vector, legal_hold, anonymized_at, status
+FROM orders_partitioned;
+
+INSERT INTO order_items (id, order_id, product_id, quantity, price, product_name)
+SELECT id, order_id, product_id, quantity, price, product_name
+FROM order_items_partitioned;
+
+DROP TABLE order_items_partitioned;
+DROP TABLE orders_partitioned;
+DROP TABLE IF EXISTS order_keys;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_order_id ON orders (order_id);
+CREATE INDEX IF NOT EXISTS idx_order_items_order_id ON order_items (order_id);
+ALTER TABLE order_items ADD CONSTRAINT fk_orders_order_items FOREIGN KEY (order_id) REFERENCES orders (order_id);
+CREATE INDEX IF NOT EXISTS idx_orders_search_vector ON orders USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_orders_retention ON orders (order_date, id)
+    WHERE legal_hold = FALSE AND anonymized_at IS NULL;
+
+CREATE OR REPLACE FUNCTION order_items_search_vector_trigger() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE orders SET search_vector = order_search_document(order_id, customer_name)
+        WHERE order_id = OLD.order_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE orders SET search_vector = order_search_document(order_id, customer_name)
+        WHERE order_id = NEW.order_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER orders_search_vector
+    BEFORE INSERT OR UPDATE OF order_id, customer_name ON orders
+    FOR EACH ROW EXECUTE FUNCTION orders_search_vector_trigger();
+
+CREATE TRIGGER order_items_search_vector
+    AFTER INSERT OR UPDATE OR DELETE ON order_items
+    FOR EACH ROW EXECUTE FUNCTION order_items_search_vector_trigger();
@@ -0,0 +1,181 @@
+-- Range partition orders and order_items by the UTC month of order_date.
+-- Postgres only enforces uniqueness on partitioned tables for keys containing the partition key,
+-- so order_keys keeps order_id globally unique and maps id and order_id to the order's partition.
+CREATE TABLE IF NOT EXISTS order_keys (
+    order_id TEXT PRIMARY KEY,
+    id BIGINT NOT NULL,
+    order_date TIMESTAMPTZ NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_keys_id ON order_keys (id);
+
+-- Move the unpartitioned tables out of the way, keeping their id sequences
+DROP TRIGGER IF EXISTS order_items_search_vector ON order_items;
+DROP TRIGGER IF EXISTS orders_search_vector ON orders;
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS fk_orders_order_items;
+DROP INDEX IF EXISTS idx_order_items_order_id;
+DROP INDEX IF EXISTS idx_orders_order_id;
+DROP INDEX IF EXISTS idx_orders_search_vector;
+DROP INDEX IF EXISTS idx_orders_retention;
+ALTER TABLE orders RENAME CONSTRAINT orders_pkey TO orders_unpartitioned_pkey;
+ALTER TABLE order_items RENAME CONSTRAINT order_items_pkey TO order_items_unpartitioned_pkey;
+ALTER TABLE orders RENAME TO orders_unpartitioned;
+ALTER TABLE order_items RENAME TO order_items_unpartitioned;
+ALTER SEQUENCE orders_id_seq OWNED BY NONE;
+ALTER SEQUENCE order_items_id_seq OWNED BY NONE;
+
+CREATE TABLE orders (
+    id BIGINT NOT NULL DEFAULT nextval('orders_id_seq'),
+    order_id TEXT NOT NULL,
+    customer_id BIGINT NOT NULL,
+    customer_name TEXT NOT NULL DEFAULT '',
+    total_amount NUMERIC NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'pending',
+    order_date TIMESTAMPTZ NOT NULL,
+    legal_hold BOOLEAN NOT NULL DEFAULT FALSE,
+    anonymized_at TIMESTAMPTZ,
+    search_vector TSVECTOR,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id, order_date),
+    CONSTRAINT uq_orders_order_id UNIQUE (order_id, order_date)
+) PARTITION BY RANGE (order_date);
+
+CREATE TABLE order_items (
+    id BIGINT NOT NULL DEFAULT nextval('order_items_id_seq'),
+    order_id TEXT NOT NULL,
+    order_date TIMESTAMPTZ NOT NULL,
+    product_id BIGINT NOT NULL,
+    product_name TEXT NOT NULL DEFAULT '',
+    quantity BIGINT NOT NULL,
+    price NUMERIC NOT NULL,
+    PRIMARY KEY (id, order_date),
+    CONSTRAINT fk_orders_order_items FOREIGN KEY (order_id, order_date) REFERENCES orders (order_id, order_date)
+) PARTITION BY RANGE (order_date);
+
+ALTER SEQUENCE orders_id_seq OWNED BY orders.id;
+ALTER SEQUENCE order_items_id_seq OWNED BY order_items.id;
+
+CREATE INDEX IF NOT EXISTS idx_order_items_order_id ON order_items (order_id, order_date);
+CREATE INDEX IF NOT EXISTS idx_orders_search_vector ON orders USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_orders_retention ON orders (order_date, id)
+    WHERE legal_hold = FALSE AND anonymized_at IS NULL;
+
+-- Rows outside every monthly partition land here until a partition for their month exists.
+-- A month whose rows are already in the default partition cannot be created, so create months ahead.
+CREATE TABLE IF NOT EXISTS orders_default PARTITION OF orders DEFAULT;
+CREATE TABLE IF NOT EXISTS order_items_default PARTITION OF order_items DEFAULT;
+
+-- create_order_partitions creates the orders and order_items partitions for the month of p_month
+CREATE OR REPLACE FUNCTION create_order_partitions(p_month DATE) RETURNS VOID AS $$
+DECLARE
+    v_start TIMESTAMPTZ := date_trunc('month', p_month)::TIMESTAMP AT TIME ZONE 'UTC';
+    v_end TIMESTAMPTZ := (date_trunc('month', p_month) + INTERVAL '1 month')::TIMESTAMP AT TIME ZONE 'UTC';
+    v_suffix TEXT := to_char(p_month, 'YYYYMM');
+BEGIN
+    EXECUTE format('CREATE TABLE IF NOT EXISTS %I PARTITION OF orders FOR VALUES FROM (%L) TO (%L)',
+        'orders_p' || v_suffix, v_start, v_end);
+    EXECUTE format('CREATE TABLE IF NOT EXISTS %I PARTITION OF order_items FOR VALUES FROM (%L) TO (%L)',
+        'order_items_p' || v_suffix, v_start, v_end);
+END;
+$$ LANGUAGE plpgsql;
+
+-- archive_order_partitions detaches the partitions for the month of p_month and moves them into
+-- p_schema, and into p_tablespace when one is given. The items are detached first because they
+-- reference the orders partition.
+CREATE OR REPLACE FUNCTION archive_order_partitions(p_month DATE, p_schema TEXT, p_tablespace TEXT) RETURNS VOID AS $$
+DECLARE
+    v_suffix TEXT := to_char(p_month, 'YYYYMM');
+    v_table TEXT;
+    v_constraint TEXT;
+BEGIN
+    EXECUTE format('CREATE SCHEMA IF NOT EXISTS %I', p_schema);
+    FOREACH v_table IN ARRAY ARRAY['order_items_p' || v_suffix, 'orders_p' || v_suffix] LOOP
+        IF to_regclass(quote_ident(v_table)) IS NULL THEN
+            CONTINUE;
+        END IF;
+        EXECUTE format('ALTER TABLE %I DETACH PARTITION %I', split_part(v_table, '_p', 1), v_table);
+        -- Archived items keep no foreign key to the live orders table
+        FOR v_constraint IN
+            SELECT conname FROM pg_constraint WHERE conrelid = to_regclass(quote_ident(v_table)) AND contype = 'f'
+        LOOP
+            EXECUTE format('ALTER TABLE %I DROP CONSTRAINT %I', v_table, v_constraint);
+        END LOOP;
+        EXECUTE format('ALTER TABLE %I SET SCHEMA %I', v_table, p_schema);
+        IF coalesce(p_tablespace, '') <> '' THEN
+            EXECUTE format('ALTER TABLE %I.%I SET TABLESPACE %I', p_schema, v_table, p_tablespace);
+        END IF;
+    END LOOP;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Partitions for every month holding orders and the next three months
+SELECT create_order_partitions(month::DATE)
+FROM generate_series(
+    date_trunc('month', coalesce((SELECT min(order_date) FROM orders_unpartitioned), now()) AT TIME ZONE 'UTC'),
+    date_trunc('month', now() AT TIME ZONE 'UTC') + INTERVAL '3 months',
+    INTERVAL '1 month'
+) AS month;
+
+-- order_keys follows inserts, deletes and key changes; archived orders keep their keys so
+-- their order_id is never reused
+CREATE OR REPLACE FUNCTION order_keys_trigger() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO order_keys (order_id, id, order_date) VALUES (NEW.order_id, NEW.id, NEW.order_date);
+    ELSIF TG_OP = 'UPDATE' THEN
+        UPDATE order_keys SET order_id = NEW.order_id, id = NEW.id, order_date = NEW.order_date
+        WHERE order_id = OLD.order_id;
+    ELSE
+        DELETE FROM order_keys WHERE order_id = OLD.order_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER order_keys
+    AFTER INSERT OR DELETE ON orders
+    FOR EACH ROW EXECUTE FUNCTION order_keys_trigger();
+
+CREATE TRIGGER order_keys_update
+    AFTER UPDATE ON orders
+    FOR EACH ROW
+    WHEN (OLD.id <> NEW.id OR OLD.order_id <> NEW.order_id OR OLD.order_date <> NEW.order_date)
+    EXECUTE FUNCTION order_keys_trigger();
+
+INSERT INTO orders (id, order_id, customer_id, customer_name, total_amount, status, order_date,
+                    legal_hold, anonymized_at, search_vector, created_at, updated_at)
+SELECT id, order_id, customer_id, customer_name, total_amount, status, order_date,
+       legal_hold, anonymized_at, search_vector, created_at, updated_at
+FROM orders_unpartitioned;
+
+INSERT INTO order_items (id, order_id, order_date, product_id, product_name, quantity, price)
+SELECT i.id, i.order_id, o.order_date, i.product_id, i.product_name, i.quantity, i.price
+FROM order_items_unpartitioned i
+JOIN orders_unpartitioned o ON o.order_id = i.order_id;
+
+DROP TABLE order_items_unpartitioned;
+DROP TABLE orders_unpartitioned;
+
+-- Search triggers as before, with item changes only touching the order's own partition
+CREATE OR REPLACE FUNCTION order_items_search_vector_trigger() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE orders SET search_vector = order_search_document(order_id, customer_name)
+        WHERE order_id = OLD.order_id AND order_date = OLD.order_date;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE orders SET search_vector = order_search_document(order_id, customer_name)
+        WHERE order_id = NEW.order_id AND order_date = NEW.order_date;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER orders_search_vector
+    BEFORE INSERT OR UPDATE OF order_id, customer_name ON orders
+    FOR EACH ROW EXECUTE FUNCTION orders_search_vector_trigger();
+
+CREATE TRIGGER order_items_search_vector
+    AFTER INSERT OR UPDATE OR DELETE ON order_items
+    FOR EACH ROW EXECUTE FUNCTION order_items_search_vector_trigger();
@@ -0,0 +1,2 @@
+-- Optimistic concurrency: every save of an existing order bumps its version
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+-- Soft delete: deleted orders keep their rows and are filtered out of every read
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
@@ -0,0 +1 @@
+ALTER TABLE order_summaries DROP COLUMN IF EXISTS version;
@@ -0,0 +1,5 @@
+-- Conditional requests: the read model tracks the order version that backs its ETag
+ALTER TABLE order_summaries ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+UPDATE order_summaries SET version = orders.version, updated_at = orders.updated_at
+FROM orders WHERE orders.id = order_summaries.id;